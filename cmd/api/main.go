@@ -16,12 +16,17 @@ import (
 	"unipile-connector/internal/adapter/handler"
 	"unipile-connector/internal/adapter/middleware"
 	"unipile-connector/internal/adapter/repository/postgres"
+	"unipile-connector/internal/domain/entity"
 	"unipile-connector/internal/domain/service"
 	"unipile-connector/internal/infrastructure/client"
 	"unipile-connector/internal/infrastructure/config"
 	"unipile-connector/internal/infrastructure/database"
 	"unipile-connector/internal/infrastructure/server"
+	"unipile-connector/internal/infrastructure/worker"
 	"unipile-connector/internal/usecase/account"
+	"unipile-connector/internal/usecase/invitation"
+	"unipile-connector/internal/usecase/messaging"
+	"unipile-connector/internal/usecase/profile"
 	"unipile-connector/internal/usecase/user"
 	"unipile-connector/pkg/logger"
 )
@@ -70,10 +75,14 @@ func main() {
 	}
 
 	// Initialize Unipile client
-	unipileClient := client.NewUnipileClient(cfg.Unipile.BaseURL, cfg.Unipile.APIKey)
+	unipileClient := client.NewUnipileClient(cfg.Unipile.BaseURL, cfg.Unipile.APIKey, client.RetryPolicy{
+		MaxRetries: cfg.Unipile.MaxRetries,
+		BaseDelay:  cfg.Unipile.RetryBaseDelay,
+		MaxDelay:   cfg.Unipile.RetryMaxDelay,
+	}, log)
 
 	// Test Unipile connection
-	if err := unipileClient.TestConnection(); err != nil {
+	if err := unipileClient.TestConnection(context.Background()); err != nil {
 		log.Warnf("Failed to connect to Unipile API: %v", err)
 	}
 
@@ -91,19 +100,63 @@ func main() {
 	}
 	rateLimiter := limiter.New(memory.NewStore(), rate, limiter.WithTrustForwardHeader(true))
 	rateLimitMiddleware := mgin.NewMiddleware(rateLimiter)
-	middlewares := middleware.NewMiddlewares(corsMiddleware, jwtMiddleware, rateLimitMiddleware)
+	if cfg.Unipile.WebhookSecret == "" {
+		log.Warn("UNIPILE_WEBHOOK_SECRET is not set, Unipile webhooks will be rejected")
+	}
+	webhookAuthMiddleware := middleware.WebhookAuthMiddleware(cfg.Unipile.WebhookSecret)
+	if cfg.Unipile.HostedAuthSecret == "" {
+		log.Warn("UNIPILE_HOSTED_AUTH_SECRET is not set, hosted auth links cannot be created")
+	}
+	hostedAuthStateService := service.NewHostedAuthStateService(cfg.Unipile.HostedAuthSecret)
+	middlewares := middleware.NewMiddlewares(corsMiddleware, jwtMiddleware, rateLimitMiddleware, webhookAuthMiddleware)
 
 	// Initialize repositories
 	repos := postgres.GetRepositories(db)
 
 	// Initialize use cases
 	userUsecase := user.NewUserUsecase(repos.User, jwtService, log)
-	accountUsecase := account.NewAccountUsecase(repos.Tx, repos.Account, unipileClient, log)
+	accountEventHub := service.NewAccountEventHub()
+	checkpointTTLs := make(map[entity.CheckpointType]time.Duration, len(cfg.Checkpoint.TTLs))
+	for checkpointType, ttl := range cfg.Checkpoint.TTLs {
+		checkpointTTLs[entity.CheckpointType(checkpointType)] = ttl
+	}
+	accountUsecase := account.NewAccountUsecase(repos.Tx, repos.Account, unipileClient, accountEventHub, account.CheckpointConfig{
+		TTL:                   cfg.Checkpoint.TTL,
+		TTLs:                  checkpointTTLs,
+		DeleteExpiredAccounts: cfg.Checkpoint.DeleteExpiredAccounts,
+		MaxAttempts:           cfg.Checkpoint.MaxAttempts,
+	}, hostedAuthStateService, account.HostedAuthConfig{
+		NotifyURL:          cfg.Server.PublicURL + "/api/v1/webhooks/unipile/hosted-auth",
+		SuccessRedirectURL: cfg.Server.PublicURL + "/dashboard",
+		FailureRedirectURL: cfg.Server.PublicURL + "/dashboard",
+		LinkTTL:            cfg.Unipile.HostedAuthLinkTTL,
+	}, log)
+
+	messagingUsecase := messaging.NewMessagingUsecase(repos.Tx, repos.Account, repos.Chat, repos.Message, unipileClient, log)
+	profileUsecase := profile.NewProfileUsecase(repos.Account, repos.Profile, unipileClient, cfg.Unipile.ProfileCacheTTL, log)
+	invitationUsecase := invitation.NewInvitationUsecase(repos.Tx, repos.Account, repos.Invitation, unipileClient, log)
+
+	// Start account reconciliation worker
+	reconcileWorker := worker.NewPeriodicWorker("account-reconciliation", cfg.Unipile.ReconcileInterval, accountUsecase.ReconcileAccounts, log)
+	reconcileWorker.Start(context.Background())
+
+	// Start checkpoint expiry worker
+	checkpointExpiryWorker := worker.NewPeriodicWorker("checkpoint-expiry", cfg.Checkpoint.SweepInterval, accountUsecase.ExpireCheckpoints, log)
+	checkpointExpiryWorker.Start(context.Background())
+
+	// Start chat sync worker, it backfills the history of new accounts and catches up on missed webhook events
+	chatSyncWorker := worker.NewPeriodicWorker("chat-sync", cfg.Unipile.ChatSyncInterval, messagingUsecase.SyncChats, log)
+	chatSyncWorker.Start(context.Background())
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(userUsecase)
 	accountHandler := handler.NewAccountHandler(accountUsecase)
-	handlers := handler.NewHandlers(authHandler, accountHandler)
+	webhookHandler := handler.NewWebhookHandler(accountUsecase, messagingUsecase, invitationUsecase)
+	eventHandler := handler.NewEventHandler(accountEventHub, jwtService)
+	messagingHandler := handler.NewMessagingHandler(messagingUsecase)
+	profileHandler := handler.NewProfileHandler(profileUsecase)
+	invitationHandler := handler.NewInvitationHandler(invitationUsecase)
+	handlers := handler.NewHandlers(authHandler, accountHandler, webhookHandler, eventHandler, messagingHandler, profileHandler, invitationHandler)
 
 	// Initialize server
 	srv := server.NewServer(middlewares, handlers)
@@ -131,6 +184,14 @@ func main() {
 	// Stop blacklist cleanup goroutine
 	blacklistService.StopCleanup()
 
+	// Stop background workers
+	reconcileWorker.Stop()
+	checkpointExpiryWorker.Stop()
+	chatSyncWorker.Stop()
+
+	// Close event streams, they would otherwise keep the server from shutting down
+	accountEventHub.Close()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
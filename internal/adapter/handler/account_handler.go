@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"encoding/base64"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
 
 	"unipile-connector/internal/domain/errs"
 	"unipile-connector/internal/usecase/account"
@@ -14,10 +16,16 @@ import (
 // AccountHandler handles account-related requests
 type AccountHandler interface {
 	ListUserAccounts(c *gin.Context)
+	DisconnectAccount(c *gin.Context)
 	DisconnectLinkedIn(c *gin.Context)
-	ConnectLinkedIn(c *gin.Context)
+	ListProviders(c *gin.Context)
+	ConnectAccount(c *gin.Context)
 	SolveCheckpoint(c *gin.Context)
 	WaitForAccountValidation(c *gin.Context)
+	ReconnectAccount(c *gin.Context)
+	ResendCheckpoint(c *gin.Context)
+	GetQRCode(c *gin.Context)
+	CreateHostedAuthLink(c *gin.Context)
 }
 
 // AccountHandlerImpl handles account-related requests
@@ -32,23 +40,9 @@ func NewAccountHandler(accountUsecase account.Usecase) AccountHandler {
 	}
 }
 
-func (h *AccountHandlerImpl) userIDFromContext(c *gin.Context) (uint, error) {
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
-		return 0, errs.ErrUserNotAuthenticated
-	}
-
-	userID, ok := userIDStr.(uint)
-	if !ok {
-		return 0, errs.ErrInvalidUserID
-	}
-
-	return userID, nil
-}
-
 // ListUserAccounts retrieves all accounts for the current user
 func (h *AccountHandlerImpl) ListUserAccounts(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+	userID, err := userIDFromContext(c)
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -65,88 +59,155 @@ func (h *AccountHandlerImpl) ListUserAccounts(c *gin.Context) {
 	})
 }
 
-// DisconnectLinkedInRequest represents request to disconnect a LinkedIn account
-type DisconnectLinkedInRequest struct {
+// DisconnectAccountRequest represents request to disconnect an account
+type DisconnectAccountRequest struct {
 	AccountID string `json:"account_id" binding:"required"`
 }
 
-// DisconnectLinkedIn disconnects LinkedIn account for the current user
-func (h *AccountHandlerImpl) DisconnectLinkedIn(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+// DisconnectAccount disconnects an account of the path provider for the current user
+func (h *AccountHandlerImpl) DisconnectAccount(c *gin.Context) {
+	userID, err := userIDFromContext(c)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
 
-	var req DisconnectLinkedInRequest
+	provider, err := h.providerFromPath(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var req DisconnectAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		RespondError(c, errs.WrapValidationError(err, "Invalid request data"))
 		return
 	}
 
-	err = h.accountUsecase.DisconnectLinkedIn(c.Request.Context(), userID, req.AccountID)
+	err = h.accountUsecase.DisconnectAccount(c.Request.Context(), userID, provider.Name, req.AccountID)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
 
-	RespondSuccess(c, http.StatusOK, "LinkedIn account disconnected successfully", nil)
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" account disconnected successfully", nil)
+}
+
+// DisconnectLinkedIn serves the former DELETE /accounts/linkedin route as the disconnect route of provider "linkedin"
+func (h *AccountHandlerImpl) DisconnectLinkedIn(c *gin.Context) {
+	c.Params = append(c.Params, gin.Param{Key: "id", Value: "linkedin"})
+	h.DisconnectAccount(c)
+}
+
+// ListProviders lists the providers accounts can be connected with
+func (h *AccountHandlerImpl) ListProviders(c *gin.Context) {
+	RespondSuccess(c, http.StatusOK, "Providers retrieved successfully", gin.H{
+		"providers": h.accountUsecase.ListProviders(),
+	})
 }
 
-// ConnectLinkedInRequest represents LinkedIn connection request
-type ConnectLinkedInRequest struct {
-	Type        string `json:"type" binding:"required"` // "credentials" or "cookie"
+// providerFromPath returns the provider named by the :id path parameter of provider routes
+func (h *AccountHandlerImpl) providerFromPath(c *gin.Context) (*account.Provider, error) {
+	return h.accountUsecase.GetProvider(c.Param("id"))
+}
+
+// ConnectAccountRequest represents account connection request
+type ConnectAccountRequest struct {
+	Type        string `json:"type"` // Auth method of the provider, e.g. "credentials" or "cookie"
 	Username    string `json:"username,omitempty"`
 	Password    string `json:"password,omitempty"`
 	AccessToken string `json:"access_token,omitempty"`
 	UserAgent   string `json:"user_agent,omitempty"`
 }
 
-// ConnectLinkedIn handles LinkedIn account connection
-func (h *AccountHandlerImpl) ConnectLinkedIn(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+func (r *ConnectAccountRequest) usecaseRequest(provider *account.Provider) *account.ConnectAccountRequest {
+	return &account.ConnectAccountRequest{
+		Provider:    provider.Name,
+		Type:        r.Type,
+		Username:    r.Username,
+		Password:    r.Password,
+		AccessToken: r.AccessToken,
+		UserAgent:   r.UserAgent,
+	}
+}
+
+// ConnectAccount handles connecting an account of the path provider
+func (h *AccountHandlerImpl) ConnectAccount(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
 
-	var req ConnectLinkedInRequest
+	var req ConnectAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		RespondError(c, errs.WrapValidationError(err, "Invalid request data"))
 		return
 	}
 
-	// Validate request based on type
-	if req.Type == "credentials" {
-		if req.Username == "" || req.Password == "" {
-			RespondError(c, errs.WrapValidationError(errors.New("username and password required for credentials type"), "Username and password required for credentials type"))
-			return
-		}
-	} else if req.Type == "cookie" {
-		if req.AccessToken == "" {
-			RespondError(c, errs.WrapValidationError(errors.New("access token required for cookie type"), "Access token required for cookie type"))
-			return
-		}
-	} else {
-		RespondError(c, errs.WrapValidationError(errors.New("type must be 'credentials' or 'cookie"), "Type must be 'credentials' or 'cookie'"))
+	connectReq := req.usecaseRequest(provider)
+	if err := provider.ValidateAuth(connectReq); err != nil {
+		RespondError(c, err)
 		return
 	}
 
-	connectReq := &account.ConnectLinkedInRequest{
-		Username:    req.Username,
-		Password:    req.Password,
-		AccessToken: req.AccessToken,
-		UserAgent:   req.UserAgent,
+	// Store account in database
+	entityAccount, err := h.accountUsecase.ConnectAccount(c.Request.Context(), userID, connectReq)
+	if err != nil {
+		RespondError(c, err)
+		return
 	}
 
-	// Store account in database
-	entityAccount, err := h.accountUsecase.ConnectLinkedInAccount(c.Request.Context(), userID, connectReq)
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" account connected successfully", gin.H{
+		"account": entityAccount,
+	})
+}
+
+// ReconnectAccountRequest represents request to reconnect a disconnected account
+type ReconnectAccountRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+	ConnectAccountRequest
+}
+
+// ReconnectAccount handles reconnecting an account in CREDENTIALS, ERROR or STOPPED state
+func (h *AccountHandlerImpl) ReconnectAccount(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var req ReconnectAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid request data"))
+		return
+	}
+
+	connectReq := req.usecaseRequest(provider)
+	if err := provider.ValidateAuth(connectReq); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	entityAccount, err := h.accountUsecase.ReconnectAccount(c.Request.Context(), userID, req.AccountID, connectReq)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
 
-	RespondSuccess(c, http.StatusOK, "LinkedIn account connected successfully", gin.H{
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" account reconnected successfully", gin.H{
 		"account": entityAccount,
 	})
 }
@@ -157,9 +218,15 @@ type SolveCheckpointRequest struct {
 	Code      string `json:"code" binding:"required"`
 }
 
-// SolveCheckpoint handles LinkedIn checkpoint solving
+// SolveCheckpoint handles checkpoint solving
 func (h *AccountHandlerImpl) SolveCheckpoint(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -172,6 +239,7 @@ func (h *AccountHandlerImpl) SolveCheckpoint(c *gin.Context) {
 	}
 
 	solveReq := &account.SolveCheckpointRequest{
+		Provider:  provider.Name,
 		AccountID: req.AccountID,
 		Code:      req.Code,
 	}
@@ -186,7 +254,47 @@ func (h *AccountHandlerImpl) SolveCheckpoint(c *gin.Context) {
 		return
 	}
 
-	RespondSuccess(c, http.StatusOK, "LinkedIn checkpoint solved successfully", gin.H{
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" checkpoint solved successfully", gin.H{
+		"account": entityAccount,
+	})
+}
+
+// ResendCheckpointRequest represents request to send a new checkpoint code
+type ResendCheckpointRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+}
+
+// ResendCheckpoint handles requesting a new checkpoint code
+func (h *AccountHandlerImpl) ResendCheckpoint(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var req ResendCheckpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid request data"))
+		return
+	}
+
+	entityAccount, err := h.accountUsecase.ResendCheckpoint(c.Request.Context(), userID, provider.Name, req.AccountID)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCodeOrExpiredCheckpoint) {
+			RespondUnauthorized(c, err)
+			return
+		}
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" checkpoint code sent successfully", gin.H{
 		"account": entityAccount,
 	})
 }
@@ -197,9 +305,15 @@ type WaitForAccountValidationRequest struct {
 	Timeout   int    `json:"timeout" binding:"required"`
 }
 
-// WaitForAccountValidation handles LinkedIn account validation
+// WaitForAccountValidation handles account validation
 func (h *AccountHandlerImpl) WaitForAccountValidation(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -211,13 +325,112 @@ func (h *AccountHandlerImpl) WaitForAccountValidation(c *gin.Context) {
 		return
 	}
 
-	entityAccount, err := h.accountUsecase.WaitForAccountValidation(c.Request.Context(), userID, req.AccountID, time.Duration(req.Timeout)*time.Second)
+	entityAccount, err := h.accountUsecase.WaitForAccountValidation(c.Request.Context(), userID, provider.Name, req.AccountID, time.Duration(req.Timeout)*time.Second)
 	if err != nil {
 		RespondError(c, err)
 		return
 	}
 
-	RespondSuccess(c, http.StatusOK, "LinkedIn account validated successfully", gin.H{
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" account validated successfully", gin.H{
 		"account": entityAccount,
 	})
 }
+
+// QR code formats served by GetQRCode
+const (
+	qrCodeFormatPNG     = "png"
+	qrCodeFormatDataURI = "data_uri"
+)
+
+// qrCodeSize is the width and height of the rendered QR code in pixels
+const qrCodeSize = 256
+
+// GetQRCode serves the current QR code of a QRCODE checkpoint as a PNG image, or as a data URI with format=data_uri
+func (h *AccountHandlerImpl) GetQRCode(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	provider, err := h.providerFromPath(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	accountID := c.Query("account_id")
+	if accountID == "" {
+		RespondError(c, errs.WrapValidationError(errors.New("account_id is required"), "Account ID is required"))
+		return
+	}
+	format := c.DefaultQuery("format", qrCodeFormatPNG)
+	if format != qrCodeFormatPNG && format != qrCodeFormatDataURI {
+		RespondError(c, errs.WrapValidationError(errors.New("format must be 'png' or 'data_uri'"), "Format must be 'png' or 'data_uri'"))
+		return
+	}
+
+	qrCode, err := h.accountUsecase.GetQRCode(c.Request.Context(), userID, provider.Name, accountID)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCodeOrExpiredCheckpoint) {
+			RespondUnauthorized(c, err)
+			return
+		}
+		RespondError(c, err)
+		return
+	}
+
+	image, err := qrcode.Encode(qrCode.Code, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		RespondError(c, errs.WrapInternalError(err, "Failed to render QR code"))
+		return
+	}
+
+	// The code rotates, never serve a cached one
+	c.Header("Cache-Control", "no-store")
+	if format == qrCodeFormatPNG {
+		c.Data(http.StatusOK, "image/png", image)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, provider.DisplayName+" QR code retrieved successfully", gin.H{
+		"account_id": qrCode.AccountID,
+		"qrcode":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(image),
+		"issued_at":  qrCode.IssuedAt,
+		"expires_at": qrCode.ExpiresAt,
+	})
+}
+
+// CreateHostedAuthLinkRequest represents request to create a hosted auth wizard link
+type CreateHostedAuthLinkRequest struct {
+	Providers []string `json:"providers"` // Providers offered by the wizard, all providers when empty
+}
+
+// CreateHostedAuthLink handles creating a link to the Unipile hosted auth wizard
+func (h *AccountHandlerImpl) CreateHostedAuthLink(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var req CreateHostedAuthLinkRequest
+	// The body is optional, an empty one offers every provider
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, errs.WrapValidationError(err, "Invalid request data"))
+			return
+		}
+	}
+
+	link, err := h.accountUsecase.CreateHostedAuthLink(c.Request.Context(), userID, req.Providers)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Hosted auth link created successfully", gin.H{
+		"url":        link.URL,
+		"expires_at": link.ExpiresAt,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,10 +20,19 @@ import (
 
 type accountUsecaseMock struct {
 	listUserAccountsFn         func(ctx context.Context, userID uint) ([]*entity.Account, error)
-	disconnectLinkedInFn       func(ctx context.Context, userID uint, accountID string) error
-	connectLinkedInFn          func(ctx context.Context, userID uint, req *accountusecase.ConnectLinkedInRequest) (*entity.Account, error)
+	disconnectAccountFn        func(ctx context.Context, userID uint, provider, accountID string) error
+	connectAccountFn           func(ctx context.Context, userID uint, req *accountusecase.ConnectAccountRequest) (*entity.Account, error)
 	solveCheckpointFn          func(ctx context.Context, userID uint, req *accountusecase.SolveCheckpointRequest) (*entity.Account, error)
-	waitForAccountValidationFn func(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error)
+	waitForAccountValidationFn func(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error)
+	reconcileAccountsFn        func(ctx context.Context) error
+	handleAccountStatusEventFn func(ctx context.Context, event *accountusecase.AccountStatusEvent) (*entity.Account, error)
+	expireCheckpointsFn        func(ctx context.Context) error
+	reconnectAccountFn         func(ctx context.Context, userID uint, accountID string, req *accountusecase.ConnectAccountRequest) (*entity.Account, error)
+	resendCheckpointFn         func(ctx context.Context, userID uint, provider, accountID string) (*entity.Account, error)
+	getProviderFn              func(name string) (*accountusecase.Provider, error)
+	getQRCodeFn                func(ctx context.Context, userID uint, provider, accountID string) (*accountusecase.QRCode, error)
+	createHostedAuthLinkFn     func(ctx context.Context, userID uint, providers []string) (*accountusecase.HostedAuthLink, error)
+	handleHostedAuthFn         func(ctx context.Context, state string, notification *accountusecase.HostedAuthNotification) (*entity.Account, error)
 }
 
 var _ accountusecase.Usecase = (*accountUsecaseMock)(nil)
@@ -34,18 +44,18 @@ func (m *accountUsecaseMock) ListUserAccounts(ctx context.Context, userID uint)
 	return m.listUserAccountsFn(ctx, userID)
 }
 
-func (m *accountUsecaseMock) DisconnectLinkedIn(ctx context.Context, userID uint, accountID string) error {
-	if m.disconnectLinkedInFn == nil {
+func (m *accountUsecaseMock) DisconnectAccount(ctx context.Context, userID uint, provider, accountID string) error {
+	if m.disconnectAccountFn == nil {
 		return nil
 	}
-	return m.disconnectLinkedInFn(ctx, userID, accountID)
+	return m.disconnectAccountFn(ctx, userID, provider, accountID)
 }
 
-func (m *accountUsecaseMock) ConnectLinkedInAccount(ctx context.Context, userID uint, req *accountusecase.ConnectLinkedInRequest) (*entity.Account, error) {
-	if m.connectLinkedInFn == nil {
+func (m *accountUsecaseMock) ConnectAccount(ctx context.Context, userID uint, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+	if m.connectAccountFn == nil {
 		return nil, nil
 	}
-	return m.connectLinkedInFn(ctx, userID, req)
+	return m.connectAccountFn(ctx, userID, req)
 }
 
 func (m *accountUsecaseMock) SolveCheckpoint(ctx context.Context, userID uint, req *accountusecase.SolveCheckpointRequest) (*entity.Account, error) {
@@ -55,11 +65,78 @@ func (m *accountUsecaseMock) SolveCheckpoint(ctx context.Context, userID uint, r
 	return m.solveCheckpointFn(ctx, userID, req)
 }
 
-func (m *accountUsecaseMock) WaitForAccountValidation(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error) {
+func (m *accountUsecaseMock) WaitForAccountValidation(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error) {
 	if m.waitForAccountValidationFn == nil {
 		return nil, nil
 	}
-	return m.waitForAccountValidationFn(ctx, userID, accountID, timeout)
+	return m.waitForAccountValidationFn(ctx, userID, provider, accountID, timeout)
+}
+
+func (m *accountUsecaseMock) ReconcileAccounts(ctx context.Context) error {
+	if m.reconcileAccountsFn == nil {
+		return nil
+	}
+	return m.reconcileAccountsFn(ctx)
+}
+
+func (m *accountUsecaseMock) HandleAccountStatusEvent(ctx context.Context, event *accountusecase.AccountStatusEvent) (*entity.Account, error) {
+	if m.handleAccountStatusEventFn == nil {
+		return nil, nil
+	}
+	return m.handleAccountStatusEventFn(ctx, event)
+}
+
+func (m *accountUsecaseMock) ExpireCheckpoints(ctx context.Context) error {
+	if m.expireCheckpointsFn == nil {
+		return nil
+	}
+	return m.expireCheckpointsFn(ctx)
+}
+
+func (m *accountUsecaseMock) ReconnectAccount(ctx context.Context, userID uint, accountID string, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+	if m.reconnectAccountFn == nil {
+		return nil, nil
+	}
+	return m.reconnectAccountFn(ctx, userID, accountID, req)
+}
+
+func (m *accountUsecaseMock) ResendCheckpoint(ctx context.Context, userID uint, provider, accountID string) (*entity.Account, error) {
+	if m.resendCheckpointFn == nil {
+		return nil, nil
+	}
+	return m.resendCheckpointFn(ctx, userID, provider, accountID)
+}
+
+func (m *accountUsecaseMock) GetQRCode(ctx context.Context, userID uint, provider, accountID string) (*accountusecase.QRCode, error) {
+	if m.getQRCodeFn == nil {
+		return nil, nil
+	}
+	return m.getQRCodeFn(ctx, userID, provider, accountID)
+}
+
+func (m *accountUsecaseMock) CreateHostedAuthLink(ctx context.Context, userID uint, providers []string) (*accountusecase.HostedAuthLink, error) {
+	if m.createHostedAuthLinkFn == nil {
+		return nil, nil
+	}
+	return m.createHostedAuthLinkFn(ctx, userID, providers)
+}
+
+func (m *accountUsecaseMock) HandleHostedAuthNotification(ctx context.Context, state string, notification *accountusecase.HostedAuthNotification) (*entity.Account, error) {
+	if m.handleHostedAuthFn == nil {
+		return nil, nil
+	}
+	return m.handleHostedAuthFn(ctx, state, notification)
+}
+
+func (m *accountUsecaseMock) GetProvider(name string) (*accountusecase.Provider, error) {
+	if m.getProviderFn == nil {
+		return accountusecase.NewProviderRegistry(accountusecase.DefaultProviders()...).Get(name)
+	}
+	return m.getProviderFn(name)
+}
+
+func (m *accountUsecaseMock) ListProviders() []accountusecase.Provider {
+	return accountusecase.DefaultProviders()
 }
 
 func TestAccountHandler_ListUserAccounts_Success(t *testing.T) {
@@ -106,7 +183,7 @@ func TestAccountHandler_ListUserAccounts_Success(t *testing.T) {
 	}
 }
 
-func TestAccountHandler_ConnectLinkedIn_ValidationError(t *testing.T) {
+func TestAccountHandler_ConnectAccount_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	h := &AccountHandlerImpl{accountUsecase: &accountUsecaseMock{}}
@@ -117,9 +194,10 @@ func TestAccountHandler_ConnectLinkedIn_ValidationError(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/connect", body)
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
 	c.Set("user_id", uint(1))
 
-	h.ConnectLinkedIn(c)
+	h.ConnectAccount(c)
 
 	if w.Code != http.StatusBadRequest {
 		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
@@ -135,6 +213,144 @@ func TestAccountHandler_ConnectLinkedIn_ValidationError(t *testing.T) {
 	}
 }
 
+func TestAccountHandler_ConnectAccount_Provider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotReq *accountusecase.ConnectAccountRequest
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			connectAccountFn: func(ctx context.Context, userID uint, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+				gotReq = req
+				return &entity.Account{UserID: userID, Provider: req.Provider, AccountID: "wa-1", CurrentStatus: entity.AccountStatusPending}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/whatsapp/connect", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "whatsapp"}}
+	c.Set("user_id", uint(1))
+
+	h.ConnectAccount(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if gotReq == nil || gotReq.Provider != "WHATSAPP" {
+		t.Fatalf("unexpected connect request: %+v", gotReq)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["message"] != "WhatsApp account connected successfully" {
+		t.Fatalf("unexpected message: %v", resp["message"])
+	}
+}
+
+func TestAccountHandler_ConnectAccount_UnsupportedProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			connectAccountFn: func(ctx context.Context, userID uint, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+				t.Fatal("expected usecase not to be called")
+				return nil, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/myspace/connect", bytes.NewBufferString(`{"type":"credentials","username":"user","password":"pass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "myspace"}}
+	c.Set("user_id", uint(1))
+
+	h.ConnectAccount(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp errs.CodedError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Message != "Unsupported provider" {
+		t.Fatalf("unexpected message: %s", resp.Message)
+	}
+}
+
+func TestAccountHandler_ReconnectAccount_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotAccountID string
+	var gotReq *accountusecase.ConnectAccountRequest
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			reconnectAccountFn: func(ctx context.Context, userID uint, accountID string, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+				gotAccountID = accountID
+				gotReq = req
+				return &entity.Account{UserID: userID, AccountID: accountID, CurrentStatus: entity.AccountStatusOK}, nil
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{"account_id":"acc-1","type":"cookie","access_token":"li_at"}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/reconnect", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
+	c.Set("user_id", uint(1))
+
+	h.ReconnectAccount(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if gotAccountID != "acc-1" {
+		t.Fatalf("expected account ID acc-1, got %s", gotAccountID)
+	}
+	if gotReq == nil || gotReq.AccessToken != "li_at" {
+		t.Fatalf("unexpected reconnect request: %+v", gotReq)
+	}
+}
+
+func TestAccountHandler_ReconnectAccount_NotReconnectable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			reconnectAccountFn: func(ctx context.Context, userID uint, accountID string, req *accountusecase.ConnectAccountRequest) (*entity.Account, error) {
+				return nil, errs.ErrAccountNotReconnectable
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{"account_id":"acc-1","type":"credentials","username":"user","password":"pass"}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/reconnect", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
+	c.Set("user_id", uint(1))
+
+	h.ReconnectAccount(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
 func TestAccountHandler_SolveCheckpoint_InvalidCode(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -157,6 +373,7 @@ func TestAccountHandler_SolveCheckpoint_InvalidCode(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/checkpoint", bytes.NewBuffer(raw))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
 	c.Set("user_id", uint(1))
 
 	h.SolveCheckpoint(c)
@@ -178,16 +395,107 @@ func TestAccountHandler_SolveCheckpoint_InvalidCode(t *testing.T) {
 	}
 }
 
+func TestAccountHandler_ResendCheckpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, http.StatusOK},
+		{"expired checkpoint", errs.ErrInvalidCodeOrExpiredCheckpoint, http.StatusUnauthorized},
+		{"no pending checkpoint", errs.ErrNoPendingCheckpoint, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &AccountHandlerImpl{
+				accountUsecase: &accountUsecaseMock{
+					resendCheckpointFn: func(ctx context.Context, userID uint, provider, accountID string) (*entity.Account, error) {
+						if accountID != "acc-1" {
+							t.Fatalf("unexpected account ID: %s", accountID)
+						}
+						if tt.err != nil {
+							return nil, tt.err
+						}
+						return &entity.Account{AccountID: accountID, CurrentStatus: entity.AccountStatusPending}, nil
+					},
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/checkpoint/resend", bytes.NewBufferString(`{"account_id":"acc-1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+			c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
+			c.Set("user_id", uint(1))
+
+			h.ResendCheckpoint(c)
+
+			if w.Code != tt.expected {
+				t.Fatalf("expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestAccountHandler_DisconnectAccount_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var called bool
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			disconnectAccountFn: func(ctx context.Context, userID uint, provider, accountID string) error {
+				called = true
+				if userID != 5 || provider != "WHATSAPP" || accountID != "acc-77" {
+					t.Fatalf("unexpected arguments: %d, %s, %s", userID, provider, accountID)
+				}
+				return nil
+			},
+		},
+	}
+
+	payload := map[string]string{"account_id": "acc-77"}
+	raw, _ := json.Marshal(payload)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/whatsapp/disconnect", bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "whatsapp"}}
+	c.Set("user_id", uint(5))
+
+	h.DisconnectAccount(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !called {
+		t.Fatal("expected usecase to be called")
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["message"] != "WhatsApp account disconnected successfully" {
+		t.Fatalf("unexpected message: %v", resp["message"])
+	}
+}
+
 func TestAccountHandler_DisconnectLinkedIn_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var called bool
 	h := &AccountHandlerImpl{
 		accountUsecase: &accountUsecaseMock{
-			disconnectLinkedInFn: func(ctx context.Context, userID uint, accountID string) error {
+			disconnectAccountFn: func(ctx context.Context, userID uint, provider, accountID string) error {
 				called = true
-				if userID != 5 || accountID != "acc-77" {
-					t.Fatalf("unexpected arguments: %d, %s", userID, accountID)
+				if userID != 5 || provider != "LINKEDIN" || accountID != "acc-77" {
+					t.Fatalf("unexpected arguments: %d, %s, %s", userID, provider, accountID)
 				}
 				return nil
 			},
@@ -228,7 +536,7 @@ func TestAccountHandler_WaitForAccountValidation_Success(t *testing.T) {
 	var called bool
 	h := &AccountHandlerImpl{
 		accountUsecase: &accountUsecaseMock{
-			waitForAccountValidationFn: func(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error) {
+			waitForAccountValidationFn: func(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error) {
 				called = true
 				if userID != 3 || accountID != "acc-456" {
 					t.Fatalf("unexpected arguments: %d, %s", userID, accountID)
@@ -257,6 +565,7 @@ func TestAccountHandler_WaitForAccountValidation_Success(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/wait-validation", bytes.NewBuffer(raw))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
 	c.Set("user_id", uint(3))
 
 	h.WaitForAccountValidation(c)
@@ -282,7 +591,7 @@ func TestAccountHandler_WaitForAccountValidation_ValidationError(t *testing.T) {
 
 	h := &AccountHandlerImpl{
 		accountUsecase: &accountUsecaseMock{
-			waitForAccountValidationFn: func(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error) {
+			waitForAccountValidationFn: func(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error) {
 				return nil, errs.WrapValidationError(errors.New("account not found"), "Account not found")
 			},
 		},
@@ -299,6 +608,7 @@ func TestAccountHandler_WaitForAccountValidation_ValidationError(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/accounts/linkedin/wait-validation", bytes.NewBuffer(raw))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "linkedin"}}
 	c.Set("user_id", uint(1))
 
 	h.WaitForAccountValidation(c)
@@ -316,3 +626,151 @@ func TestAccountHandler_WaitForAccountValidation_ValidationError(t *testing.T) {
 		t.Fatalf("expected validation error kind, got %s", resp.Kind)
 	}
 }
+
+func TestAccountHandler_GetQRCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			getQRCodeFn: func(ctx context.Context, userID uint, provider, accountID string) (*accountusecase.QRCode, error) {
+				if provider != "WHATSAPP" || accountID != "wa-1" {
+					t.Fatalf("unexpected arguments: %s, %s", provider, accountID)
+				}
+				return &accountusecase.QRCode{AccountID: accountID, Code: "2@abc", IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		status      int
+		contentType string
+	}{
+		{"png", "account_id=wa-1", http.StatusOK, "image/png"},
+		{"data uri", "account_id=wa-1&format=data_uri", http.StatusOK, "application/json; charset=utf-8"},
+		{"missing account", "format=png", http.StatusBadRequest, "application/json; charset=utf-8"},
+		{"unknown format", "account_id=wa-1&format=svg", http.StatusBadRequest, "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/whatsapp/qrcode?"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: "whatsapp"}}
+			c.Set("user_id", uint(1))
+
+			h.GetQRCode(c)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Fatalf("unexpected content type: %s", ct)
+			}
+			if tt.name == "png" && !bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")) {
+				t.Fatal("expected a PNG image")
+			}
+			if tt.name == "data uri" {
+				var resp map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if qr, _ := resp["qrcode"].(string); !strings.HasPrefix(qr, "data:image/png;base64,") {
+					t.Fatalf("unexpected qrcode: %v", resp["qrcode"])
+				}
+			}
+		})
+	}
+}
+
+func TestAccountHandler_GetQRCode_Expired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			getQRCodeFn: func(ctx context.Context, userID uint, provider, accountID string) (*accountusecase.QRCode, error) {
+				return nil, errs.ErrInvalidCodeOrExpiredCheckpoint
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/whatsapp/qrcode?account_id=wa-1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "whatsapp"}}
+	c.Set("user_id", uint(1))
+
+	h.GetQRCode(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAccountHandler_CreateHostedAuthLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expiresAt := time.Now().Add(time.Hour)
+	var receivedProviders []string
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			createHostedAuthLinkFn: func(ctx context.Context, userID uint, providers []string) (*accountusecase.HostedAuthLink, error) {
+				receivedProviders = providers
+				return &accountusecase.HostedAuthLink{URL: "https://account.unipile.com/link", ExpiresAt: expiresAt}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/hosted-link", strings.NewReader(`{"providers":["LINKEDIN"]}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", uint(1))
+
+	h.CreateHostedAuthLink(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if len(receivedProviders) != 1 || receivedProviders[0] != "LINKEDIN" {
+		t.Fatalf("unexpected providers: %v", receivedProviders)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["url"] != "https://account.unipile.com/link" {
+		t.Fatalf("unexpected url: %v", resp["url"])
+	}
+}
+
+func TestAccountHandler_CreateHostedAuthLink_EmptyBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	h := &AccountHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			createHostedAuthLinkFn: func(ctx context.Context, userID uint, providers []string) (*accountusecase.HostedAuthLink, error) {
+				called = true
+				if len(providers) != 0 {
+					t.Fatalf("expected no providers, got %v", providers)
+				}
+				return &accountusecase.HostedAuthLink{URL: "https://account.unipile.com/link"}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/hosted-link", nil)
+	c.Set("user_id", uint(1))
+
+	h.CreateHostedAuthLink(c)
+
+	if w.Code != http.StatusOK || !called {
+		t.Fatalf("expected status %d with usecase called, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -43,20 +43,6 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func (h *AuthHandlerImpl) userIDFromContext(c *gin.Context) (uint, error) {
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
-		return 0, errs.ErrUserNotAuthenticated
-	}
-
-	userID, ok := userIDStr.(uint)
-	if !ok {
-		return 0, errs.ErrInvalidUserID
-	}
-
-	return userID, nil
-}
-
 // Register handles user registration
 func (h *AuthHandlerImpl) Register(c *gin.Context) {
 	var req RegisterRequest
@@ -152,7 +138,7 @@ func (h *AuthHandlerImpl) RefreshToken(c *gin.Context) {
 
 // GetCurrentUser returns current user info
 func (h *AuthHandlerImpl) GetCurrentUser(c *gin.Context) {
-	userID, err := h.userIDFromContext(c)
+	userID, err := userIDFromContext(c)
 	if err != nil {
 		RespondError(c, err)
 		return
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/errs"
+)
+
+// userIDFromContext returns the ID of the user authenticated by the JWT middleware
+func userIDFromContext(c *gin.Context) (uint, error) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		return 0, errs.ErrUserNotAuthenticated
+	}
+
+	userID, ok := userIDValue.(uint)
+	if !ok {
+		return 0, errs.ErrInvalidUserID
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/service"
+)
+
+// eventHeartbeatInterval keeps idle event streams alive through proxies
+const eventHeartbeatInterval = 15 * time.Second
+
+// EventHandler streams real-time events to the dashboard
+type EventHandler interface {
+	StreamAccountEvents(c *gin.Context)
+}
+
+// EventHandlerImpl streams real-time events to the dashboard
+type EventHandlerImpl struct {
+	eventHub          service.AccountEventHub
+	jwtService        service.JWTService
+	heartbeatInterval time.Duration
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventHub service.AccountEventHub, jwtService service.JWTService) EventHandler {
+	return &EventHandlerImpl{
+		eventHub:          eventHub,
+		jwtService:        jwtService,
+		heartbeatInterval: eventHeartbeatInterval,
+	}
+}
+
+// StreamAccountEvents streams account status events of the current user as Server-Sent Events.
+// The stream is closed once the token it was opened with expires or is revoked, the token being checked again on
+// every heartbeat, so the client has to authenticate again to keep receiving events.
+func (h *EventHandlerImpl) StreamAccountEvents(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+	// A nil channel never fires when the token has no expiry
+	var expired <-chan time.Time
+	if value, exists := c.Get("token_claims"); exists {
+		if claims, ok := value.(*service.Claims); ok && claims.ExpiresAt != nil {
+			expiry := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+			defer expiry.Stop()
+			expired = expiry.C
+		}
+	}
+
+	events, unsubscribe := h.eventHub.Subscribe(userID)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout, so clear the deadline for this connection
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	// Send an initial comment so the client knows the stream is open
+	if _, err := fmt.Fprint(c.Writer, ": connected\n\n"); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-expired:
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Hub closed on server shutdown
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := h.jwtService.ValidateToken(tokenString); err != nil {
+				// Token revoked by a logout or a refresh
+				return
+			}
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
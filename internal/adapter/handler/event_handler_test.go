@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"unipile-connector/internal/domain/service"
+)
+
+func newEventTestServer(t *testing.T, hub service.AccountEventHub, userID uint) *httptest.Server {
+	t.Helper()
+	return newAuthenticatedEventTestServer(t, hub, &expiringJWTService{expiresIn: time.Hour}, userID)
+}
+
+// newAuthenticatedEventTestServer serves the event stream behind the JWT middleware of the given JWT service
+func newAuthenticatedEventTestServer(t *testing.T, hub service.AccountEventHub, jwtService service.JWTService, userID uint) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := &EventHandlerImpl{eventHub: hub, jwtService: jwtService, heartbeatInterval: 50 * time.Millisecond}
+	router := gin.New()
+	router.GET("/api/v1/accounts/events", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		claims, err := jwtService.ValidateToken(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("token_claims", claims)
+		c.Next()
+	}, h.StreamAccountEvents)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// openEventStream opens the event stream with the token and waits for it to be connected
+func openEventStream(t *testing.T, srv *httptest.Server, token string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/accounts/events", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	reader := bufio.NewReader(resp.Body)
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("expected connected comment, got %q (%v)", line, err)
+	}
+	return resp, reader
+}
+
+// waitStreamEnd fails the test unless the stream ends within the timeout
+func waitStreamEnd(t *testing.T, reader *bufio.Reader, timeout time.Duration) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, err := reader.ReadString('\n'); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("expected the stream to end")
+	}
+}
+
+// readEvent reads lines until a data line is found, skipping comments and heartbeats
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+	var eventType string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			return eventType, strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventHandler_StreamAccountEvents(t *testing.T) {
+	hub := service.NewAccountEventHub()
+	defer hub.Close()
+	srv := newEventTestServer(t, hub, 1)
+
+	resp, err := http.Get(srv.URL + "/api/v1/accounts/events")
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	// Wait for the stream to open before publishing
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("expected connected comment, got %q (%v)", line, err)
+	}
+
+	hub.Publish(2, service.AccountEvent{Type: service.AccountEventTypeStatus, AccountID: "other-user"})
+	hub.Publish(1, service.AccountEvent{Type: service.AccountEventTypeStatus, AccountID: "acc-1", Provider: "LINKEDIN", Status: "OK"})
+
+	eventType, data := readEvent(t, reader)
+	if eventType != service.AccountEventTypeStatus {
+		t.Fatalf("unexpected event type: %s", eventType)
+	}
+	var event service.AccountEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+	if event.AccountID != "acc-1" || event.Status != "OK" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
+
+func TestEventHandler_StreamAccountEvents_ClosedOnShutdown(t *testing.T) {
+	hub := service.NewAccountEventHub()
+	srv := newEventTestServer(t, hub, 1)
+
+	resp, err := http.Get(srv.URL + "/api/v1/accounts/events")
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+
+	hub.Close()
+
+	waitStreamEnd(t, reader, 2*time.Second)
+}
+
+func TestEventHandler_StreamAccountEvents_ClosedOnTokenExpiry(t *testing.T) {
+	hub := service.NewAccountEventHub()
+	defer hub.Close()
+	jwtService := &expiringJWTService{expiresIn: 200 * time.Millisecond}
+	srv := newAuthenticatedEventTestServer(t, hub, jwtService, 1)
+
+	// Heartbeats keep validating the token, only its expiry ends the stream
+	opened := time.Now()
+	_, reader := openEventStream(t, srv, "token")
+	waitStreamEnd(t, reader, 2*time.Second)
+	if elapsed := time.Since(opened); elapsed < jwtService.expiresIn {
+		t.Fatalf("expected the stream to stay open until the token expiry, ended after %s", elapsed)
+	}
+}
+
+func TestEventHandler_StreamAccountEvents_ClosedOnTokenRevocation(t *testing.T) {
+	hub := service.NewAccountEventHub()
+	defer hub.Close()
+	jwtService := service.NewJWTService("secret", "test", service.NewTokenBlacklistService())
+	srv := newAuthenticatedEventTestServer(t, hub, jwtService, 1)
+
+	token, err := jwtService.GenerateToken(1, "jane")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	_, reader := openEventStream(t, srv, token)
+
+	// Logging out revokes the token, the next heartbeat ends the stream
+	jwtService.BlacklistToken(token)
+	waitStreamEnd(t, reader, 2*time.Second)
+}
+
+// expiringJWTService accepts any token as one expiring expiresIn after its first validation, other methods panic
+type expiringJWTService struct {
+	service.JWTService
+	expiresIn time.Duration
+	expiresAt time.Time
+}
+
+func (s *expiringJWTService) ValidateToken(string) (*service.Claims, error) {
+	if s.expiresAt.IsZero() {
+		s.expiresAt = time.Now().Add(s.expiresIn)
+	}
+	claims := &service.Claims{UserID: 1}
+	// NewNumericDate would truncate the expiry to the second
+	claims.ExpiresAt = &jwt.NumericDate{Time: s.expiresAt}
+	return claims, nil
+}
+
+func TestEventHandler_StreamAccountEvents_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &EventHandlerImpl{eventHub: service.NewAccountEventHub(), heartbeatInterval: time.Second}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/events", nil)
+
+	h.StreamAccountEvents(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -2,11 +2,16 @@ package handler
 
 // Handlers handles all requests
 type Handlers struct {
-	AuthHandler    AuthHandler
-	AccountHandler AccountHandler
+	AuthHandler       AuthHandler
+	AccountHandler    AccountHandler
+	WebhookHandler    WebhookHandler
+	EventHandler      EventHandler
+	MessagingHandler  MessagingHandler
+	ProfileHandler    ProfileHandler
+	InvitationHandler InvitationHandler
 }
 
 // NewHandlers creates a new handlers
-func NewHandlers(authHandler AuthHandler, accountHandler AccountHandler) *Handlers {
-	return &Handlers{AuthHandler: authHandler, AccountHandler: accountHandler}
+func NewHandlers(authHandler AuthHandler, accountHandler AccountHandler, webhookHandler WebhookHandler, eventHandler EventHandler, messagingHandler MessagingHandler, profileHandler ProfileHandler, invitationHandler InvitationHandler) *Handlers {
+	return &Handlers{AuthHandler: authHandler, AccountHandler: accountHandler, WebhookHandler: webhookHandler, EventHandler: eventHandler, MessagingHandler: messagingHandler, ProfileHandler: profileHandler, InvitationHandler: invitationHandler}
 }
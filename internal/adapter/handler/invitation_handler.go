@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/usecase/invitation"
+)
+
+// InvitationHandler handles LinkedIn connection invitation requests of an account
+type InvitationHandler interface {
+	SendInvitation(c *gin.Context)
+	ListSentInvitations(c *gin.Context)
+	ListReceivedInvitations(c *gin.Context)
+	WithdrawInvitation(c *gin.Context)
+}
+
+// InvitationHandlerImpl handles LinkedIn connection invitation requests of an account
+type InvitationHandlerImpl struct {
+	invitationUsecase invitation.Usecase
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(invitationUsecase invitation.Usecase) InvitationHandler {
+	return &InvitationHandlerImpl{
+		invitationUsecase: invitationUsecase,
+	}
+}
+
+// SendInvitationBody represents the body of an invitation, the message is an optional note of up to 300 characters
+type SendInvitationBody struct {
+	ProviderID string `json:"provider_id" binding:"required"`
+	Message    string `json:"message"`
+}
+
+// SendInvitation invites a LinkedIn user to connect with an account of the current user
+func (h *InvitationHandlerImpl) SendInvitation(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var body SendInvitationBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid request body"))
+		return
+	}
+
+	sent, err := h.invitationUsecase.SendInvitation(c.Request.Context(), userID, c.Param("id"), &invitation.SendInvitationRequest{
+		ProviderID: body.ProviderID,
+		Message:    body.Message,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusCreated, "Invitation sent successfully", gin.H{
+		"invitation": sent,
+	})
+}
+
+// ListSentInvitations lists one page of the pending invitations sent by an account of the current user
+func (h *InvitationHandlerImpl) ListSentInvitations(c *gin.Context) {
+	h.listInvitations(c, h.invitationUsecase.ListSentInvitations)
+}
+
+// ListReceivedInvitations lists one page of the pending invitations received by an account of the current user
+func (h *InvitationHandlerImpl) ListReceivedInvitations(c *gin.Context) {
+	h.listInvitations(c, h.invitationUsecase.ListReceivedInvitations)
+}
+
+// listInvitations binds the page of an invitation listing and responds with the listed page
+func (h *InvitationHandlerImpl) listInvitations(c *gin.Context, list func(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error)) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	page, err := list(c.Request.Context(), userID, c.Param("id"), &invitation.PageRequest{Limit: query.Limit, Cursor: query.Cursor})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Invitations retrieved successfully", gin.H{
+		"invitations": page.Invitations,
+		"cursor":      page.Cursor,
+	})
+}
+
+// WithdrawInvitation withdraws a pending invitation sent by an account of the current user
+func (h *InvitationHandlerImpl) WithdrawInvitation(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	withdrawn, err := h.invitationUsecase.WithdrawInvitation(c.Request.Context(), userID, c.Param("id"), c.Param("invitationId"))
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Invitation withdrawn successfully", gin.H{
+		"invitation": withdrawn,
+	})
+}
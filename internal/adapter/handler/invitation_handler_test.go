@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/usecase/invitation"
+)
+
+type invitationUsecaseMock struct {
+	sendInvitationFn          func(ctx context.Context, userID uint, accountID string, req *invitation.SendInvitationRequest) (*entity.Invitation, error)
+	listSentInvitationsFn     func(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error)
+	listReceivedInvitationsFn func(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error)
+	withdrawInvitationFn      func(ctx context.Context, userID uint, accountID, invitationID string) (*entity.Invitation, error)
+	handleRelationEventFn     func(ctx context.Context, event *invitation.RelationEvent) (int64, error)
+}
+
+var _ invitation.Usecase = (*invitationUsecaseMock)(nil)
+
+func (m *invitationUsecaseMock) SendInvitation(ctx context.Context, userID uint, accountID string, req *invitation.SendInvitationRequest) (*entity.Invitation, error) {
+	return m.sendInvitationFn(ctx, userID, accountID, req)
+}
+
+func (m *invitationUsecaseMock) ListSentInvitations(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error) {
+	return m.listSentInvitationsFn(ctx, userID, accountID, req)
+}
+
+func (m *invitationUsecaseMock) ListReceivedInvitations(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error) {
+	return m.listReceivedInvitationsFn(ctx, userID, accountID, req)
+}
+
+func (m *invitationUsecaseMock) WithdrawInvitation(ctx context.Context, userID uint, accountID, invitationID string) (*entity.Invitation, error) {
+	return m.withdrawInvitationFn(ctx, userID, accountID, invitationID)
+}
+
+func (m *invitationUsecaseMock) HandleRelationEvent(ctx context.Context, event *invitation.RelationEvent) (int64, error) {
+	return m.handleRelationEventFn(ctx, event)
+}
+
+func TestInvitationHandler_SendInvitation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedAccountID string
+	var received *invitation.SendInvitationRequest
+	h := &InvitationHandlerImpl{
+		invitationUsecase: &invitationUsecaseMock{
+			sendInvitationFn: func(ctx context.Context, userID uint, accountID string, req *invitation.SendInvitationRequest) (*entity.Invitation, error) {
+				receivedAccountID, received = accountID, req
+				return &entity.Invitation{InvitationID: "inv-1", Status: entity.InvitationStatusPending, ProviderID: req.ProviderID}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/acc-1/invitations", bytes.NewBufferString(`{"provider_id":"ACoAA","message":"Hi Jane"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+	c.Set("user_id", uint(1))
+
+	h.SendInvitation(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if receivedAccountID != "acc-1" || received.ProviderID != "ACoAA" || received.Message != "Hi Jane" {
+		t.Fatalf("unexpected request for %s: %+v", receivedAccountID, received)
+	}
+
+	var resp struct {
+		Invitation map[string]interface{} `json:"invitation"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Invitation["id"] != "inv-1" || resp.Invitation["status"] != "pending" {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestInvitationHandler_SendInvitation_MissingProviderID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &InvitationHandlerImpl{invitationUsecase: &invitationUsecaseMock{}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/acc-1/invitations", bytes.NewBufferString(`{"message":"Hi"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+	c.Set("user_id", uint(1))
+
+	h.SendInvitation(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestInvitationHandler_ListSentInvitations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *invitation.PageRequest
+	h := &InvitationHandlerImpl{
+		invitationUsecase: &invitationUsecaseMock{
+			listSentInvitationsFn: func(ctx context.Context, userID uint, accountID string, req *invitation.PageRequest) (*invitation.InvitationPage, error) {
+				received = req
+				return &invitation.InvitationPage{Invitations: []*entity.Invitation{{InvitationID: "inv-1"}}, Cursor: "page-2"}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/invitations/sent?limit=20&cursor=page-1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+	c.Set("user_id", uint(1))
+
+	h.ListSentInvitations(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received.Limit != 20 || received.Cursor != "page-1" {
+		t.Fatalf("unexpected request: %+v", received)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["cursor"] != "page-2" || len(resp["invitations"].([]interface{})) != 1 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
+func TestInvitationHandler_WithdrawInvitation_NotPending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedInvitationID string
+	h := &InvitationHandlerImpl{
+		invitationUsecase: &invitationUsecaseMock{
+			withdrawInvitationFn: func(ctx context.Context, userID uint, accountID, invitationID string) (*entity.Invitation, error) {
+				receivedInvitationID = invitationID
+				return nil, errs.ErrInvitationNotPending
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/api/v1/accounts/acc-1/invitations/sent/inv-1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "invitationId", Value: "inv-1"}}
+	c.Set("user_id", uint(1))
+
+	h.WithdrawInvitation(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if receivedInvitationID != "inv-1" {
+		t.Fatalf("unexpected invitation %s", receivedInvitationID)
+	}
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/messaging"
+)
+
+// MessagingHandler handles chat and message requests of an account
+type MessagingHandler interface {
+	ListChats(c *gin.Context)
+	Inbox(c *gin.Context)
+	SearchMessages(c *gin.Context)
+	ListChatAttendees(c *gin.Context)
+	ListMessages(c *gin.Context)
+	SendMessage(c *gin.Context)
+	StartChat(c *gin.Context)
+}
+
+// MessagingHandlerImpl handles chat and message requests of an account
+type MessagingHandlerImpl struct {
+	messagingUsecase messaging.Usecase
+}
+
+// NewMessagingHandler creates a new messaging handler
+func NewMessagingHandler(messagingUsecase messaging.Usecase) MessagingHandler {
+	return &MessagingHandlerImpl{
+		messagingUsecase: messagingUsecase,
+	}
+}
+
+// PageQuery represents the pagination query parameters of a list request
+type PageQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=250"`
+	Cursor string `form:"cursor"`
+}
+
+func (q *PageQuery) usecaseRequest() messaging.PageRequest {
+	return messaging.PageRequest{Limit: q.Limit, Cursor: q.Cursor}
+}
+
+// ListChatsQuery represents the query parameters for listing chats
+type ListChatsQuery struct {
+	PageQuery
+	Unread *bool `form:"unread"`
+}
+
+// ListChats lists one page of the synced chats of an account of the current user
+func (h *MessagingHandlerImpl) ListChats(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query ListChatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	page, err := h.messagingUsecase.ListChats(c.Request.Context(), userID, c.Param("id"), &messaging.ListChatsRequest{
+		PageRequest: query.usecaseRequest(),
+		Unread:      query.Unread,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Chats retrieved successfully", gin.H{
+		"chats":     page.Chats,
+		"cursor":    page.Cursor,
+		"synced_at": page.SyncedAt,
+	})
+}
+
+// InboxQuery represents the query parameters of the unified inbox, dates are RFC 3339
+type InboxQuery struct {
+	PageQuery
+	Unread    *bool      `form:"unread"`
+	AccountID string     `form:"account_id"`
+	Provider  string     `form:"provider"`
+	Since     *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until     *time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// Inbox lists one page of the chats of every account of the current user, newest activity first
+func (h *MessagingHandlerImpl) Inbox(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query InboxQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	page, err := h.messagingUsecase.Inbox(c.Request.Context(), userID, &messaging.InboxRequest{
+		PageRequest: query.usecaseRequest(),
+		Unread:      query.Unread,
+		AccountID:   query.AccountID,
+		Provider:    query.Provider,
+		Since:       query.Since,
+		Until:       query.Until,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Inbox retrieved successfully", gin.H{
+		"items":  page.Items,
+		"cursor": page.Cursor,
+	})
+}
+
+// SearchQuery represents the query parameters of a message search
+type SearchQuery struct {
+	Q      string `form:"q" binding:"required"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=250"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// SearchMessages searches the messages of every account of the current user by text and attendee names, best match first
+func (h *MessagingHandlerImpl) SearchMessages(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	page, err := h.messagingUsecase.SearchMessages(c.Request.Context(), userID, &messaging.SearchRequest{
+		Query:  query.Q,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Messages searched successfully", gin.H{
+		"items":       page.Items,
+		"next_offset": page.NextOffset,
+	})
+}
+
+// ListChatAttendees lists one page of attendees of a chat of an account of the current user
+func (h *MessagingHandlerImpl) ListChatAttendees(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	pageReq := query.usecaseRequest()
+	page, err := h.messagingUsecase.ListChatAttendees(c.Request.Context(), userID, c.Param("id"), c.Param("chatId"), &pageReq)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Chat attendees retrieved successfully", gin.H{
+		"attendees": page.Attendees,
+		"cursor":    page.Cursor,
+	})
+}
+
+// ListMessages lists one page of the synced messages of a chat of an account of the current user, newest first
+func (h *MessagingHandlerImpl) ListMessages(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid query parameters"))
+		return
+	}
+
+	pageReq := query.usecaseRequest()
+	page, err := h.messagingUsecase.ListMessages(c.Request.Context(), userID, c.Param("id"), c.Param("chatId"), &pageReq)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Messages retrieved successfully", gin.H{
+		"messages": page.Messages,
+		"cursor":   page.Cursor,
+	})
+}
+
+// maxMessageBodySize caps the size of a message request, attachments included
+const maxMessageBodySize = 25 << 20
+
+// SendMessageBody represents a message sent as JSON or multipart/form-data, attachments need the latter
+type SendMessageBody struct {
+	Text string `form:"text" json:"text"`
+}
+
+// StartChatBody represents a new chat sent as JSON or multipart/form-data, attachments need the latter
+type StartChatBody struct {
+	AttendeeIDs []string `form:"attendee_ids" json:"attendee_ids" binding:"required,min=1,dive,required"`
+	Text        string   `form:"text" json:"text"`
+	Subject     string   `form:"subject" json:"subject"`
+	InMail      bool     `form:"inmail" json:"inmail"`
+}
+
+// SendMessage sends a message in a chat of an account of the current user
+func (h *MessagingHandlerImpl) SendMessage(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var body SendMessageBody
+	attachments, err := bindMessageBody(c, &body)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	sent, err := h.messagingUsecase.SendMessage(c.Request.Context(), userID, c.Param("id"), c.Param("chatId"), &messaging.SendMessageRequest{
+		Text:        body.Text,
+		Attachments: attachments,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusCreated, "Message sent successfully", gin.H{
+		"chat_id":    sent.ChatID,
+		"message_id": sent.MessageID,
+	})
+}
+
+// StartChat starts a chat from an account of the current user and sends the first message
+func (h *MessagingHandlerImpl) StartChat(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	var body StartChatBody
+	attachments, err := bindMessageBody(c, &body)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	sent, err := h.messagingUsecase.StartChat(c.Request.Context(), userID, c.Param("id"), &messaging.StartChatRequest{
+		AttendeeProviderIDs: body.AttendeeIDs,
+		Text:                body.Text,
+		Subject:             body.Subject,
+		InMail:              body.InMail,
+		Attachments:         attachments,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusCreated, "Chat started successfully", gin.H{
+		"chat_id":    sent.ChatID,
+		"message_id": sent.MessageID,
+	})
+}
+
+// bindMessageBody binds a JSON or multipart/form-data message body and reads the uploaded attachments
+func bindMessageBody(c *gin.Context, body any) ([]service.Attachment, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMessageBodySize)
+	if err := c.ShouldBind(body); err != nil {
+		return nil, errs.WrapValidationError(err, "Invalid request body")
+	}
+	if c.Request.MultipartForm == nil {
+		return nil, nil
+	}
+
+	files := c.Request.MultipartForm.File["attachments"]
+	attachments := make([]service.Attachment, 0, len(files))
+	for _, file := range files {
+		f, err := file.Open()
+		if err != nil {
+			return nil, errs.WrapValidationError(err, "Invalid attachment")
+		}
+		content, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errs.WrapValidationError(fmt.Errorf("failed to read %s: %w", file.Filename, err), "Invalid attachment")
+		}
+		attachments = append(attachments, service.Attachment{
+			FileName:    file.Filename,
+			ContentType: file.Header.Get("Content-Type"),
+			Content:     content,
+		})
+	}
+	return attachments, nil
+}
@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/usecase/messaging"
+)
+
+type messagingUsecaseMock struct {
+	listChatsFn          func(ctx context.Context, userID uint, accountID string, req *messaging.ListChatsRequest) (*messaging.ChatPage, error)
+	inboxFn              func(ctx context.Context, userID uint, req *messaging.InboxRequest) (*messaging.InboxPage, error)
+	searchMessagesFn     func(ctx context.Context, userID uint, req *messaging.SearchRequest) (*messaging.SearchPage, error)
+	listChatAttendeesFn  func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.PageRequest) (*messaging.AttendeePage, error)
+	listMessagesFn       func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.PageRequest) (*messaging.MessagePage, error)
+	sendMessageFn        func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.SendMessageRequest) (*messaging.SentMessage, error)
+	startChatFn          func(ctx context.Context, userID uint, accountID string, req *messaging.StartChatRequest) (*messaging.SentMessage, error)
+	handleMessageEventFn func(ctx context.Context, event *messaging.MessageEvent) (*entity.Message, error)
+}
+
+var _ messaging.Usecase = (*messagingUsecaseMock)(nil)
+
+func (m *messagingUsecaseMock) ListChats(ctx context.Context, userID uint, accountID string, req *messaging.ListChatsRequest) (*messaging.ChatPage, error) {
+	if m.listChatsFn == nil {
+		return &messaging.ChatPage{}, nil
+	}
+	return m.listChatsFn(ctx, userID, accountID, req)
+}
+
+func (m *messagingUsecaseMock) Inbox(ctx context.Context, userID uint, req *messaging.InboxRequest) (*messaging.InboxPage, error) {
+	if m.inboxFn == nil {
+		return &messaging.InboxPage{}, nil
+	}
+	return m.inboxFn(ctx, userID, req)
+}
+
+func (m *messagingUsecaseMock) SearchMessages(ctx context.Context, userID uint, req *messaging.SearchRequest) (*messaging.SearchPage, error) {
+	if m.searchMessagesFn == nil {
+		return &messaging.SearchPage{}, nil
+	}
+	return m.searchMessagesFn(ctx, userID, req)
+}
+
+func (m *messagingUsecaseMock) ListChatAttendees(ctx context.Context, userID uint, accountID, chatID string, req *messaging.PageRequest) (*messaging.AttendeePage, error) {
+	if m.listChatAttendeesFn == nil {
+		return &messaging.AttendeePage{}, nil
+	}
+	return m.listChatAttendeesFn(ctx, userID, accountID, chatID, req)
+}
+
+func (m *messagingUsecaseMock) ListMessages(ctx context.Context, userID uint, accountID, chatID string, req *messaging.PageRequest) (*messaging.MessagePage, error) {
+	if m.listMessagesFn == nil {
+		return &messaging.MessagePage{}, nil
+	}
+	return m.listMessagesFn(ctx, userID, accountID, chatID, req)
+}
+
+func (m *messagingUsecaseMock) SendMessage(ctx context.Context, userID uint, accountID, chatID string, req *messaging.SendMessageRequest) (*messaging.SentMessage, error) {
+	if m.sendMessageFn == nil {
+		return &messaging.SentMessage{}, nil
+	}
+	return m.sendMessageFn(ctx, userID, accountID, chatID, req)
+}
+
+func (m *messagingUsecaseMock) StartChat(ctx context.Context, userID uint, accountID string, req *messaging.StartChatRequest) (*messaging.SentMessage, error) {
+	if m.startChatFn == nil {
+		return &messaging.SentMessage{}, nil
+	}
+	return m.startChatFn(ctx, userID, accountID, req)
+}
+
+func (m *messagingUsecaseMock) SyncChats(ctx context.Context) error {
+	return nil
+}
+
+func (m *messagingUsecaseMock) HandleMessageEvent(ctx context.Context, event *messaging.MessageEvent) (*entity.Message, error) {
+	if m.handleMessageEventFn == nil {
+		return nil, nil
+	}
+	return m.handleMessageEventFn(ctx, event)
+}
+
+func TestMessagingHandler_ListChats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedAccountID string
+	var received *messaging.ListChatsRequest
+	h := &MessagingHandlerImpl{
+		messagingUsecase: &messagingUsecaseMock{
+			listChatsFn: func(ctx context.Context, userID uint, accountID string, req *messaging.ListChatsRequest) (*messaging.ChatPage, error) {
+				receivedAccountID = accountID
+				received = req
+				return &messaging.ChatPage{Chats: []*entity.Chat{{ChatID: "chat-1"}}, Cursor: "page-2"}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/chats?limit=20&cursor=page-1&unread=true", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+	c.Set("user_id", uint(1))
+
+	h.ListChats(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if receivedAccountID != "acc-1" || received.Limit != 20 || received.Cursor != "page-1" || received.Unread == nil || !*received.Unread {
+		t.Fatalf("unexpected request for %s: %+v", receivedAccountID, received)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["cursor"] != "page-2" || len(resp["chats"].([]interface{})) != 1 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
+func TestMessagingHandler_ListChats_InvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &MessagingHandlerImpl{messagingUsecase: &messagingUsecaseMock{}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/chats?limit=1000", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+	c.Set("user_id", uint(1))
+
+	h.ListChats(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMessagingHandler_ListMessages_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "account of another user", err: errs.ErrAccountNotFound},
+		{name: "chat of another account", err: errs.ErrChatNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedChatID string
+			h := &MessagingHandlerImpl{
+				messagingUsecase: &messagingUsecaseMock{
+					listMessagesFn: func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.PageRequest) (*messaging.MessagePage, error) {
+						receivedChatID = chatID
+						return nil, tt.err
+					},
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/chats/chat-1/messages", nil)
+			c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "chatId", Value: "chat-1"}}
+			c.Set("user_id", uint(1))
+
+			h.ListMessages(c)
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+			}
+			if receivedChatID != "chat-1" {
+				t.Fatalf("unexpected chat ID: %s", receivedChatID)
+			}
+		})
+	}
+}
+
+func TestMessagingHandler_SendMessage_Multipart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedChatID string
+	var received *messaging.SendMessageRequest
+	h := &MessagingHandlerImpl{
+		messagingUsecase: &messagingUsecaseMock{
+			sendMessageFn: func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.SendMessageRequest) (*messaging.SentMessage, error) {
+				receivedChatID = chatID
+				received = req
+				return &messaging.SentMessage{ChatID: chatID, MessageID: "msg-1"}, nil
+			},
+		},
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("text", "Hello")
+	part, _ := writer.CreateFormFile("attachments", "deck.pdf")
+	_, _ = part.Write([]byte("%PDF"))
+	_ = writer.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/acc-1/chats/chat-1/messages", &body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "chatId", Value: "chat-1"}}
+	c.Set("user_id", uint(1))
+
+	h.SendMessage(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if receivedChatID != "chat-1" || received.Text != "Hello" || len(received.Attachments) != 1 {
+		t.Fatalf("unexpected request for %s: %+v", receivedChatID, received)
+	}
+	if received.Attachments[0].FileName != "deck.pdf" || string(received.Attachments[0].Content) != "%PDF" {
+		t.Fatalf("unexpected attachment: %+v", received.Attachments[0])
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["message_id"] != "msg-1" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
+func TestMessagingHandler_SendMessage_NotConnected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &MessagingHandlerImpl{
+		messagingUsecase: &messagingUsecaseMock{
+			sendMessageFn: func(ctx context.Context, userID uint, accountID, chatID string, req *messaging.SendMessageRequest) (*messaging.SentMessage, error) {
+				return nil, errs.ErrAccountNotConnected
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/acc-1/chats/chat-1/messages", strings.NewReader(`{"text":"Hello"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "chatId", Value: "chat-1"}}
+	c.Set("user_id", uint(1))
+
+	h.SendMessage(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestMessagingHandler_StartChat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "valid", body: `{"attendee_ids":["ACoAA1"],"text":"Hi","inmail":true}`, wantStatus: http.StatusCreated},
+		{name: "no attendees", body: `{"text":"Hi"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *messaging.StartChatRequest
+			h := &MessagingHandlerImpl{
+				messagingUsecase: &messagingUsecaseMock{
+					startChatFn: func(ctx context.Context, userID uint, accountID string, req *messaging.StartChatRequest) (*messaging.SentMessage, error) {
+						received = req
+						return &messaging.SentMessage{ChatID: "chat-9", MessageID: "msg-9"}, nil
+					},
+				},
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/accounts/acc-1/chats", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "acc-1"}}
+			c.Set("user_id", uint(1))
+
+			h.StartChat(c)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusCreated && (received == nil || !received.InMail || received.AttendeeProviderIDs[0] != "ACoAA1") {
+				t.Fatalf("unexpected request: %+v", received)
+			}
+		})
+	}
+}
+
+func TestMessagingHandler_Inbox(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *messaging.InboxRequest
+	h := &MessagingHandlerImpl{
+		messagingUsecase: &messagingUsecaseMock{
+			inboxFn: func(ctx context.Context, userID uint, req *messaging.InboxRequest) (*messaging.InboxPage, error) {
+				received = req
+				return &messaging.InboxPage{
+					Items: []messaging.InboxItem{{Chat: &entity.Chat{ChatID: "chat-1"}, AccountID: "acc-1", Provider: "LINKEDIN"}},
+				}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/inbox?unread=true&provider=LINKEDIN&account_id=acc-1&since=2025-01-01T00:00:00Z&until=2025-02-01T00:00:00%2B01:00", nil)
+	c.Set("user_id", uint(1))
+
+	h.Inbox(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received.Unread == nil || !*received.Unread || received.Provider != "LINKEDIN" || received.AccountID != "acc-1" {
+		t.Fatalf("unexpected request: %+v", received)
+	}
+	if received.Since == nil || received.Until == nil || received.Until.Sub(*received.Since) != 31*24*time.Hour-time.Hour {
+		t.Fatalf("unexpected date range: %v - %v", received.Since, received.Until)
+	}
+
+	var resp struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0]["id"] != "chat-1" || resp.Items[0]["account_id"] != "acc-1" || resp.Items[0]["provider"] != "LINKEDIN" {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestMessagingHandler_SearchMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *messaging.SearchRequest
+	nextOffset := 20
+	h := &MessagingHandlerImpl{
+		messagingUsecase: &messagingUsecaseMock{
+			searchMessagesFn: func(ctx context.Context, userID uint, req *messaging.SearchRequest) (*messaging.SearchPage, error) {
+				received = req
+				return &messaging.SearchPage{
+					Items: []messaging.SearchResult{{
+						Message:   &entity.Message{MessageID: "msg-1", Text: "Senior engineer role"},
+						ChatID:    "chat-1",
+						AccountID: "acc-1",
+						Highlight: "Senior <mark>engineer</mark> role",
+					}},
+					NextOffset: &nextOffset,
+				}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/messages/search?q=%22senior+engin*%22&limit=10&offset=10", nil)
+	c.Set("user_id", uint(1))
+
+	h.SearchMessages(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received.Query != `"senior engin*"` || received.Limit != 10 || received.Offset != 10 {
+		t.Fatalf("unexpected request: %+v", received)
+	}
+
+	var resp struct {
+		Items      []map[string]interface{} `json:"items"`
+		NextOffset int                      `json:"next_offset"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0]["id"] != "msg-1" || resp.Items[0]["chat_id"] != "chat-1" || resp.Items[0]["highlight"] != "Senior <mark>engineer</mark> role" || resp.NextOffset != 20 {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestMessagingHandler_SearchMessages_MissingQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &MessagingHandlerImpl{messagingUsecase: &messagingUsecaseMock{}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/messages/search", nil)
+	c.Set("user_id", uint(1))
+
+	h.SearchMessages(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMessagingHandler_Inbox_InvalidDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &MessagingHandlerImpl{messagingUsecase: &messagingUsecaseMock{}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/inbox?since=yesterday", nil)
+	c.Set("user_id", uint(1))
+
+	h.Inbox(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/usecase/profile"
+)
+
+// ProfileHandler handles user profile lookups through an account
+type ProfileHandler interface {
+	GetProfile(c *gin.Context)
+}
+
+// ProfileHandlerImpl handles user profile lookups through an account
+type ProfileHandlerImpl struct {
+	profileUsecase profile.Usecase
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profileUsecase profile.Usecase) ProfileHandler {
+	return &ProfileHandlerImpl{
+		profileUsecase: profileUsecase,
+	}
+}
+
+// GetProfile gets the profile of a user by provider ID or public identifier, as seen by an account of the current user
+func (h *ProfileHandlerImpl) GetProfile(c *gin.Context) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	userProfile, err := h.profileUsecase.GetProfile(c.Request.Context(), userID, c.Param("id"), c.Param("identifier"))
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Profile retrieved successfully", gin.H{
+		"profile": userProfile,
+	})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/usecase/profile"
+)
+
+type profileUsecaseMock struct {
+	getProfileFn func(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error)
+}
+
+var _ profile.Usecase = (*profileUsecaseMock)(nil)
+
+func (m *profileUsecaseMock) GetProfile(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error) {
+	return m.getProfileFn(ctx, userID, accountID, identifier)
+}
+
+func TestProfileHandler_GetProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedAccountID, receivedIdentifier string
+	h := &ProfileHandlerImpl{
+		profileUsecase: &profileUsecaseMock{
+			getProfileFn: func(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error) {
+				receivedAccountID, receivedIdentifier = accountID, identifier
+				return &entity.Profile{ProviderID: "ACoAA", PublicIdentifier: "jane-doe", Headline: "Engineer", Company: "Acme"}, nil
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/profiles/jane-doe", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "identifier", Value: "jane-doe"}}
+	c.Set("user_id", uint(1))
+
+	h.GetProfile(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if receivedAccountID != "acc-1" || receivedIdentifier != "jane-doe" {
+		t.Fatalf("unexpected lookup %s %s", receivedAccountID, receivedIdentifier)
+	}
+
+	var resp struct {
+		Profile map[string]interface{} `json:"profile"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Profile["provider_id"] != "ACoAA" || resp.Profile["public_identifier"] != "jane-doe" || resp.Profile["company"] != "Acme" {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestProfileHandler_GetProfile_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &ProfileHandlerImpl{
+		profileUsecase: &profileUsecaseMock{
+			getProfileFn: func(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error) {
+				return nil, errs.ErrProfileNotFound
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/profiles/nobody", nil)
+	c.Params = gin.Params{{Key: "id", Value: "acc-1"}, {Key: "identifier", Value: "nobody"}}
+	c.Set("user_id", uint(1))
+
+	h.GetProfile(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
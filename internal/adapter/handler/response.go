@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"unipile-connector/internal/domain/entity"
 	"unipile-connector/internal/domain/errs"
 )
 
@@ -18,6 +19,28 @@ func RespondSuccess(c *gin.Context, status int, message string, payload gin.H) {
 	c.JSON(status, body)
 }
 
+// codedErrorStatuses maps well-known coded errors to a more specific HTTP status than their kind
+var codedErrorStatuses = []struct {
+	err    error
+	status int
+}{
+	{errs.ErrProviderAccountDisconnected, http.StatusConflict},
+	{errs.ErrProviderPermissionDenied, http.StatusForbidden},
+	{errs.ErrProviderRateLimited, http.StatusTooManyRequests},
+	{errs.ErrProviderUnavailable, http.StatusBadGateway},
+	{errs.ErrAccountNotReconnectable, http.StatusConflict},
+	{errs.ErrNoPendingCheckpoint, http.StatusConflict},
+	{errs.ErrCheckpointLocked, http.StatusLocked},
+	{errs.ErrAccountOwnedByAnotherUser, http.StatusConflict},
+	{errs.ErrAccountNotConnected, http.StatusConflict},
+	{errs.ErrAccountNotFound, http.StatusNotFound},
+	{errs.ErrChatNotFound, http.StatusNotFound},
+	{errs.ErrProfileNotFound, http.StatusNotFound},
+	{errs.ErrInvitationNotFound, http.StatusNotFound},
+	{errs.ErrInvitationNotPending, http.StatusConflict},
+	{entity.ErrInvalidAccountStatusTransition, http.StatusConflict},
+}
+
 // RespondError responds with the appropriate error code and message
 func RespondError(c *gin.Context, err error) {
 	if err == nil {
@@ -27,6 +50,12 @@ func RespondError(c *gin.Context, err error) {
 
 	var codedErr *errs.CodedError
 	if errors.As(err, &codedErr) {
+		for _, mapped := range codedErrorStatuses {
+			if errors.Is(err, mapped.err) {
+				c.AbortWithStatusJSON(mapped.status, err.(*errs.CodedError))
+				return
+			}
+		}
 		switch codedErr.Kind {
 		case errs.ValidationErrorKind:
 			c.AbortWithStatusJSON(http.StatusBadRequest, err.(*errs.CodedError))
@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"unipile-connector/internal/domain/entity"
 	"unipile-connector/internal/domain/errs"
 )
 
@@ -62,6 +63,31 @@ func TestRespondError_WithCodedErrorKinds(t *testing.T) {
 	}
 }
 
+func TestRespondError_WithProviderErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{errs.ErrInvalidProviderCredentials, http.StatusBadRequest},
+		{errs.ErrProviderAccountDisconnected, http.StatusConflict},
+		{errs.ErrProviderPermissionDenied, http.StatusForbidden},
+		{errs.ErrProviderRateLimited, http.StatusTooManyRequests},
+		{errs.ErrProviderUnavailable, http.StatusBadGateway},
+		{errs.ErrAccountNotReconnectable, http.StatusConflict},
+		{errs.ErrNoPendingCheckpoint, http.StatusConflict},
+		{errs.ErrCheckpointLocked, http.StatusLocked},
+		{errs.WrapBusinessError(entity.ErrInvalidAccountStatusTransition, "Invalid account status transition"), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		gin.SetMode(gin.TestMode)
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		RespondError(c, tt.err)
+		require.Equal(t, tt.expected, rec.Code, tt.err.Error())
+	}
+}
+
 func TestRespondError_WithGenericError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	rec := httptest.NewRecorder()
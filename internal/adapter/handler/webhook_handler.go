@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/usecase/account"
+	"unipile-connector/internal/usecase/invitation"
+	"unipile-connector/internal/usecase/messaging"
+)
+
+// WebhookHandler handles webhook calls from Unipile
+type WebhookHandler interface {
+	UnipileWebhook(c *gin.Context)
+	HostedAuthCallback(c *gin.Context)
+}
+
+// WebhookHandlerImpl handles webhook calls from Unipile
+type WebhookHandlerImpl struct {
+	accountUsecase    account.Usecase
+	messagingUsecase  messaging.Usecase
+	invitationUsecase invitation.Usecase
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(accountUsecase account.Usecase, messagingUsecase messaging.Usecase, invitationUsecase invitation.Usecase) WebhookHandler {
+	return &WebhookHandlerImpl{
+		accountUsecase:    accountUsecase,
+		messagingUsecase:  messagingUsecase,
+		invitationUsecase: invitationUsecase,
+	}
+}
+
+// UnipileWebhookRequest represents a Unipile webhook payload.
+// Account status events are nested under AccountStatus, messaging and users events are flat and carry an event name.
+type UnipileWebhookRequest struct {
+	AccountStatus *UnipileAccountStatus `json:"AccountStatus"`
+
+	Event       string                `json:"event"`
+	AccountID   string                `json:"account_id"`
+	AccountInfo *UnipileAccountInfo   `json:"account_info"`
+	ChatID      string                `json:"chat_id"`
+	MessageID   string                `json:"message_id"`
+	Message     string                `json:"message"`
+	Timestamp   string                `json:"timestamp"`
+	Sender      *UnipileMessageSender `json:"sender"`
+	Attachments json.RawMessage       `json:"attachments"`
+
+	// New relation of the account, sent by the users webhook once an invitation is accepted
+	UserProviderID       string `json:"user_provider_id"`
+	UserPublicIdentifier string `json:"user_public_identifier"`
+	UserFullName         string `json:"user_full_name"`
+}
+
+// UnipileAccountInfo represents the account of a Unipile messaging event
+type UnipileAccountInfo struct {
+	Type   string `json:"type"`
+	UserID string `json:"user_id"` // Provider ID of the account user
+}
+
+// UnipileMessageSender represents the sender of the message of a Unipile messaging event
+type UnipileMessageSender struct {
+	AttendeeID         string `json:"attendee_id"`
+	AttendeeName       string `json:"attendee_name"`
+	AttendeeProviderID string `json:"attendee_provider_id"`
+}
+
+// UnipileAccountStatus represents the account status event of a Unipile webhook
+type UnipileAccountStatus struct {
+	AccountID   string `json:"account_id"`
+	AccountType string `json:"account_type"`
+	Message     string `json:"message"`
+}
+
+// UnipileWebhook handles Unipile webhook events
+func (h *WebhookHandlerImpl) UnipileWebhook(c *gin.Context) {
+	var req UnipileWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid webhook payload"))
+		return
+	}
+
+	if req.AccountStatus == nil && req.Event == invitation.RelationEventNew {
+		h.relationEvent(c, &req)
+		return
+	}
+
+	if req.AccountStatus == nil && req.Event != "" {
+		h.messageEvent(c, &req)
+		return
+	}
+
+	if req.AccountStatus == nil {
+		// Acknowledge events we do not handle, so Unipile does not retry them
+		RespondSuccess(c, http.StatusOK, "Webhook event ignored", nil)
+		return
+	}
+
+	if req.AccountStatus.AccountID == "" || req.AccountStatus.Message == "" {
+		RespondError(c, errs.WrapValidationError(errors.New("account_id and message are required"), "Invalid webhook payload"))
+		return
+	}
+
+	entityAccount, err := h.accountUsecase.HandleAccountStatusEvent(c.Request.Context(), &account.AccountStatusEvent{
+		AccountID:   req.AccountStatus.AccountID,
+		AccountType: req.AccountStatus.AccountType,
+		Message:     req.AccountStatus.Message,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if entityAccount == nil {
+		RespondSuccess(c, http.StatusOK, "Webhook event ignored", nil)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Webhook event processed", nil)
+}
+
+// messageEvent applies a Unipile messaging event to the synced chats
+func (h *WebhookHandlerImpl) messageEvent(c *gin.Context, req *UnipileWebhookRequest) {
+	// Reactions, read receipts and other events do not carry a message, acknowledge them so Unipile does not retry them
+	if !messaging.IsMessageEvent(req.Event) {
+		RespondSuccess(c, http.StatusOK, "Webhook event ignored", nil)
+		return
+	}
+	if req.AccountID == "" || req.ChatID == "" || req.MessageID == "" {
+		RespondError(c, errs.WrapValidationError(errors.New("account_id, chat_id and message_id are required"), "Invalid webhook payload"))
+		return
+	}
+
+	event := &messaging.MessageEvent{
+		Event:       req.Event,
+		AccountID:   req.AccountID,
+		ChatID:      req.ChatID,
+		MessageID:   req.MessageID,
+		Text:        req.Message,
+		Timestamp:   req.Timestamp,
+		Attachments: req.Attachments,
+	}
+	if req.AccountInfo != nil {
+		event.AccountUserID = req.AccountInfo.UserID
+	}
+	if req.Sender != nil {
+		event.SenderAttendeeID = req.Sender.AttendeeID
+		event.SenderProviderID = req.Sender.AttendeeProviderID
+	}
+
+	message, err := h.messagingUsecase.HandleMessageEvent(c.Request.Context(), event)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if message == nil {
+		RespondSuccess(c, http.StatusOK, "Webhook event ignored", nil)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Webhook event processed", nil)
+}
+
+// relationEvent marks the invitations accepted by a new relation of the account
+func (h *WebhookHandlerImpl) relationEvent(c *gin.Context, req *UnipileWebhookRequest) {
+	if req.AccountID == "" || req.UserProviderID == "" {
+		RespondError(c, errs.WrapValidationError(errors.New("account_id and user_provider_id are required"), "Invalid webhook payload"))
+		return
+	}
+
+	accepted, err := h.invitationUsecase.HandleRelationEvent(c.Request.Context(), &invitation.RelationEvent{
+		AccountID:            req.AccountID,
+		UserProviderID:       req.UserProviderID,
+		UserPublicIdentifier: req.UserPublicIdentifier,
+		UserFullName:         req.UserFullName,
+	})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	if accepted == 0 {
+		RespondSuccess(c, http.StatusOK, "Webhook event ignored", nil)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Webhook event processed", nil)
+}
+
+// HostedAuthCallbackRequest represents the payload Unipile sends to the notify URL of a hosted auth link
+type HostedAuthCallbackRequest struct {
+	Status    string `json:"status" binding:"required"`
+	AccountID string `json:"account_id" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+}
+
+// HostedAuthCallback handles the notification Unipile sends once an account is connected through the hosted auth wizard.
+// The call is authenticated with the signed state carried in the query string.
+func (h *WebhookHandlerImpl) HostedAuthCallback(c *gin.Context) {
+	state := c.Query("state")
+	if state == "" {
+		RespondUnauthorized(c, errs.ErrInvalidHostedAuthState)
+		return
+	}
+
+	var req HostedAuthCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, errs.WrapValidationError(err, "Invalid hosted auth payload"))
+		return
+	}
+
+	entityAccount, err := h.accountUsecase.HandleHostedAuthNotification(c.Request.Context(), state, &account.HostedAuthNotification{
+		Status:    req.Status,
+		AccountID: req.AccountID,
+		Name:      req.Name,
+	})
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidHostedAuthState) {
+			RespondUnauthorized(c, err)
+			return
+		}
+		RespondError(c, err)
+		return
+	}
+
+	if entityAccount == nil {
+		RespondSuccess(c, http.StatusOK, "Hosted auth notification ignored", nil)
+		return
+	}
+
+	RespondSuccess(c, http.StatusOK, "Hosted auth notification processed", nil)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	accountusecase "unipile-connector/internal/usecase/account"
+	invitationusecase "unipile-connector/internal/usecase/invitation"
+	messagingusecase "unipile-connector/internal/usecase/messaging"
+)
+
+func TestWebhookHandler_UnipileWebhook_AccountStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *accountusecase.AccountStatusEvent
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			handleAccountStatusEventFn: func(ctx context.Context, event *accountusecase.AccountStatusEvent) (*entity.Account, error) {
+				received = event
+				return &entity.Account{AccountID: event.AccountID, CurrentStatus: "OK"}, nil
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{"AccountStatus":{"account_id":"acc-1","account_type":"LINKEDIN","message":"OK"}}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.UnipileWebhook(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if received == nil {
+		t.Fatal("expected usecase to be called")
+	}
+	if received.AccountID != "acc-1" || received.AccountType != "LINKEDIN" || received.Message != "OK" {
+		t.Fatalf("unexpected event: %+v", received)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["message"] != "Webhook event processed" {
+		t.Fatalf("unexpected message: %v", resp["message"])
+	}
+}
+
+func TestWebhookHandler_UnipileWebhook_IgnoresOtherEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			handleAccountStatusEventFn: func(ctx context.Context, event *accountusecase.AccountStatusEvent) (*entity.Account, error) {
+				t.Fatal("expected usecase not to be called")
+				return nil, nil
+			},
+		},
+		messagingUsecase: &messagingUsecaseMock{
+			handleMessageEventFn: func(ctx context.Context, event *messagingusecase.MessageEvent) (*entity.Message, error) {
+				t.Fatal("expected usecase not to be called")
+				return nil, nil
+			},
+		},
+	}
+
+	for _, payload := range []string{
+		`{"event":"message_reaction","account_id":"acc-1","chat_id":"chat-1","message_id":"msg-1"}`,
+		// Read receipts carry no message
+		`{"event":"message_read","account_id":"acc-1","chat_id":"chat-1"}`,
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		h.UnipileWebhook(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d for %s, got %d", http.StatusOK, payload, w.Code)
+		}
+	}
+}
+
+func TestWebhookHandler_UnipileWebhook_MessageEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *messagingusecase.MessageEvent
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{},
+		messagingUsecase: &messagingUsecaseMock{
+			handleMessageEventFn: func(ctx context.Context, event *messagingusecase.MessageEvent) (*entity.Message, error) {
+				received = event
+				return &entity.Message{MessageID: event.MessageID}, nil
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{
+		"event":"message_received",
+		"account_id":"acc-1",
+		"account_type":"LINKEDIN",
+		"account_info":{"type":"LINKEDIN","user_id":"ACoSelf"},
+		"chat_id":"chat-1",
+		"message_id":"msg-1",
+		"message":"Hello",
+		"timestamp":"2025-01-02T10:00:00.000Z",
+		"sender":{"attendee_id":"att-1","attendee_name":"Jane Doe","attendee_provider_id":"ACoJane"},
+		"attachments":[]
+	}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.UnipileWebhook(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received == nil || received.ChatID != "chat-1" || received.Text != "Hello" || received.AccountUserID != "ACoSelf" || received.SenderProviderID != "ACoJane" {
+		t.Fatalf("unexpected event: %+v", received)
+	}
+}
+
+func TestWebhookHandler_UnipileWebhook_RelationEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received *invitationusecase.RelationEvent
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{},
+		invitationUsecase: &invitationUsecaseMock{
+			handleRelationEventFn: func(ctx context.Context, event *invitationusecase.RelationEvent) (int64, error) {
+				received = event
+				return 1, nil
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{
+		"event":"new_relation",
+		"account_id":"acc-1",
+		"account_type":"LINKEDIN",
+		"webhook_name":"relations",
+		"user_full_name":"Jane Doe",
+		"user_provider_id":"ACoAA",
+		"user_public_identifier":"jane-doe",
+		"user_profile_url":"https://www.linkedin.com/in/jane-doe"
+	}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.UnipileWebhook(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received == nil || received.AccountID != "acc-1" || received.UserProviderID != "ACoAA" || received.UserPublicIdentifier != "jane-doe" {
+		t.Fatalf("unexpected event: %+v", received)
+	}
+}
+
+func TestWebhookHandler_UnipileWebhook_InvalidPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &WebhookHandlerImpl{accountUsecase: &accountUsecaseMock{}}
+
+	body := bytes.NewBufferString(`{"AccountStatus":{"account_type":"LINKEDIN"}}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.UnipileWebhook(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp errs.CodedError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Kind != errs.ValidationErrorKind {
+		t.Fatalf("expected validation error kind, got %s", resp.Kind)
+	}
+}
+
+func TestWebhookHandler_HostedAuthCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var receivedState string
+	var received *accountusecase.HostedAuthNotification
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			handleHostedAuthFn: func(ctx context.Context, state string, notification *accountusecase.HostedAuthNotification) (*entity.Account, error) {
+				receivedState = state
+				received = notification
+				return &entity.Account{AccountID: notification.AccountID, CurrentStatus: "OK"}, nil
+			},
+		},
+	}
+
+	body := bytes.NewBufferString(`{"status":"CREATION_SUCCESS","account_id":"acc-1","name":"42"}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/unipile/hosted-auth?state=signed-state", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.HostedAuthCallback(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if receivedState != "signed-state" {
+		t.Fatalf("unexpected state: %s", receivedState)
+	}
+	if received == nil || received.AccountID != "acc-1" || received.Name != "42" || received.Status != "CREATION_SUCCESS" {
+		t.Fatalf("unexpected notification: %+v", received)
+	}
+}
+
+func TestWebhookHandler_HostedAuthCallback_InvalidState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &WebhookHandlerImpl{
+		accountUsecase: &accountUsecaseMock{
+			handleHostedAuthFn: func(ctx context.Context, state string, notification *accountusecase.HostedAuthNotification) (*entity.Account, error) {
+				return nil, errs.ErrInvalidHostedAuthState
+			},
+		},
+	}
+
+	for _, target := range []string{"/api/v1/webhooks/unipile/hosted-auth", "/api/v1/webhooks/unipile/hosted-auth?state=forged"} {
+		body := bytes.NewBufferString(`{"status":"CREATION_SUCCESS","account_id":"acc-1","name":"42"}`)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, target, body)
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		h.HostedAuthCallback(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected status %d, got %d", target, http.StatusUnauthorized, w.Code)
+		}
+	}
+}
@@ -4,12 +4,13 @@ import "github.com/gin-gonic/gin"
 
 // Middlewares handles all middlewares
 type Middlewares struct {
-	CORSMiddleware      gin.HandlerFunc
-	JWTMiddleware       gin.HandlerFunc
-	RateLimitMiddleware gin.HandlerFunc
+	CORSMiddleware        gin.HandlerFunc
+	JWTMiddleware         gin.HandlerFunc
+	RateLimitMiddleware   gin.HandlerFunc
+	WebhookAuthMiddleware gin.HandlerFunc
 }
 
 // NewMiddlewares creates a new middleware
-func NewMiddlewares(corsMiddleware, jwtMiddleware, rateLimitMiddleware gin.HandlerFunc) *Middlewares {
-	return &Middlewares{CORSMiddleware: corsMiddleware, JWTMiddleware: jwtMiddleware, RateLimitMiddleware: rateLimitMiddleware}
+func NewMiddlewares(corsMiddleware, jwtMiddleware, rateLimitMiddleware, webhookAuthMiddleware gin.HandlerFunc) *Middlewares {
+	return &Middlewares{CORSMiddleware: corsMiddleware, JWTMiddleware: jwtMiddleware, RateLimitMiddleware: rateLimitMiddleware, WebhookAuthMiddleware: webhookAuthMiddleware}
 }
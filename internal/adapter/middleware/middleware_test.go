@@ -11,10 +11,12 @@ func TestNewMiddlewares(t *testing.T) {
 	cors := gin.HandlerFunc(func(c *gin.Context) {})
 	jwt := gin.HandlerFunc(func(c *gin.Context) {})
 	rate := gin.HandlerFunc(func(c *gin.Context) {})
+	webhook := gin.HandlerFunc(func(c *gin.Context) {})
 
-	m := NewMiddlewares(cors, jwt, rate)
+	m := NewMiddlewares(cors, jwt, rate, webhook)
 	require.NotNil(t, m)
 	require.IsType(t, cors, m.CORSMiddleware)
 	require.IsType(t, jwt, m.JWTMiddleware)
 	require.IsType(t, rate, m.RateLimitMiddleware)
+	require.IsType(t, webhook, m.WebhookAuthMiddleware)
 }
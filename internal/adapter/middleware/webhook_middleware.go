@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"unipile-connector/internal/domain/errs"
+)
+
+// WebhookSecretHeader is the header Unipile is configured to send the shared webhook secret in
+const WebhookSecretHeader = "Unipile-Auth"
+
+// WebhookAuthMiddleware authenticates webhook calls with a shared secret header.
+// All calls are rejected when no secret is configured.
+func WebhookAuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(WebhookSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			err := errs.WrapValidationError(errors.New("invalid webhook secret"), "Invalid webhook secret")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, err.(*errs.CodedError))
+			return
+		}
+
+		c.Next()
+	}
+}
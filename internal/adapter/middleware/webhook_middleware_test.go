@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		secret   string
+		header   string
+		expected int
+	}{
+		{name: "valid secret", secret: "s3cret", header: "s3cret", expected: http.StatusOK},
+		{name: "wrong secret", secret: "s3cret", header: "nope", expected: http.StatusUnauthorized},
+		{name: "missing header", secret: "s3cret", header: "", expected: http.StatusUnauthorized},
+		{name: "no secret configured", secret: "", header: "", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(WebhookAuthMiddleware(tt.secret))
+			engine.POST("/webhook", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/webhook", nil)
+			if tt.header != "" {
+				req.Header.Set(WebhookSecretHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -38,6 +38,29 @@ func (r *accountRepo) GetByUserID(ctx context.Context, userID uint) ([]*entity.A
 	return accounts, nil
 }
 
+func (r *accountRepo) List(ctx context.Context) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	err := r.db.WithContext(ctx).Order("id").Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *accountRepo) GetByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	var account entity.Account
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND account_id = ?", userID, accountID).
+		First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
 func (r *accountRepo) GetByUserIDAndAccountIDForUpdate(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
 	var account entity.Account
 	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
@@ -53,18 +76,51 @@ func (r *accountRepo) GetByUserIDAndAccountIDForUpdate(ctx context.Context, user
 	return &account, nil
 }
 
-func (r *accountRepo) GetWithStatus(ctx context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
+func (r *accountRepo) GetByAccountIDForUpdate(ctx context.Context, accountID string) (*entity.Account, error) {
+	var account entity.Account
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id = ?", accountID).
+		First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListPendingWithExpiredCheckpoint lists PENDING accounts without any checkpoint still valid at now
+func (r *accountRepo) ListPendingWithExpiredCheckpoint(ctx context.Context, now time.Time) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	err := r.db.WithContext(ctx).
+		Where("current_status = ?", entity.AccountStatusPending).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM account_status_histories ash
+			WHERE ash.account_id = accounts.id AND ash.checkpoint_expires_at > ? AND ash.deleted_at IS NULL
+		)`, now).
+		Order("id").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *accountRepo) GetWithStatus(ctx context.Context, userID uint, accountID string, checkpoints ...entity.CheckpointType) (*entity.AccountWithStatus, error) {
 	var accountWithStatus entity.AccountWithStatus
+	// The latest unexpired checkpoint of the account, filtered in the subquery so other accounts cannot take its place
 	err := r.db.WithContext(ctx).
 		Select("accounts.*, ash.status as current_status, ash.checkpoint, ash.checkpoint_expires_at").
 		Table("accounts").
 		Joins(`INNER JOIN (
 			SELECT account_id, status, checkpoint, checkpoint_expires_at
 			FROM account_status_histories ash
-			WHERE checkpoint_expires_at > ? AND checkpoint = ? AND deleted_at IS NULL
+			WHERE checkpoint_expires_at > ? AND checkpoint IN ? AND deleted_at IS NULL
+				AND account_id IN (SELECT id FROM accounts WHERE user_id = ? AND account_id = ?)
 			ORDER BY ash.created_at DESC
 			LIMIT 1
-		) ash ON accounts.id = ash.account_id`, time.Now(), checkpoint).
+		) ash ON accounts.id = ash.account_id`, time.Now(), checkpoints, userID, accountID).
 		Where("accounts.user_id = ? AND accounts.account_id = ? AND accounts.deleted_at IS NULL", userID, accountID).
 		First(&accountWithStatus).Error
 	if err != nil {
@@ -77,9 +133,57 @@ func (r *accountRepo) GetWithStatus(ctx context.Context, userID uint, accountID,
 }
 
 func (r *accountRepo) Update(ctx context.Context, account *entity.Account) error {
-	return r.db.WithContext(ctx).Save(account).Error
+	// Status histories are append-only and written with CreateStatusHistory, only their checkpoint metadata is updated
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(account).Error
 }
 
 func (r *accountRepo) DeleteByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) error {
 	return r.db.WithContext(ctx).Where("user_id = ? AND account_id = ?", userID, accountID).Delete(&entity.Account{}).Error
 }
+
+func (r *accountRepo) CreateStatusHistory(ctx context.Context, history *entity.AccountStatusHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// UpdateCheckpointMetadata updates the checkpoint metadata of a status history row, its only mutable column
+func (r *accountRepo) UpdateCheckpointMetadata(ctx context.Context, history *entity.AccountStatusHistory) error {
+	return r.db.WithContext(ctx).Model(history).Update("checkpoint_metadata", history.CheckpointMetadata).Error
+}
+
+func (r *accountRepo) GetCheckpointAttempt(ctx context.Context, statusHistoryID uint) (*entity.CheckpointAttempt, error) {
+	var attempt entity.CheckpointAttempt
+	err := r.db.WithContext(ctx).Where("status_history_id = ?", statusHistoryID).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrCheckpointAttemptNotFound
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *accountRepo) SaveCheckpointAttempt(ctx context.Context, attempt *entity.CheckpointAttempt) error {
+	return r.db.WithContext(ctx).Save(attempt).Error
+}
+
+func (r *accountRepo) CreateHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *accountRepo) GetHostedAuthStateForUpdate(ctx context.Context, nonce string) (*entity.HostedAuthState, error) {
+	var state entity.HostedAuthState
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("nonce = ?", nonce).
+		First(&state).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrHostedAuthStateNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *accountRepo) SaveHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error {
+	return r.db.WithContext(ctx).Save(state).Error
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -32,6 +33,14 @@ func TestAccountRepository_CreateAndRetrieve(t *testing.T) {
 	got, err := repo.GetByUserIDAndAccountIDForUpdate(ctx, 1, "acc-123")
 	require.NoError(t, err)
 	require.Equal(t, account.AccountID, got.AccountID)
+
+	got, err = repo.GetByUserIDAndAccountID(ctx, 1, "acc-123")
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+
+	// Accounts of another user are not found
+	_, err = repo.GetByUserIDAndAccountID(ctx, 2, "acc-123")
+	require.ErrorIs(t, err, repository.ErrAccountNotFound)
 }
 
 func TestAccountRepository_GetByUserIDAndAccountID_NotFound(t *testing.T) {
@@ -60,7 +69,7 @@ func TestAccountRepository_UpdateAndDelete(t *testing.T) {
 
 	updated, err := repo.GetByUserIDAndAccountIDForUpdate(ctx, 2, "acc-456")
 	require.NoError(t, err)
-	require.Equal(t, "OK", updated.CurrentStatus)
+	require.Equal(t, entity.AccountStatusOK, updated.CurrentStatus)
 
 	require.NoError(t, repo.DeleteByUserIDAndAccountID(ctx, 2, "acc-456"))
 
@@ -68,3 +77,215 @@ func TestAccountRepository_UpdateAndDelete(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, accounts, 0)
 }
+
+func TestAccountRepository_ListAndCreateStatusHistory(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	first := &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-1", CurrentStatus: "OK"}
+	second := &entity.Account{UserID: 2, Provider: "LINKEDIN", AccountID: "acc-2", CurrentStatus: "OK"}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	accounts, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, "acc-1", accounts[0].AccountID)
+	require.Equal(t, "acc-2", accounts[1].AccountID)
+
+	require.NoError(t, repo.CreateStatusHistory(ctx, &entity.AccountStatusHistory{
+		AccountID:      first.ID,
+		PreviousStatus: "OK",
+		Status:         "CREDENTIALS",
+		Cause:          entity.StatusCauseWebhook,
+		Payload:        []byte(`{"message":"CREDENTIALS"}`),
+	}))
+
+	fetched, err := repo.GetByUserIDAndAccountIDForUpdate(ctx, 1, "acc-1")
+	require.NoError(t, err)
+	require.Len(t, fetched.AccountStatusHistories, 1)
+	require.Equal(t, entity.AccountStatusCredentials, fetched.AccountStatusHistories[0].Status)
+	require.Equal(t, entity.AccountStatusOK, fetched.AccountStatusHistories[0].PreviousStatus)
+	require.Equal(t, entity.StatusCauseWebhook, fetched.AccountStatusHistories[0].Cause)
+	require.JSONEq(t, `{"message":"CREDENTIALS"}`, string(fetched.AccountStatusHistories[0].Payload))
+}
+
+func TestAccountRepository_UpdateCheckpointMetadata(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	account := &entity.Account{UserID: 1, Provider: "WHATSAPP", AccountID: "wa-1", CurrentStatus: "PENDING"}
+	require.NoError(t, repo.Create(ctx, account))
+	history := &entity.AccountStatusHistory{
+		AccountID:          account.ID,
+		Status:             "PENDING",
+		Checkpoint:         entity.CheckpointTypeQRCode,
+		CheckpointMetadata: []byte(`{"type":"QRCODE","qrcode":"2@old"}`),
+	}
+	require.NoError(t, repo.CreateStatusHistory(ctx, history))
+
+	history.CheckpointMetadata = []byte(`{"type":"QRCODE","qrcode":"2@old","refreshed_at":"2025-01-01T00:00:00Z"}`)
+	history.Status = "OK"
+	require.NoError(t, repo.UpdateCheckpointMetadata(ctx, history))
+
+	fetched, err := repo.GetByUserIDAndAccountIDForUpdate(ctx, 1, "wa-1")
+	require.NoError(t, err)
+	require.Len(t, fetched.AccountStatusHistories, 1)
+	require.JSONEq(t, `{"type":"QRCODE","qrcode":"2@old","refreshed_at":"2025-01-01T00:00:00Z"}`, string(fetched.AccountStatusHistories[0].CheckpointMetadata))
+	require.Equal(t, entity.AccountStatusPending, fetched.AccountStatusHistories[0].Status)
+}
+
+func TestAccountRepository_GetByAccountIDForUpdate(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	account := &entity.Account{UserID: 3, Provider: "LINKEDIN", AccountID: "acc-789", CurrentStatus: "PENDING"}
+	require.NoError(t, repo.Create(ctx, account))
+
+	got, err := repo.GetByAccountIDForUpdate(ctx, "acc-789")
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+	require.Equal(t, uint(3), got.UserID)
+
+	_, err = repo.GetByAccountIDForUpdate(ctx, "missing")
+	require.ErrorIs(t, err, repository.ErrAccountNotFound)
+}
+
+func TestAccountRepository_RejectsInvalidStatus(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	account := &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-1", CurrentStatus: entity.AccountStatusDeleted}
+	require.NoError(t, repo.Create(ctx, account))
+
+	err := repo.CreateStatusHistory(ctx, &entity.AccountStatusHistory{
+		AccountID:      account.ID,
+		PreviousStatus: entity.AccountStatusDeleted,
+		Status:         entity.AccountStatusOK,
+	})
+	require.ErrorIs(t, err, entity.ErrInvalidAccountStatusTransition)
+
+	account.CurrentStatus = "CREATION_SUCCESS"
+	require.ErrorIs(t, repo.Update(ctx, account), entity.ErrInvalidAccountStatusTransition)
+}
+
+func TestAccountRepository_ListPendingWithExpiredCheckpoint(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	newPending := func(accountID string, expiresAt ...time.Time) {
+		account := &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: accountID, CurrentStatus: entity.AccountStatusPending}
+		for _, expiry := range expiresAt {
+			account.AccountStatusHistories = append(account.AccountStatusHistories, entity.AccountStatusHistory{
+				Checkpoint:          entity.CheckpointTypeOTP,
+				CheckpointExpiresAt: expiry,
+				Status:              entity.AccountStatusPending,
+			})
+		}
+		require.NoError(t, repo.Create(ctx, account))
+	}
+
+	newPending("acc-expired", now.Add(-time.Minute))
+	newPending("acc-live", now.Add(time.Minute))
+	// A newer checkpoint replaces the expired one
+	newPending("acc-chained", now.Add(-time.Minute), now.Add(time.Minute))
+	require.NoError(t, repo.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-ok", CurrentStatus: entity.AccountStatusOK}))
+
+	accounts, err := repo.ListPendingWithExpiredCheckpoint(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, "acc-expired", accounts[0].AccountID)
+}
+
+func TestAccountRepository_CheckpointAttempt(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	account := &entity.Account{
+		UserID: 1, Provider: "LINKEDIN", AccountID: "acc-1", CurrentStatus: entity.AccountStatusPending,
+		AccountStatusHistories: []entity.AccountStatusHistory{
+			{Checkpoint: entity.CheckpointType2FA, CheckpointExpiresAt: time.Now().Add(time.Minute), Status: entity.AccountStatusPending},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, account))
+	historyID := account.AccountStatusHistories[0].ID
+
+	_, err := repo.GetCheckpointAttempt(ctx, historyID)
+	require.ErrorIs(t, err, repository.ErrCheckpointAttemptNotFound)
+
+	attempt := &entity.CheckpointAttempt{AccountID: account.ID, StatusHistoryID: historyID, Checkpoint: entity.CheckpointType2FA, FailedAttempts: 1}
+	require.NoError(t, repo.SaveCheckpointAttempt(ctx, attempt))
+
+	lockedAt := time.Now()
+	attempt.FailedAttempts = 2
+	attempt.LockedAt = &lockedAt
+	require.NoError(t, repo.SaveCheckpointAttempt(ctx, attempt))
+
+	stored, err := repo.GetCheckpointAttempt(ctx, historyID)
+	require.NoError(t, err)
+	require.Equal(t, attempt.ID, stored.ID)
+	require.Equal(t, 2, stored.FailedAttempts)
+	require.True(t, stored.IsLocked())
+}
+
+func TestAccountRepository_HostedAuthState(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetHostedAuthStateForUpdate(ctx, "nonce-1")
+	require.ErrorIs(t, err, repository.ErrHostedAuthStateNotFound)
+
+	state := &entity.HostedAuthState{Nonce: "nonce-1", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.CreateHostedAuthState(ctx, state))
+	require.Error(t, repo.CreateHostedAuthState(ctx, &entity.HostedAuthState{Nonce: "nonce-1", UserID: 2, ExpiresAt: time.Now()}))
+
+	usedAt := time.Now()
+	state.AccountID = "acc-1"
+	state.UsedAt = &usedAt
+	require.NoError(t, repo.SaveHostedAuthState(ctx, state))
+
+	stored, err := repo.GetHostedAuthStateForUpdate(ctx, "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, uint(1), stored.UserID)
+	require.Equal(t, "acc-1", stored.AccountID)
+	require.True(t, stored.IsUsed())
+}
+
+func TestAccountRepository_GetWithStatus(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	account := &entity.Account{
+		UserID: 1, Provider: "WHATSAPP", AccountID: "wa-1", CurrentStatus: entity.AccountStatusPending,
+		AccountStatusHistories: []entity.AccountStatusHistory{
+			{Checkpoint: entity.CheckpointTypeQRCode, CheckpointExpiresAt: expiresAt, Status: entity.AccountStatusPending},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, account))
+	// A newer checkpoint of another account must not hide the one of the account
+	require.NoError(t, repo.Create(ctx, &entity.Account{
+		UserID: 2, Provider: "LINKEDIN", AccountID: "li-1", CurrentStatus: entity.AccountStatusPending,
+		AccountStatusHistories: []entity.AccountStatusHistory{
+			{Checkpoint: entity.CheckpointTypeInAppValidation, CheckpointExpiresAt: expiresAt, Status: entity.AccountStatusPending},
+		},
+	}))
+
+	got, err := repo.GetWithStatus(ctx, 1, "wa-1", entity.CheckpointTypeInAppValidation, entity.CheckpointTypeQRCode)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+	require.Equal(t, entity.CheckpointTypeQRCode, got.Checkpoint)
+	require.Equal(t, entity.AccountStatusPending, got.CurrentStatus)
+
+	_, err = repo.GetWithStatus(ctx, 1, "wa-1", entity.CheckpointTypeInAppValidation)
+	require.ErrorIs(t, err, repository.ErrAccountNotFound)
+}
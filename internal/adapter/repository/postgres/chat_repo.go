@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+// chatRepo implements ChatRepository interface
+type chatRepo struct {
+	db *gorm.DB
+}
+
+// NewChatRepository creates a new chat repository
+func NewChatRepository(db *gorm.DB) repository.ChatRepository {
+	return &chatRepo{db: db}
+}
+
+func (r *chatRepo) Upsert(ctx context.Context, chat *entity.Chat) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "chat_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"provider_id", "attendee_provider_id", "name", "type", "subject", "content_type",
+			"unread_count", "archived", "read_only", "attendee_names", "last_message_at", "updated_at",
+		}),
+	}).Create(chat).Error
+}
+
+func (r *chatRepo) GetByAccountIDAndChatID(ctx context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+	var chat entity.Chat
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND chat_id = ?", accountID, chatID).
+		First(&chat).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// List lists the chats matching the filter, newest activity first
+func (r *chatRepo) List(ctx context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+	query := r.db.WithContext(ctx).Where("account_id IN ?", filter.AccountIDs)
+	if filter.Unread != nil {
+		if *filter.Unread {
+			query = query.Where("unread_count > 0")
+		} else {
+			query = query.Where("unread_count = 0")
+		}
+	}
+	if filter.Since != nil {
+		query = query.Where("last_message_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("last_message_at < ?", *filter.Until)
+	}
+	if filter.After != nil {
+		query = query.Where("(last_message_at < ? OR (last_message_at = ? AND id < ?))",
+			filter.After.LastMessageAt, filter.After.LastMessageAt, filter.After.ID)
+	}
+
+	var chats []*entity.Chat
+	err := query.Order("last_message_at DESC, id DESC").Limit(filter.Limit).Find(&chats).Error
+	if err != nil {
+		return nil, err
+	}
+	return chats, nil
+}
+
+func (r *chatRepo) Update(ctx context.Context, chat *entity.Chat) error {
+	return r.db.WithContext(ctx).Save(chat).Error
+}
+
+func (r *chatRepo) GetSyncState(ctx context.Context, accountID uint) (*entity.ChatSyncState, error) {
+	var state entity.ChatSyncState
+	err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&state).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrChatSyncStateNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *chatRepo) SaveSyncState(ctx context.Context, state *entity.ChatSyncState) error {
+	return r.db.WithContext(ctx).Save(state).Error
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+func TestChatRepository_UpsertAndGet(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewChatRepository(db)
+	ctx := context.Background()
+
+	chat := &entity.Chat{AccountID: 1, ChatID: "chat-1", Name: "Jane", UnreadCount: 1, LastMessageAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, repo.Upsert(ctx, chat))
+	require.NotZero(t, chat.ID)
+
+	// A second upsert updates the stored chat and returns its ID
+	updated := &entity.Chat{AccountID: 1, ChatID: "chat-1", Name: "Jane Doe", LastMessageAt: chat.LastMessageAt}
+	require.NoError(t, repo.Upsert(ctx, updated))
+	require.Equal(t, chat.ID, updated.ID)
+
+	got, err := repo.GetByAccountIDAndChatID(ctx, 1, "chat-1")
+	require.NoError(t, err)
+	require.Equal(t, chat.ID, got.ID)
+	require.Equal(t, "Jane Doe", got.Name)
+	require.Zero(t, got.UnreadCount)
+
+	// Chats of another account are not found
+	_, err = repo.GetByAccountIDAndChatID(ctx, 2, "chat-1")
+	require.ErrorIs(t, err, repository.ErrChatNotFound)
+}
+
+func TestChatRepository_List(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewChatRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, chat := range []*entity.Chat{
+		{AccountID: 1, ChatID: "oldest", LastMessageAt: base},
+		{AccountID: 1, ChatID: "unread", UnreadCount: 3, LastMessageAt: base.Add(time.Hour)},
+		{AccountID: 2, ChatID: "other-account", LastMessageAt: base.Add(2 * time.Hour)},
+		{AccountID: 1, ChatID: "newest", LastMessageAt: base.Add(3 * time.Hour)},
+	} {
+		require.NoError(t, repo.Upsert(ctx, chat), i)
+	}
+
+	page, err := repo.List(ctx, &repository.ChatFilter{AccountIDs: []uint{1}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "newest", page[0].ChatID)
+	require.Equal(t, "unread", page[1].ChatID)
+
+	last := page[1]
+	page, err = repo.List(ctx, &repository.ChatFilter{
+		AccountIDs: []uint{1},
+		After:      &repository.ChatPosition{LastMessageAt: last.LastMessageAt, ID: last.ID},
+		Limit:      2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "oldest", page[0].ChatID)
+
+	unread := true
+	page, err = repo.List(ctx, &repository.ChatFilter{AccountIDs: []uint{1, 2}, Unread: &unread, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "unread", page[0].ChatID)
+
+	since, until := base.Add(time.Hour), base.Add(3*time.Hour)
+	page, err = repo.List(ctx, &repository.ChatFilter{AccountIDs: []uint{1, 2}, Since: &since, Until: &until, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "other-account", page[0].ChatID)
+	require.Equal(t, "unread", page[1].ChatID)
+}
+
+func TestChatRepository_SyncState(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewChatRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetSyncState(ctx, 1)
+	require.ErrorIs(t, err, repository.ErrChatSyncStateNotFound)
+
+	syncedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveSyncState(ctx, &entity.ChatSyncState{AccountID: 1, Cursor: "page-2", LastSyncedAt: &syncedAt}))
+
+	state, err := repo.GetSyncState(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "page-2", state.Cursor)
+	require.False(t, state.IsBackfilled())
+	require.True(t, syncedAt.Equal(*state.LastSyncedAt))
+}
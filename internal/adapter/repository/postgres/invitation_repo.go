@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+// invitationRepo implements InvitationRepository interface
+type invitationRepo struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository(db *gorm.DB) repository.InvitationRepository {
+	return &invitationRepo{db: db}
+}
+
+func (r *invitationRepo) Upsert(ctx context.Context, invitation *entity.Invitation) error {
+	columns := []string{"provider_id", "public_identifier", "name", "headline", "message", "updated_at"}
+	if !invitation.SentAt.IsZero() {
+		columns = append(columns, "sent_at")
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "invitation_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}, clause.Returning{}).Create(invitation).Error
+}
+
+func (r *invitationRepo) GetByAccountIDAndInvitationID(ctx context.Context, accountID uint, invitationID string) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND invitation_id = ?", accountID, invitationID).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepo) Update(ctx context.Context, invitation *entity.Invitation) error {
+	return r.db.WithContext(ctx).Save(invitation).Error
+}
+
+func (r *invitationRepo) AcceptPending(ctx context.Context, accountID uint, providerID string, acceptedAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Invitation{}).
+		Where("account_id = ? AND provider_id = ? AND status = ?", accountID, providerID, entity.InvitationStatusPending).
+		Updates(map[string]any{
+			"status":      entity.InvitationStatusAccepted,
+			"accepted_at": acceptedAt,
+			"updated_at":  time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *invitationRepo) ClosePendingExcept(ctx context.Context, accountID uint, direction entity.InvitationDirection, invitationIDs []string, listedAt time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Invitation{}).
+		Where("account_id = ? AND direction = ? AND status = ? AND created_at < ?", accountID, direction, entity.InvitationStatusPending, listedAt)
+	if len(invitationIDs) > 0 {
+		query = query.Where("invitation_id NOT IN ?", invitationIDs)
+	}
+	result := query.Updates(map[string]any{
+		"status":     entity.InvitationStatusClosed,
+		"closed_at":  listedAt,
+		"updated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
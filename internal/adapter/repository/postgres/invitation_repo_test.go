@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+func TestInvitationRepository_UpsertKeepsStatus(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewInvitationRepository(db)
+	ctx := context.Background()
+
+	sentAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	invitation := &entity.Invitation{AccountID: 1, InvitationID: "inv-1", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoAA", Name: "Jane", SentAt: sentAt}
+	require.NoError(t, repo.Upsert(ctx, invitation))
+	require.NotZero(t, invitation.ID)
+
+	withdrawnAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	invitation.Withdraw(withdrawnAt)
+	require.NoError(t, repo.Update(ctx, invitation))
+
+	// Listing the invitation again without its sent time refreshes it and reports the stored status and sent time
+	listed := &entity.Invitation{AccountID: 1, InvitationID: "inv-1", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoAA", Name: "Jane Doe"}
+	require.NoError(t, repo.Upsert(ctx, listed))
+	require.Equal(t, invitation.ID, listed.ID)
+	require.Equal(t, entity.InvitationStatusWithdrawn, listed.Status)
+	require.Equal(t, "Jane Doe", listed.Name)
+	require.True(t, listed.SentAt.Equal(sentAt))
+
+	got, err := repo.GetByAccountIDAndInvitationID(ctx, 1, "inv-1")
+	require.NoError(t, err)
+	require.Equal(t, entity.InvitationStatusWithdrawn, got.Status)
+	require.NotNil(t, got.WithdrawnAt)
+	require.True(t, got.WithdrawnAt.Equal(withdrawnAt))
+
+	_, err = repo.GetByAccountIDAndInvitationID(ctx, 2, "inv-1")
+	require.ErrorIs(t, err, repository.ErrInvitationNotFound)
+}
+
+func TestInvitationRepository_AcceptPending(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewInvitationRepository(db)
+	ctx := context.Background()
+
+	for _, invitation := range []*entity.Invitation{
+		{AccountID: 1, InvitationID: "pending", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoAA"},
+		{AccountID: 1, InvitationID: "withdrawn", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusWithdrawn, ProviderID: "ACoAA"},
+		{AccountID: 1, InvitationID: "received", Direction: entity.InvitationDirectionReceived, Status: entity.InvitationStatusPending, ProviderID: "ACoAA"},
+		{AccountID: 2, InvitationID: "other-account", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoAA"},
+	} {
+		require.NoError(t, repo.Upsert(ctx, invitation))
+	}
+
+	acceptedAt := time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)
+	accepted, err := repo.AcceptPending(ctx, 1, "ACoAA", acceptedAt)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, accepted)
+
+	// A new relation accepts both the invitation sent to the user and the one received from them
+	for _, id := range []string{"pending", "received"} {
+		got, err := repo.GetByAccountIDAndInvitationID(ctx, 1, id)
+		require.NoError(t, err)
+		require.Equal(t, entity.InvitationStatusAccepted, got.Status, id)
+		require.NotNil(t, got.AcceptedAt, id)
+	}
+	got, err := repo.GetByAccountIDAndInvitationID(ctx, 1, "withdrawn")
+	require.NoError(t, err)
+	require.Nil(t, got.AcceptedAt)
+}
+
+func TestInvitationRepository_ClosePendingExcept(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewInvitationRepository(db)
+	ctx := context.Background()
+
+	for _, invitation := range []*entity.Invitation{
+		{AccountID: 1, InvitationID: "listed", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending},
+		{AccountID: 1, InvitationID: "missing", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending},
+		{AccountID: 1, InvitationID: "accepted", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusAccepted},
+		{AccountID: 1, InvitationID: "received", Direction: entity.InvitationDirectionReceived, Status: entity.InvitationStatusPending},
+		{AccountID: 2, InvitationID: "other-account", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending},
+	} {
+		require.NoError(t, repo.Upsert(ctx, invitation))
+	}
+
+	// Invitations tracked after the listing started are kept
+	closed, err := repo.ClosePendingExcept(ctx, 1, entity.InvitationDirectionSent, []string{"listed"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, closed)
+
+	closed, err = repo.ClosePendingExcept(ctx, 1, entity.InvitationDirectionSent, []string{"listed"}, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, closed)
+
+	got, err := repo.GetByAccountIDAndInvitationID(ctx, 1, "missing")
+	require.NoError(t, err)
+	require.Equal(t, entity.InvitationStatusClosed, got.Status)
+	require.NotNil(t, got.ClosedAt)
+	for id, want := range map[string]entity.InvitationStatus{
+		"listed":   entity.InvitationStatusPending,
+		"accepted": entity.InvitationStatusAccepted,
+		"received": entity.InvitationStatusPending,
+	} {
+		got, err := repo.GetByAccountIDAndInvitationID(ctx, 1, id)
+		require.NoError(t, err)
+		require.Equal(t, want, got.Status, id)
+	}
+
+	// An empty listing closes every pending invitation in the direction
+	closed, err = repo.ClosePendingExcept(ctx, 1, entity.InvitationDirectionReceived, nil, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, closed)
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+// messageRepo implements MessageRepository interface
+type messageRepo struct {
+	db *gorm.DB
+}
+
+// NewMessageRepository creates a new message repository
+func NewMessageRepository(db *gorm.DB) repository.MessageRepository {
+	return &messageRepo{db: db}
+}
+
+func (r *messageRepo) Upsert(ctx context.Context, message *entity.Message) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chat_id"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"provider_id", "sender_id", "sender_attendee_id", "text", "sent_at",
+			"is_sender", "seen", "edited", "deleted", "attachments", "updated_at",
+		}),
+	}).Create(message).Error
+}
+
+func (r *messageRepo) GetByChatIDAndMessageID(ctx context.Context, chatID uint, messageID string) (*entity.Message, error) {
+	var message entity.Message
+	err := r.db.WithContext(ctx).
+		Where("chat_id = ? AND message_id = ?", chatID, messageID).
+		First(&message).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+// List lists the messages of a chat, newest first
+func (r *messageRepo) List(ctx context.Context, filter *repository.MessageFilter) ([]*entity.Message, error) {
+	query := r.db.WithContext(ctx).Where("chat_id = ?", filter.ChatID)
+	if filter.After != nil {
+		query = query.Where("(sent_at < ? OR (sent_at = ? AND id < ?))", filter.After.SentAt, filter.After.SentAt, filter.After.ID)
+	}
+
+	var messages []*entity.Message
+	err := query.Order("sent_at DESC, id DESC").Limit(filter.Limit).Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// searchHeadlineOptions keeps a few short fragments around the matches of a message text
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=20, MinWords=5, FragmentDelimiter=\" ... \""
+
+// Search finds the messages whose text or chat attendee names match the query, best match first.
+// It relies on the Postgres full-text search columns of the 005_message_search migration.
+func (r *messageRepo) Search(ctx context.Context, filter *repository.MessageSearchFilter) ([]*repository.MessageSearchResult, error) {
+	query := tsQuery(filter.Query)
+
+	var results []*repository.MessageSearchResult
+	err := r.db.WithContext(ctx).
+		Table("messages m").
+		Select(`m.*, c.chat_id AS chat_external_id, c.attendee_names,
+			ts_rank(m.search_vector, q.query) + ts_rank(c.search_vector, q.query) AS rank,
+			ts_headline('simple', `+htmlEscaped("m.text")+`, q.query, ?) AS highlight,
+			ts_headline('simple', `+htmlEscaped("c.attendee_names")+`, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS attendee_highlight`,
+			searchHeadlineOptions).
+		Joins("JOIN chats c ON c.id = m.chat_id").
+		Joins("CROSS JOIN to_tsquery('simple', ?) AS q(query)", query).
+		Where("m.account_id IN ?", filter.AccountIDs).
+		Where("m.deleted = ?", false).
+		Where("(m.search_vector @@ q.query OR c.search_vector @@ q.query)").
+		Order("rank DESC, m.sent_at DESC, m.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// htmlEscaped returns the SQL expression of a text column with its HTML special characters escaped.
+// Highlights are built from the escaped text, so the <mark> tags are the only markup they carry.
+func htmlEscaped(column string) string {
+	return `replace(replace(replace(replace(replace(coalesce(` + column + `, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// tsQuery renders a search query in the to_tsquery syntax: terms are ANDed, the words of a phrase follow
+// each other and a prefix term matches any word starting with its last word
+func tsQuery(query repository.SearchQuery) string {
+	terms := make([]string, 0, len(query))
+	for _, term := range query {
+		if len(term.Words) == 0 {
+			continue
+		}
+		words := make([]string, len(term.Words))
+		copy(words, term.Words)
+		if term.Prefix {
+			words[len(words)-1] += ":*"
+		}
+		terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+	}
+	return strings.Join(terms, " & ")
+}
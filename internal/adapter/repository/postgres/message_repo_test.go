@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+func TestMessageRepository_UpsertAndList(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewMessageRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.Upsert(ctx, &entity.Message{
+			AccountID: 1,
+			ChatID:    1,
+			MessageID: []string{"msg-1", "msg-2", "msg-3"}[i],
+			Text:      "Hello",
+			SentAt:    base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	require.NoError(t, repo.Upsert(ctx, &entity.Message{AccountID: 1, ChatID: 1, MessageID: "msg-2", Text: "Hello again", Edited: true, SentAt: base.Add(time.Minute)}))
+	require.NoError(t, repo.Upsert(ctx, &entity.Message{AccountID: 1, ChatID: 2, MessageID: "msg-9", SentAt: base}))
+
+	page, err := repo.List(ctx, &repository.MessageFilter{ChatID: 1, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "msg-3", page[0].MessageID)
+	require.Equal(t, "msg-2", page[1].MessageID)
+	require.Equal(t, "Hello again", page[1].Text)
+	require.True(t, page[1].Edited)
+
+	page, err = repo.List(ctx, &repository.MessageFilter{
+		ChatID: 1,
+		After:  &repository.MessagePosition{SentAt: page[1].SentAt, ID: page[1].ID},
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "msg-1", page[0].MessageID)
+
+	got, err := repo.GetByChatIDAndMessageID(ctx, 2, "msg-9")
+	require.NoError(t, err)
+	require.Equal(t, uint(1), got.AccountID)
+
+	_, err = repo.GetByChatIDAndMessageID(ctx, 1, "msg-9")
+	require.ErrorIs(t, err, repository.ErrMessageNotFound)
+}
+
+func TestHTMLEscaped(t *testing.T) {
+	db := newTestDB(t)
+
+	var escaped string
+	require.NoError(t, db.Raw(`SELECT `+htmlEscaped("text")+` FROM (SELECT ? AS text) AS t`, `<img src=x onerror="alert('hi')"> & more`).Scan(&escaped).Error)
+	require.Equal(t, `&lt;img src=x onerror=&quot;alert(&#39;hi&#39;)&quot;&gt; &amp; more`, escaped)
+
+	require.NoError(t, db.Raw(`SELECT `+htmlEscaped("text")+` FROM (SELECT NULL AS text) AS t`).Scan(&escaped).Error)
+	require.Equal(t, "", escaped)
+}
+
+func TestTSQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query repository.SearchQuery
+		want  string
+	}{
+		{name: "words", query: repository.SearchQuery{{Words: []string{"acme"}}, {Words: []string{"offer"}}}, want: "(acme) & (offer)"},
+		{name: "phrase", query: repository.SearchQuery{{Words: []string{"series", "a"}}}, want: "(series <-> a)"},
+		{name: "prefix", query: repository.SearchQuery{{Words: []string{"recruit"}, Prefix: true}}, want: "(recruit:*)"},
+		{name: "prefix phrase", query: repository.SearchQuery{{Words: []string{"acme", "corp"}, Prefix: true}}, want: "(acme <-> corp:*)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tsQuery(tt.query))
+		})
+	}
+}
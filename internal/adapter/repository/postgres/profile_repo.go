@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+// profileRepo implements ProfileRepository interface
+type profileRepo struct {
+	db *gorm.DB
+}
+
+// NewProfileRepository creates a new profile repository
+func NewProfileRepository(db *gorm.DB) repository.ProfileRepository {
+	return &profileRepo{db: db}
+}
+
+func (r *profileRepo) GetByAccountIDAndIdentifier(ctx context.Context, accountID uint, identifier string) (*entity.Profile, error) {
+	var profile entity.Profile
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND (provider_id = ? OR lower(public_identifier) = lower(?))", accountID, identifier, identifier).
+		Order("fetched_at DESC").
+		First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *profileRepo) Upsert(ctx context.Context, profile *entity.Profile) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "provider_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"provider", "provider_id", "public_identifier", "first_name", "last_name", "headline", "company",
+			"location", "profile_picture_url", "public_profile_url", "network_distance", "fetched_at", "updated_at",
+		}),
+	}).Create(profile).Error
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+)
+
+func TestProfileRepository_UpsertAndGet(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewProfileRepository(db)
+	ctx := context.Background()
+
+	fetchedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	profile := &entity.Profile{AccountID: 1, ProviderID: "ACoAA", PublicIdentifier: "jane-doe", Headline: "Engineer", FetchedAt: fetchedAt}
+	require.NoError(t, repo.Upsert(ctx, profile))
+	require.NotZero(t, profile.ID)
+
+	// A second upsert refreshes the cached profile and returns its ID
+	refreshed := &entity.Profile{AccountID: 1, ProviderID: "ACoAA", PublicIdentifier: "jane-doe", Headline: "Staff Engineer", Company: "Acme", FetchedAt: fetchedAt.Add(time.Hour)}
+	require.NoError(t, repo.Upsert(ctx, refreshed))
+	require.Equal(t, profile.ID, refreshed.ID)
+
+	got, err := repo.GetByAccountIDAndIdentifier(ctx, 1, "jane-doe")
+	require.NoError(t, err)
+	require.Equal(t, "Staff Engineer", got.Headline)
+	require.Equal(t, "Acme", got.Company)
+	require.True(t, got.FetchedAt.Equal(fetchedAt.Add(time.Hour)))
+
+	// The same profile is found by provider ID and by public identifier in another case
+	for _, identifier := range []string{"ACoAA", "Jane-Doe"} {
+		got, err = repo.GetByAccountIDAndIdentifier(ctx, 1, identifier)
+		require.NoError(t, err)
+		require.Equal(t, profile.ID, got.ID)
+	}
+
+	// Profiles are cached per account
+	_, err = repo.GetByAccountIDAndIdentifier(ctx, 2, "jane-doe")
+	require.ErrorIs(t, err, repository.ErrProfileNotFound)
+}
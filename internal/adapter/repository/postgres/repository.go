@@ -8,15 +8,18 @@ import (
 
 // GetRepositories returns initialized repositories
 func GetRepositories(db *gorm.DB) repository.Repositories {
-	userRepo := NewUserRepository(db)
-	accountRepo := NewAccountRepository(db)
-	txRepo := NewTxRepository(db, &repository.Repositories{
-		User:    userRepo,
-		Account: accountRepo,
-	})
+	return newRepositories(db)
+}
+
+// newRepositories returns repositories bound to the given database handle, which may be a transaction
+func newRepositories(db *gorm.DB) repository.Repositories {
 	return repository.Repositories{
-		Tx:      txRepo,
-		User:    userRepo,
-		Account: accountRepo,
+		Tx:         NewTxRepository(db),
+		User:       NewUserRepository(db),
+		Account:    NewAccountRepository(db),
+		Chat:       NewChatRepository(db),
+		Message:    NewMessageRepository(db),
+		Profile:    NewProfileRepository(db),
+		Invitation: NewInvitationRepository(db),
 	}
 }
@@ -16,6 +16,6 @@ import (
 func newTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:memdb_%d?mode=memory&cache=shared", time.Now().UnixNano())), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
 	require.NoError(t, err)
-	require.NoError(t, db.AutoMigrate(&entity.User{}, &entity.Account{}, &entity.AccountStatusHistory{}))
+	require.NoError(t, db.AutoMigrate(&entity.User{}, &entity.Account{}, &entity.AccountStatusHistory{}, &entity.CheckpointAttempt{}, &entity.HostedAuthState{}, &entity.Chat{}, &entity.Message{}, &entity.ChatSyncState{}, &entity.Profile{}, &entity.Invitation{}))
 	return db
 }
@@ -8,17 +8,19 @@ import (
 )
 
 type txRepository struct {
-	db    *gorm.DB
-	repos *repository.Repositories
+	db *gorm.DB
 }
 
 // NewTxRepository creates a new transaction repository
-func NewTxRepository(db *gorm.DB, repos *repository.Repositories) repository.TxRepository {
-	return &txRepository{db: db, repos: repos}
+func NewTxRepository(db *gorm.DB) repository.TxRepository {
+	return &txRepository{db: db}
 }
 
+// Do runs fn in a transaction with repositories bound to it.
+// Calling Do on the repositories passed to fn nests the transaction using a savepoint.
 func (r *txRepository) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		return fn(r.repos)
+		repos := newRepositories(tx)
+		return fn(&repos)
 	})
 }
@@ -2,29 +2,98 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"unipile-connector/internal/domain/entity"
 	"unipile-connector/internal/domain/repository"
 )
 
 func TestTxRepository_Do(t *testing.T) {
 	db := newTestDB(t)
-	repos := repository.Repositories{}
-	txRepo := NewTxRepository(db, &repos)
+	txRepo := NewTxRepository(db)
 
 	ctx := context.Background()
 
 	var called bool
 	err := txRepo.Do(ctx, func(r *repository.Repositories) error {
 		called = true
-		if r != &repos {
-			t.Fatalf("unexpected repositories pointer")
-		}
-		return nil
+		require.NotNil(t, r.Tx)
+		require.NotNil(t, r.User)
+		require.NotNil(t, r.Account)
+		return r.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-commit", CurrentStatus: "OK"})
 	})
 
 	require.NoError(t, err)
 	require.True(t, called)
+
+	accounts, err := NewAccountRepository(db).GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+}
+
+func TestTxRepository_Do_RollbackUndoesWrites(t *testing.T) {
+	db := newTestDB(t)
+	repos := GetRepositories(db)
+	ctx := context.Background()
+
+	require.NoError(t, repos.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-1", CurrentStatus: "PENDING"}))
+
+	errRollback := errors.New("rollback")
+	err := repos.Tx.Do(ctx, func(r *repository.Repositories) error {
+		account, err := r.Account.GetByUserIDAndAccountIDForUpdate(ctx, 1, "acc-1")
+		require.NoError(t, err)
+
+		account.CurrentStatus = "OK"
+		require.NoError(t, r.Account.Update(ctx, account))
+		require.NoError(t, r.Account.CreateStatusHistory(ctx, &entity.AccountStatusHistory{AccountID: account.ID, Status: "OK"}))
+		require.NoError(t, r.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-2", CurrentStatus: "OK"}))
+
+		// Writes are visible inside the transaction
+		accounts, err := r.Account.GetByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, accounts, 2)
+
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	accounts, err := repos.Account.GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, entity.AccountStatusPending, accounts[0].CurrentStatus)
+	require.Empty(t, accounts[0].AccountStatusHistories)
+}
+
+func TestTxRepository_Do_NestedSavepoint(t *testing.T) {
+	db := newTestDB(t)
+	repos := GetRepositories(db)
+	ctx := context.Background()
+
+	errInner := errors.New("inner rollback")
+	err := repos.Tx.Do(ctx, func(r *repository.Repositories) error {
+		require.NoError(t, r.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-outer", CurrentStatus: "OK"}))
+
+		// The inner transaction rolls back to its savepoint only
+		err := r.Tx.Do(ctx, func(inner *repository.Repositories) error {
+			require.NoError(t, inner.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-inner", CurrentStatus: "OK"}))
+			return errInner
+		})
+		require.ErrorIs(t, err, errInner)
+
+		return r.Tx.Do(ctx, func(inner *repository.Repositories) error {
+			return inner.Account.Create(ctx, &entity.Account{UserID: 1, Provider: "LINKEDIN", AccountID: "acc-inner-committed", CurrentStatus: "OK"})
+		})
+	})
+	require.NoError(t, err)
+
+	accounts, err := repos.Account.GetByUserID(ctx, 1)
+	require.NoError(t, err)
+	accountIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, account.AccountID)
+	}
+	require.ElementsMatch(t, []string{"acc-outer", "acc-inner-committed"}, accountIDs)
 }
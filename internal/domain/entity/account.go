@@ -2,6 +2,7 @@ package entity
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,7 +15,7 @@ type Account struct {
 	UserID uint `json:"user_id"`
 	User   User `json:"user" gorm:"foreignKey:UserID"`
 
-	CurrentStatus          string                 `json:"current_status"`
+	CurrentStatus          AccountStatus          `json:"current_status"`
 	AccountStatusHistories []AccountStatusHistory `json:"account_status_histories" gorm:"foreignKey:AccountID"`
 
 	Provider  string         `json:"provider"`   // e.g., "LINKEDIN"
@@ -24,28 +25,105 @@ type Account struct {
 	DeletedAt gorm.DeletedAt `json:"-"`
 }
 
+// TransitionTo moves the account to status, rejecting moves the transition table does not allow
+func (a *Account) TransitionTo(status AccountStatus) error {
+	if err := a.CurrentStatus.ValidateTransition(status); err != nil {
+		return err
+	}
+	a.CurrentStatus = status
+	return nil
+}
+
+// BeforeSave rejects writing an account with an unknown status
+func (a *Account) BeforeSave(tx *gorm.DB) error {
+	if !a.CurrentStatus.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidAccountStatusTransition, a.CurrentStatus)
+	}
+	return nil
+}
+
+// Causes of an account status transition
+const (
+	StatusCauseUserAction     = "USER_ACTION"
+	StatusCauseReconnect      = "RECONNECT"
+	StatusCauseResend         = "CHECKPOINT_RESEND"
+	StatusCauseLocked         = "CHECKPOINT_LOCKED"
+	StatusCauseQRCodeRefresh  = "QRCODE_REFRESH"
+	StatusCauseWebhook        = "WEBHOOK"
+	StatusCauseHostedAuth     = "HOSTED_AUTH"
+	StatusCauseReconciliation = "RECONCILIATION"
+	StatusCauseExpiry         = "CHECKPOINT_EXPIRY"
+)
+
 // AccountStatusHistory represents the status history of an account
 type AccountStatusHistory struct {
 	ID        uint `json:"id"`
 	AccountID uint `json:"account_id"`
 
-	Checkpoint          string          `json:"checkpoint"`
+	Checkpoint          CheckpointType  `json:"checkpoint"`
 	CheckpointMetadata  json.RawMessage `json:"checkpoint_metadata"`
 	CheckpointExpiresAt time.Time       `json:"checkpoint_expires_at"`
 
-	// System status: OK, PENDING
-	// Unipile status: OK, ERROR/STOPPED, CREDENTIALS, CONNECTING, DELETED, CREATION_SUCCESS, RECONNECTED, SYNC_SUCCESS
-	Status string `json:"status"`
+	// Status before the transition, empty for the first row of an account
+	PreviousStatus AccountStatus `json:"previous_status"`
+	Status         AccountStatus `json:"status"`
+	// What triggered the transition, one of the StatusCause constants
+	Cause string `json:"cause"`
+	// Unipile payload received with the transition, if any
+	Payload json.RawMessage `json:"payload"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-"`
 }
 
+// BeforeCreate rejects history rows recording a transition the transition table does not allow
+func (h *AccountStatusHistory) BeforeCreate(tx *gorm.DB) error {
+	return h.PreviousStatus.ValidateTransition(h.Status)
+}
+
+// CheckpointAttempt counts the failed code submissions for the checkpoint recorded by a status history row
+type CheckpointAttempt struct {
+	ID              uint           `json:"id"`
+	AccountID       uint           `json:"account_id"`
+	StatusHistoryID uint           `json:"status_history_id" gorm:"uniqueIndex"`
+	Checkpoint      CheckpointType `json:"checkpoint"`
+	FailedAttempts  int            `json:"failed_attempts"`
+	// Set once the checkpoint no longer accepts codes
+	LockedAt *time.Time `json:"locked_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsLocked reports whether the checkpoint no longer accepts codes
+func (a *CheckpointAttempt) IsLocked() bool {
+	return a.LockedAt != nil
+}
+
+// HostedAuthState records the state issued with a hosted auth link, so the link attaches a single account
+type HostedAuthState struct {
+	ID     uint   `json:"id"`
+	Nonce  string `json:"nonce" gorm:"uniqueIndex"`
+	UserID uint   `json:"user_id"`
+	// Unipile account attached through the link, empty until the state is used
+	AccountID string     `json:"account_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsUsed reports whether an account was already attached with the state
+func (s *HostedAuthState) IsUsed() bool {
+	return s.UsedAt != nil
+}
+
 // AccountWithStatus represents an account with its current status
 type AccountWithStatus struct {
 	Account
-	CurrentStatus       string    `json:"current_status"`
-	Checkpoint          string    `json:"checkpoint"`
-	CheckpointExpiresAt time.Time `json:"checkpoint_expires_at"`
+	CurrentStatus       AccountStatus  `json:"current_status"`
+	Checkpoint          CheckpointType `json:"checkpoint"`
+	CheckpointExpiresAt time.Time      `json:"checkpoint_expires_at"`
 }
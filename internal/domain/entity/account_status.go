@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AccountStatus represents the status of an account
+type AccountStatus string
+
+// Account statuses. PENDING is a system status for accounts waiting on a checkpoint and
+// EXPIRED for accounts whose checkpoint expired before it was solved,
+// the others mirror the Unipile account statuses.
+// Unipile CREATION_SUCCESS, RECONNECTED and SYNC_SUCCESS events all map to OK.
+const (
+	AccountStatusPending     AccountStatus = "PENDING"
+	AccountStatusOK          AccountStatus = "OK"
+	AccountStatusConnecting  AccountStatus = "CONNECTING"
+	AccountStatusCredentials AccountStatus = "CREDENTIALS"
+	AccountStatusError       AccountStatus = "ERROR"
+	AccountStatusStopped     AccountStatus = "STOPPED"
+	AccountStatusDeleted     AccountStatus = "DELETED"
+	AccountStatusExpired     AccountStatus = "EXPIRED"
+)
+
+// accountStatusTransitions lists the statuses each status may move to.
+// The empty status is the state of an account that is not stored yet.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	"":                       {AccountStatusPending, AccountStatusOK},
+	AccountStatusPending:     {AccountStatusOK, AccountStatusCredentials, AccountStatusError, AccountStatusStopped, AccountStatusDeleted, AccountStatusExpired},
+	AccountStatusOK:          {AccountStatusConnecting, AccountStatusCredentials, AccountStatusError, AccountStatusStopped, AccountStatusDeleted},
+	AccountStatusConnecting:  {AccountStatusOK, AccountStatusCredentials, AccountStatusError, AccountStatusStopped, AccountStatusDeleted},
+	AccountStatusCredentials: {AccountStatusPending, AccountStatusOK, AccountStatusConnecting, AccountStatusError, AccountStatusStopped, AccountStatusDeleted},
+	AccountStatusError:       {AccountStatusPending, AccountStatusOK, AccountStatusConnecting, AccountStatusCredentials, AccountStatusStopped, AccountStatusDeleted},
+	AccountStatusStopped:     {AccountStatusPending, AccountStatusOK, AccountStatusConnecting, AccountStatusCredentials, AccountStatusError, AccountStatusDeleted},
+	AccountStatusDeleted:     {},
+	AccountStatusExpired:     {},
+}
+
+// ErrInvalidAccountStatusTransition is returned when an account is moved to a status it cannot reach
+var ErrInvalidAccountStatusTransition = errors.New("invalid account status transition")
+
+// IsValid reports whether the status is a known account status
+func (s AccountStatus) IsValid() bool {
+	if s == "" {
+		return false
+	}
+	_, ok := accountStatusTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the account can no longer leave the status
+func (s AccountStatus) IsTerminal() bool {
+	return s.IsValid() && len(accountStatusTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether the status may move to next.
+// Staying in the same non-terminal status is allowed, e.g. when a new checkpoint is issued.
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	if !next.IsValid() {
+		return false
+	}
+	if s == next {
+		return !s.IsTerminal()
+	}
+	for _, allowed := range accountStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns ErrInvalidAccountStatusTransition when the status may not move to next
+func (s AccountStatus) ValidateTransition(next AccountStatus) error {
+	if !s.CanTransitionTo(next) {
+		from := s
+		if from == "" {
+			from = "NEW"
+		}
+		return fmt.Errorf("%w: %s to %s", ErrInvalidAccountStatusTransition, from, next)
+	}
+	return nil
+}
+
+// CheckpointType represents the type of an authentication checkpoint
+type CheckpointType string
+
+// Checkpoint types returned by Unipile
+const (
+	CheckpointType2FA             CheckpointType = "2FA"
+	CheckpointTypeOTP             CheckpointType = "OTP"
+	CheckpointTypeInAppValidation CheckpointType = "IN_APP_VALIDATION"
+	CheckpointTypeCaptcha         CheckpointType = "CAPTCHA"
+	CheckpointTypePhoneRegister   CheckpointType = "PHONE_REGISTER"
+	CheckpointTypeQRCode          CheckpointType = "QRCODE"
+)
+
+// IsValid reports whether the checkpoint type is a known checkpoint type
+func (t CheckpointType) IsValid() bool {
+	switch t {
+	case CheckpointType2FA, CheckpointTypeOTP, CheckpointTypeInAppValidation, CheckpointTypeCaptcha, CheckpointTypePhoneRegister, CheckpointTypeQRCode:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAccountStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from AccountStatus
+		to   AccountStatus
+		want bool
+	}{
+		{"", AccountStatusPending, true},
+		{"", AccountStatusOK, true},
+		{"", AccountStatusCredentials, false},
+		{AccountStatusPending, AccountStatusOK, true},
+		{AccountStatusPending, AccountStatusPending, true},
+		{AccountStatusOK, AccountStatusCredentials, true},
+		{AccountStatusOK, AccountStatusPending, false},
+		{AccountStatusCredentials, AccountStatusPending, true},
+		{AccountStatusError, AccountStatusOK, true},
+		{AccountStatusOK, AccountStatusDeleted, true},
+		{AccountStatusDeleted, AccountStatusOK, false},
+		{AccountStatusDeleted, AccountStatusDeleted, false},
+		{AccountStatusPending, AccountStatusExpired, true},
+		{AccountStatusOK, AccountStatusExpired, false},
+		{AccountStatusExpired, AccountStatusPending, false},
+		{AccountStatusOK, AccountStatus("CREATION_SUCCESS"), false},
+		{AccountStatusOK, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%q -> %q: expected %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestAccountStatus_IsTerminal(t *testing.T) {
+	if !AccountStatusDeleted.IsTerminal() || !AccountStatusExpired.IsTerminal() {
+		t.Fatal("expected DELETED and EXPIRED to be terminal")
+	}
+	if AccountStatusOK.IsTerminal() || AccountStatus("UNKNOWN").IsTerminal() {
+		t.Fatal("expected OK and unknown statuses not to be terminal")
+	}
+}
+
+func TestAccount_TransitionTo(t *testing.T) {
+	account := &Account{CurrentStatus: AccountStatusDeleted}
+
+	err := account.TransitionTo(AccountStatusOK)
+	if !errors.Is(err, ErrInvalidAccountStatusTransition) {
+		t.Fatalf("expected ErrInvalidAccountStatusTransition, got %v", err)
+	}
+	if account.CurrentStatus != AccountStatusDeleted {
+		t.Fatalf("expected status to stay DELETED, got %s", account.CurrentStatus)
+	}
+
+	account.CurrentStatus = AccountStatusPending
+	if err := account.TransitionTo(AccountStatusOK); err != nil {
+		t.Fatalf("expected PENDING -> OK to be allowed, got %v", err)
+	}
+	if account.CurrentStatus != AccountStatusOK {
+		t.Fatalf("expected status OK, got %s", account.CurrentStatus)
+	}
+}
+
+func TestCheckpointType_IsValid(t *testing.T) {
+	if !CheckpointTypeInAppValidation.IsValid() {
+		t.Fatal("expected IN_APP_VALIDATION to be valid")
+	}
+	if CheckpointType("UNKNOWN").IsValid() {
+		t.Fatal("expected unknown checkpoint type to be invalid")
+	}
+}
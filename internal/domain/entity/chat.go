@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Chat represents a Unipile chat of an account, synced locally
+type Chat struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"-" gorm:"uniqueIndex:idx_chats_account_id_chat_id"`
+
+	ChatID             string `json:"id" gorm:"uniqueIndex:idx_chats_account_id_chat_id"` // Chat ID from Unipile
+	ProviderID         string `json:"provider_id"`
+	AttendeeProviderID string `json:"attendee_provider_id"`
+	Name               string `json:"name"`
+	Type               int    `json:"type"` // 0 one-to-one, 1 group, 2 channel
+	Subject            string `json:"subject"`
+	ContentType        string `json:"content_type"` // "inmail", "sponsored", "linkedin_offer"
+	UnreadCount        int    `json:"unread_count"`
+	Archived           bool   `json:"archived"`
+	ReadOnly           bool   `json:"read_only"`
+	// Names of the attendees other than the account user, comma separated
+	AttendeeNames string `json:"attendee_names"`
+	// Last activity of the chat, zero when Unipile did not report any
+	LastMessageAt time.Time `json:"last_message_at"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Message represents a message of a chat, synced locally
+type Message struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"-"`
+	ChatID    uint `json:"-" gorm:"uniqueIndex:idx_messages_chat_id_message_id"`
+
+	MessageID        string    `json:"id" gorm:"uniqueIndex:idx_messages_chat_id_message_id"` // Message ID from Unipile
+	ProviderID       string    `json:"provider_id"`
+	SenderID         string    `json:"sender_id"`
+	SenderAttendeeID string    `json:"sender_attendee_id"`
+	Text             string    `json:"text"`
+	SentAt           time.Time `json:"sent_at"`
+	IsSender         bool      `json:"is_sender"` // The account sent the message
+	Seen             bool      `json:"seen"`
+	Edited           bool      `json:"edited"`
+	Deleted          bool      `json:"deleted"`
+	// Unipile attachments of the message, as returned by the API
+	Attachments json.RawMessage `json:"attachments"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// ChatSyncState tracks the sync of the chats and messages of an account from Unipile
+type ChatSyncState struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"-" gorm:"uniqueIndex"`
+
+	// Cursor of the next page of chats to backfill, empty before the first page and once the backfill is done
+	Cursor string `json:"-"`
+	// Set once the whole history of the account was backfilled
+	BackfilledAt *time.Time `json:"backfilled_at"`
+	// Start of the last successful sync, later syncs only fetch what changed after it
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// IsBackfilled reports whether the whole history of the account was synced
+func (s *ChatSyncState) IsBackfilled() bool {
+	return s.BackfilledAt != nil
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// InvitationStatus represents the status of a connection invitation
+type InvitationStatus string
+
+// Invitation statuses. Provider users may ignore an invitation, so it stays pending until accepted or withdrawn,
+// or closed once Unipile no longer lists it without telling how it ended.
+const (
+	InvitationStatusPending   InvitationStatus = "pending"
+	InvitationStatusAccepted  InvitationStatus = "accepted"
+	InvitationStatusWithdrawn InvitationStatus = "withdrawn"
+	InvitationStatusClosed    InvitationStatus = "closed"
+)
+
+// InvitationDirection tells whether an invitation was sent or received by the account
+type InvitationDirection string
+
+// Invitation directions
+const (
+	InvitationDirectionSent     InvitationDirection = "sent"
+	InvitationDirectionReceived InvitationDirection = "received"
+)
+
+// Invitation represents a LinkedIn connection invitation sent or received by an account, tracked locally
+type Invitation struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"-" gorm:"uniqueIndex:idx_invitations_account_id_invitation_id"`
+
+	InvitationID string              `json:"id" gorm:"uniqueIndex:idx_invitations_account_id_invitation_id"` // Invitation ID from Unipile
+	Direction    InvitationDirection `json:"direction"`
+	Status       InvitationStatus    `json:"status"`
+	// The other user of the invitation, the invited user of a sent invitation or the inviter of a received one
+	ProviderID       string `json:"provider_id"`
+	PublicIdentifier string `json:"public_identifier"`
+	Name             string `json:"name"`
+	Headline         string `json:"headline"`
+	Message          string `json:"message"` // Note sent with the invitation
+	// Time the invitation was sent, zero when Unipile did not report it
+	SentAt      time.Time  `json:"sent_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+	WithdrawnAt *time.Time `json:"withdrawn_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// IsPending reports whether the invitation is neither accepted, withdrawn nor closed
+func (i *Invitation) IsPending() bool {
+	return i.Status == InvitationStatusPending
+}
+
+// Withdraw marks the invitation as withdrawn at the given time
+func (i *Invitation) Withdraw(at time.Time) {
+	i.Status = InvitationStatusWithdrawn
+	i.WithdrawnAt = &at
+}
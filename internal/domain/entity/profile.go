@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Profile represents the profile of a provider user looked up through an account, cached locally.
+// A profile is cached once per account, whether it was looked up by provider ID or by public identifier.
+type Profile struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"-" gorm:"uniqueIndex:idx_profiles_account_id_provider_id"`
+
+	Provider          string `json:"provider"`
+	ProviderID        string `json:"provider_id" gorm:"uniqueIndex:idx_profiles_account_id_provider_id"`
+	PublicIdentifier  string `json:"public_identifier"`
+	FirstName         string `json:"first_name"`
+	LastName          string `json:"last_name"`
+	Headline          string `json:"headline"`
+	Company           string `json:"company"` // Company of the current position
+	Location          string `json:"location"`
+	ProfilePictureURL string `json:"profile_picture_url"`
+	PublicProfileURL  string `json:"public_profile_url"`
+	NetworkDistance   string `json:"network_distance"` // Distance from the account, e.g. "FIRST_DEGREE"
+	// Last time the profile was fetched from Unipile
+	FetchedAt time.Time `json:"fetched_at"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// IsFresh reports whether the profile was fetched less than ttl ago
+func (p *Profile) IsFresh(now time.Time, ttl time.Duration) bool {
+	return now.Sub(p.FetchedAt) < ttl
+}
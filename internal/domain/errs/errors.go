@@ -7,4 +7,25 @@ var (
 	ErrUserNotAuthenticated           = WrapValidationError(errors.New("user not authenticated"), "User not authenticated")
 	ErrInvalidUserID                  = WrapValidationError(errors.New("invalid user ID"), "Invalid user ID")
 	ErrInvalidCodeOrExpiredCheckpoint = WrapValidationError(errors.New("invalid code or expired checkpoint"), "Invalid code or expired checkpoint")
+	ErrInvalidProviderCredentials     = WrapValidationError(errors.New("invalid provider credentials"), "Invalid or expired provider credentials")
+	ErrUnsupportedProvider            = WrapValidationError(errors.New("unsupported provider"), "Unsupported provider")
+	ErrInvalidHostedAuthState         = WrapValidationError(errors.New("invalid hosted auth state"), "Invalid or expired hosted auth state")
+	ErrAccountNotFound                = WrapValidationError(errors.New("account not found"), "Account not found")
+	ErrChatNotFound                   = WrapValidationError(errors.New("chat not found"), "Chat not found")
+	ErrProfileNotFound                = WrapValidationError(errors.New("profile not found"), "Profile not found")
+	ErrInvitationNotFound             = WrapValidationError(errors.New("invitation not found"), "Invitation not found")
+)
+
+// Business errors
+var (
+	ErrProviderAccountDisconnected = WrapBusinessError(errors.New("provider account disconnected"), "Provider account is disconnected, please reconnect it")
+	ErrProviderPermissionDenied    = WrapBusinessError(errors.New("provider permission denied"), "Insufficient permissions for this provider action")
+	ErrProviderRateLimited         = WrapBusinessError(errors.New("provider rate limited"), "Too many requests to the provider, please retry later")
+	ErrProviderUnavailable         = WrapBusinessError(errors.New("provider unavailable"), "Provider is temporarily unavailable, please retry later")
+	ErrAccountNotReconnectable     = WrapBusinessError(errors.New("account not reconnectable"), "Only disconnected accounts can be reconnected")
+	ErrNoPendingCheckpoint         = WrapBusinessError(errors.New("no pending checkpoint"), "Account has no pending checkpoint")
+	ErrCheckpointLocked            = WrapBusinessError(errors.New("checkpoint locked"), "Too many invalid codes, request a new code to continue")
+	ErrAccountNotConnected         = WrapBusinessError(errors.New("account not connected"), "Account is not connected, reconnect it before sending")
+	ErrAccountOwnedByAnotherUser   = WrapBusinessError(errors.New("account owned by another user"), "Account is already connected by another user")
+	ErrInvitationNotPending        = WrapBusinessError(errors.New("invitation not pending"), "Only pending sent invitations can be withdrawn")
 )
@@ -22,3 +22,16 @@ func TestSentinelErrors(t *testing.T) {
 	require.Equal(t, ValidationErrorKind, ce.Kind)
 	require.Equal(t, "Invalid code or expired checkpoint", ce.Message)
 }
+
+func TestProviderSentinelErrors(t *testing.T) {
+	var ce *CodedError
+
+	require.True(t, errors.As(ErrInvalidProviderCredentials, &ce))
+	require.Equal(t, ValidationErrorKind, ce.Kind)
+
+	for _, err := range []error{ErrProviderAccountDisconnected, ErrProviderPermissionDenied, ErrProviderRateLimited, ErrProviderUnavailable} {
+		require.True(t, errors.As(err, &ce))
+		require.Equal(t, BusinessErrorKind, ce.Kind)
+		require.NotEmpty(t, ce.Message)
+	}
+}
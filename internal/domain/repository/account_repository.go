@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"unipile-connector/internal/domain/entity"
 )
@@ -11,11 +12,28 @@ import (
 type AccountRepository interface {
 	Create(ctx context.Context, account *entity.Account) error
 	GetByUserID(ctx context.Context, userID uint) ([]*entity.Account, error)
+	List(ctx context.Context) ([]*entity.Account, error)
+	GetByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) (*entity.Account, error)
 	GetByUserIDAndAccountIDForUpdate(ctx context.Context, userID uint, accountID string) (*entity.Account, error)
-	GetWithStatus(ctx context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error)
+	GetByAccountIDForUpdate(ctx context.Context, accountID string) (*entity.Account, error)
+	ListPendingWithExpiredCheckpoint(ctx context.Context, now time.Time) ([]*entity.Account, error)
+	GetWithStatus(ctx context.Context, userID uint, accountID string, checkpoints ...entity.CheckpointType) (*entity.AccountWithStatus, error)
 	Update(ctx context.Context, account *entity.Account) error
 	DeleteByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) error
+	CreateStatusHistory(ctx context.Context, history *entity.AccountStatusHistory) error
+	UpdateCheckpointMetadata(ctx context.Context, history *entity.AccountStatusHistory) error
+	GetCheckpointAttempt(ctx context.Context, statusHistoryID uint) (*entity.CheckpointAttempt, error)
+	SaveCheckpointAttempt(ctx context.Context, attempt *entity.CheckpointAttempt) error
+	CreateHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error
+	GetHostedAuthStateForUpdate(ctx context.Context, nonce string) (*entity.HostedAuthState, error)
+	SaveHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error
 }
 
 // ErrAccountNotFound is returned when an account is not found
 var ErrAccountNotFound = errors.New("account not found")
+
+// ErrCheckpointAttemptNotFound is returned when no code was submitted for a checkpoint yet
+var ErrCheckpointAttemptNotFound = errors.New("checkpoint attempt not found")
+
+// ErrHostedAuthStateNotFound is returned when no hosted auth state was issued with a nonce
+var ErrHostedAuthStateNotFound = errors.New("hosted auth state not found")
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+)
+
+// ChatRepository defines the interface for the locally synced chats
+type ChatRepository interface {
+	// Upsert creates the chat or updates the stored chat with the same account and Unipile chat ID
+	Upsert(ctx context.Context, chat *entity.Chat) error
+	GetByAccountIDAndChatID(ctx context.Context, accountID uint, chatID string) (*entity.Chat, error)
+	List(ctx context.Context, filter *ChatFilter) ([]*entity.Chat, error)
+	Update(ctx context.Context, chat *entity.Chat) error
+	GetSyncState(ctx context.Context, accountID uint) (*entity.ChatSyncState, error)
+	SaveSyncState(ctx context.Context, state *entity.ChatSyncState) error
+}
+
+// ChatFilter represents the filters and the page of a chat listing, ordered by last activity, newest first
+type ChatFilter struct {
+	AccountIDs []uint
+	Unread     *bool         // Only unread or read chats, nil for both
+	Since      *time.Time    // Only chats with activity at or after it
+	Until      *time.Time    // Only chats with activity before it
+	After      *ChatPosition // Position of the last chat of the previous page, nil for the first page
+	Limit      int
+}
+
+// ChatPosition is the position of a chat in the last activity order
+type ChatPosition struct {
+	LastMessageAt time.Time
+	ID            uint
+}
+
+// MessageRepository defines the interface for the locally synced messages
+type MessageRepository interface {
+	// Upsert creates the message or updates the stored message with the same chat and Unipile message ID
+	Upsert(ctx context.Context, message *entity.Message) error
+	GetByChatIDAndMessageID(ctx context.Context, chatID uint, messageID string) (*entity.Message, error)
+	List(ctx context.Context, filter *MessageFilter) ([]*entity.Message, error)
+	// Search finds the messages whose text or chat attendee names match the query, best match first
+	Search(ctx context.Context, filter *MessageSearchFilter) ([]*MessageSearchResult, error)
+}
+
+// MessageFilter represents the page of a message listing, ordered by sending time, newest first
+type MessageFilter struct {
+	ChatID uint
+	After  *MessagePosition // Position of the last message of the previous page, nil for the first page
+	Limit  int
+}
+
+// MessagePosition is the position of a message in the sending time order
+type MessagePosition struct {
+	SentAt time.Time
+	ID     uint
+}
+
+// SearchQuery is a full-text query matching the messages that contain every term
+type SearchQuery []SearchTerm
+
+// SearchTerm is a word, or a phrase when it has several words
+type SearchTerm struct {
+	Words  []string // Letters and digits only
+	Prefix bool     // The last word matches any word it starts
+}
+
+// MessageSearchFilter represents the query and the page of a message search
+type MessageSearchFilter struct {
+	AccountIDs []uint
+	Query      SearchQuery
+	Limit      int
+	Offset     int
+}
+
+// MessageSearchResult is a message matching a search, with its chat and highlighted matches
+type MessageSearchResult struct {
+	entity.Message
+	ChatExternalID    string  // Chat ID from Unipile
+	AttendeeNames     string  // Attendee names of the chat
+	Rank              float64 // Relevance, higher is better
+	Highlight         string  // Message text fragments with the matches wrapped in <mark> tags
+	AttendeeHighlight string  // Attendee names with the matches wrapped in <mark> tags
+}
+
+// ErrChatNotFound is returned when a chat is not found
+var ErrChatNotFound = errors.New("chat not found")
+
+// ErrMessageNotFound is returned when a message is not found
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrChatSyncStateNotFound is returned when the chats of an account were never synced
+var ErrChatSyncStateNotFound = errors.New("chat sync state not found")
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+)
+
+// InvitationRepository defines the interface for the locally tracked connection invitations
+type InvitationRepository interface {
+	// Upsert creates the invitation or refreshes the stored invitation with the same account and Unipile invitation ID.
+	// The status of a stored invitation is kept, as is its sent time when the invitation has none,
+	// and the invitation is filled with the stored values.
+	Upsert(ctx context.Context, invitation *entity.Invitation) error
+	GetByAccountIDAndInvitationID(ctx context.Context, accountID uint, invitationID string) (*entity.Invitation, error)
+	Update(ctx context.Context, invitation *entity.Invitation) error
+	// AcceptPending marks the pending invitations the account sent to or received from a provider user as accepted,
+	// returning how many were
+	AcceptPending(ctx context.Context, accountID uint, providerID string, acceptedAt time.Time) (int64, error)
+	// ClosePendingExcept closes the pending invitations of the account in the direction that are not in invitationIDs
+	// and were created before listedAt, returning how many were
+	ClosePendingExcept(ctx context.Context, accountID uint, direction entity.InvitationDirection, invitationIDs []string, listedAt time.Time) (int64, error)
+}
+
+// ErrInvitationNotFound is returned when an invitation is not found
+var ErrInvitationNotFound = errors.New("invitation not found")
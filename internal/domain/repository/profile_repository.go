@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"unipile-connector/internal/domain/entity"
+)
+
+// ProfileRepository defines the interface for the locally cached user profiles
+type ProfileRepository interface {
+	// GetByAccountIDAndIdentifier gets the cached profile whose provider ID or public identifier is identifier,
+	// public identifiers are compared ignoring case
+	GetByAccountIDAndIdentifier(ctx context.Context, accountID uint, identifier string) (*entity.Profile, error)
+	// Upsert creates the profile or updates the cached profile with the same account and provider ID
+	Upsert(ctx context.Context, profile *entity.Profile) error
+}
+
+// ErrProfileNotFound is returned when a profile is not cached
+var ErrProfileNotFound = errors.New("profile not found")
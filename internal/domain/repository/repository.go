@@ -4,9 +4,13 @@ import "errors"
 
 // Repositories is a collection of repositories
 type Repositories struct {
-	Tx      TxRepository
-	User    UserRepository
-	Account AccountRepository
+	Tx         TxRepository
+	User       UserRepository
+	Account    AccountRepository
+	Chat       ChatRepository
+	Message    MessageRepository
+	Profile    ProfileRepository
+	Invitation InvitationRepository
 }
 
 // ErrRecordNotFound is returned when a record is not found
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// AccountEventTypeStatus is the event type for account status changes
+const AccountEventTypeStatus = "account.status"
+
+// AccountEvent represents a change on one of the user's accounts
+type AccountEvent struct {
+	Type      string    `json:"type"`
+	AccountID string    `json:"account_id"`
+	Provider  string    `json:"provider"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AccountEventHub fans out account events to every open session of a user
+type AccountEventHub interface {
+	// Subscribe registers a session for the user, the returned channel is closed on unsubscribe or Close
+	Subscribe(userID uint) (<-chan AccountEvent, func())
+	// Publish sends the event to every session of the user without blocking
+	Publish(userID uint, event AccountEvent)
+	// Close closes every session, further subscriptions are closed immediately
+	Close()
+}
+
+// accountEventBufferSize is the number of events buffered per session before events are dropped
+const accountEventBufferSize = 16
+
+// AccountEventHubImpl implements AccountEventHub with in-memory channels
+type AccountEventHubImpl struct {
+	subscribers map[uint]map[chan AccountEvent]struct{}
+	mutex       sync.RWMutex
+	closed      bool
+}
+
+// NewAccountEventHub creates a new account event hub
+func NewAccountEventHub() AccountEventHub {
+	return &AccountEventHubImpl{
+		subscribers: make(map[uint]map[chan AccountEvent]struct{}),
+	}
+}
+
+// Subscribe registers a session for the user
+func (h *AccountEventHubImpl) Subscribe(userID uint) (<-chan AccountEvent, func()) {
+	ch := make(chan AccountEvent, accountEventBufferSize)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan AccountEvent]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+
+			if _, ok := h.subscribers[userID][ch]; !ok {
+				return // already closed by Close
+			}
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends the event to every session of the user, sessions with a full buffer miss the event
+func (h *AccountEventHubImpl) Publish(userID uint, event AccountEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close closes every session
+func (h *AccountEventHubImpl) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+
+	for userID, sessions := range h.subscribers {
+		for ch := range sessions {
+			close(ch)
+		}
+		delete(h.subscribers, userID)
+	}
+}
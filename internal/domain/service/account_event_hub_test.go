@@ -0,0 +1,83 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountEventHub_PublishToUserSessions(t *testing.T) {
+	hub := NewAccountEventHub()
+
+	first, unsubscribeFirst := hub.Subscribe(1)
+	second, unsubscribeSecond := hub.Subscribe(1)
+	other, unsubscribeOther := hub.Subscribe(2)
+	defer unsubscribeFirst()
+	defer unsubscribeSecond()
+	defer unsubscribeOther()
+
+	hub.Publish(1, AccountEvent{Type: AccountEventTypeStatus, AccountID: "acc-1", Status: "OK"})
+
+	for _, ch := range []<-chan AccountEvent{first, second} {
+		event := <-ch
+		require.Equal(t, "acc-1", event.AccountID)
+		require.Equal(t, "OK", event.Status)
+		require.False(t, event.Timestamp.IsZero())
+	}
+
+	select {
+	case event := <-other:
+		t.Fatalf("unexpected event for other user: %+v", event)
+	default:
+	}
+}
+
+func TestAccountEventHub_UnsubscribeClosesChannel(t *testing.T) {
+	hub := NewAccountEventHub()
+
+	ch, unsubscribe := hub.Subscribe(1)
+	unsubscribe()
+	unsubscribe() // idempotent
+
+	_, ok := <-ch
+	require.False(t, ok)
+
+	// Publishing without sessions is a no-op
+	hub.Publish(1, AccountEvent{Status: "OK"})
+}
+
+func TestAccountEventHub_DropsEventsForSlowSessions(t *testing.T) {
+	hub := NewAccountEventHub()
+
+	ch, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i < accountEventBufferSize+5; i++ {
+		hub.Publish(1, AccountEvent{Status: "OK"})
+	}
+	require.Len(t, ch, accountEventBufferSize)
+}
+
+func TestAccountEventHub_Close(t *testing.T) {
+	hub := NewAccountEventHub()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		ch, unsubscribe := hub.Subscribe(uint(i % 3))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer unsubscribe()
+			for range ch {
+			}
+		}()
+	}
+
+	hub.Close()
+	wg.Wait()
+
+	ch, _ := hub.Subscribe(1)
+	_, ok := <-ch
+	require.False(t, ok)
+}
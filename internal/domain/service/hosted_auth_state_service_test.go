@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/errs"
+)
+
+func TestHostedAuthStateService_GenerateAndValidateState(t *testing.T) {
+	service := NewHostedAuthStateService("state-secret")
+
+	state, nonce, err := service.GenerateState(42, time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, state)
+	require.Len(t, nonce, 32)
+
+	userID, validatedNonce, err := service.ValidateState(state)
+	require.NoError(t, err)
+	require.Equal(t, uint(42), userID)
+	require.Equal(t, nonce, validatedNonce)
+
+	_, otherNonce, err := service.GenerateState(42, time.Hour)
+	require.NoError(t, err)
+	require.NotEqual(t, nonce, otherNonce)
+}
+
+func TestHostedAuthStateService_ValidateState_Invalid(t *testing.T) {
+	service := NewHostedAuthStateService("state-secret")
+
+	expired, _, err := service.GenerateState(42, -time.Minute)
+	require.NoError(t, err)
+	otherSecret, _, err := NewHostedAuthStateService("other-secret").GenerateState(42, time.Hour)
+	require.NoError(t, err)
+	accessToken, err := NewJWTService("state-secret", "issuer", NewTokenBlacklistService()).GenerateToken(42, "alice")
+	require.NoError(t, err)
+
+	for name, state := range map[string]string{
+		"malformed":    "invalid.token.string",
+		"expired":      expired,
+		"other secret": otherSecret,
+		"access token": accessToken,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := service.ValidateState(state)
+			require.ErrorIs(t, err, errs.ErrInvalidHostedAuthState)
+		})
+	}
+}
+
+func TestHostedAuthStateService_MissingSecret(t *testing.T) {
+	service := NewHostedAuthStateService("")
+
+	_, _, err := service.GenerateState(42, time.Hour)
+	require.ErrorIs(t, err, ErrHostedAuthStateSecretMissing)
+}
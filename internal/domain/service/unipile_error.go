@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Unipile error types returned in the "type" field of problem+json responses
+const (
+	UnipileErrorTypeInvalidCredentials        = "errors/invalid_credentials"
+	UnipileErrorTypeExpiredCredentials        = "errors/expired_credentials"
+	UnipileErrorTypeDisconnectedAccount       = "errors/disconnected_account"
+	UnipileErrorTypeInsufficientPrivileges    = "errors/insufficient_privileges"
+	UnipileErrorTypeTooManyRequests           = "errors/too_many_requests"
+	UnipileErrorTypeResourceNotFound          = "errors/resource_not_found"
+	UnipileErrorTypeAuthenticationIntentError = "errors/authentication_intent_error"
+	UnipileErrorTypeInvalidCheckpointSolution = "errors/invalid_checkpoint_solution"
+	UnipileErrorTypeCheckpointError           = "errors/checkpoint_error"
+)
+
+// UnipileError represents an error response returned by Unipile API
+type UnipileError struct {
+	Status    int    `json:"status"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"request_id,omitempty"`
+	Body      string `json:"-"` // raw response body, kept for non problem+json responses
+}
+
+func (e *UnipileError) Error() string {
+	msg := fmt.Sprintf("unipile API error (status %d", e.Status)
+	if e.Type != "" {
+		msg += ", type " + e.Type
+	}
+	if e.RequestID != "" {
+		msg += ", request " + e.RequestID
+	}
+	msg += ")"
+
+	switch {
+	case e.Title != "" && e.Detail != "":
+		return fmt.Sprintf("%s: %s: %s", msg, e.Title, e.Detail)
+	case e.Title != "":
+		return fmt.Sprintf("%s: %s", msg, e.Title)
+	case e.Detail != "":
+		return fmt.Sprintf("%s: %s", msg, e.Detail)
+	case e.Body != "":
+		return fmt.Sprintf("%s: %s", msg, e.Body)
+	}
+	return msg
+}
+
+// IsNotFound reports whether Unipile answered that the resource of the request does not exist.
+// Unipile answers alike for every resource, so the not found errors of a resource are left to the client call.
+func (e *UnipileError) IsNotFound() bool {
+	return e.Type == UnipileErrorTypeResourceNotFound || e.Status == http.StatusNotFound
+}
+
+// Is maps the Unipile error type and status to the Unipile error categories
+func (e *UnipileError) Is(target error) bool {
+	switch target {
+	case ErrUnipileInvalidCredentials:
+		return e.Type == UnipileErrorTypeInvalidCredentials || e.Type == UnipileErrorTypeExpiredCredentials
+	case ErrUnipileDisconnectedAccount:
+		return e.Type == UnipileErrorTypeDisconnectedAccount
+	case ErrUnipileInsufficientPermissions:
+		return e.Type == UnipileErrorTypeInsufficientPrivileges || (e.Type == "" && e.Status == http.StatusForbidden)
+	case ErrUnipileRateLimited:
+		return e.Type == UnipileErrorTypeTooManyRequests || e.Status == http.StatusTooManyRequests
+	case ErrUnipileInvalidCodeOrExpiredCheckpoint:
+		return e.Type == UnipileErrorTypeAuthenticationIntentError ||
+			e.Type == UnipileErrorTypeInvalidCheckpointSolution ||
+			e.Type == UnipileErrorTypeCheckpointError
+	case ErrUnipileUnavailable:
+		return e.Status >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// ErrUnipileInvalidCredentials is returned when the provider credentials are invalid or expired
+var ErrUnipileInvalidCredentials = errors.New("invalid credentials")
+
+// ErrUnipileDisconnectedAccount is returned when the account is disconnected on the provider side
+var ErrUnipileDisconnectedAccount = errors.New("disconnected account")
+
+// ErrUnipileInsufficientPermissions is returned when the API key or account lacks the required permissions
+var ErrUnipileInsufficientPermissions = errors.New("insufficient permissions")
+
+// ErrUnipileRateLimited is returned when Unipile or the provider rate limits the request
+var ErrUnipileRateLimited = errors.New("rate limited")
+
+// ErrUnipileUnavailable is returned when Unipile or the provider is temporarily unavailable
+var ErrUnipileUnavailable = errors.New("unipile unavailable")
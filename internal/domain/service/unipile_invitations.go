@@ -0,0 +1,71 @@
+package service
+
+import "errors"
+
+// SendInvitationRequest represents the request to send a LinkedIn connection invitation
+type SendInvitationRequest struct {
+	AccountID  string `json:"account_id"`
+	ProviderID string `json:"provider_id"`       // Provider ID of the invited user
+	Message    string `json:"message,omitempty"` // Note sent with the invitation, optional
+}
+
+// SendInvitationResponse represents the response from sending an invitation
+type SendInvitationResponse struct {
+	Object       string `json:"object"`
+	InvitationID string `json:"invitation_id"`
+}
+
+// ListInvitationsRequest represents the pagination parameters for listing the invitations of an account
+type ListInvitationsRequest struct {
+	AccountID string
+	Limit     int    // Page size (1-250), 0 uses the Unipile default
+	Cursor    string // Cursor returned by the previous page, empty for the first page
+}
+
+// SentInvitation represents a pending invitation sent by the account
+type SentInvitation struct {
+	Object                 string `json:"object"`
+	ID                     string `json:"id"`
+	Date                   string `json:"date"`            // Human readable date, e.g. "Sent today"
+	ParsedDatetime         string `json:"parsed_datetime"` // ISO 8601 date of the invitation
+	InvitedUser            string `json:"invited_user"`    // Name of the invited user
+	InvitedUserID          string `json:"invited_user_id"` // Provider ID of the invited user
+	InvitedUserPublicID    string `json:"invited_user_public_id"`
+	InvitedUserDescription string `json:"invited_user_description"`
+	InvitationText         string `json:"invitation_text"`
+}
+
+// SentInvitationListResponse represents the response from listing sent invitations
+type SentInvitationListResponse struct {
+	Object string           `json:"object"`
+	Items  []SentInvitation `json:"items"`
+	Cursor *string          `json:"cursor"`
+}
+
+// ReceivedInvitation represents a pending invitation received by the account
+type ReceivedInvitation struct {
+	Object         string  `json:"object"`
+	ID             string  `json:"id"`
+	Date           string  `json:"date"`
+	ParsedDatetime string  `json:"parsed_datetime"`
+	InvitationText string  `json:"invitation_text"`
+	Inviter        Inviter `json:"inviter"`
+}
+
+// Inviter represents the user who sent a received invitation
+type Inviter struct {
+	InviterID               string `json:"inviter_id"` // Provider ID of the inviter
+	InviterName             string `json:"inviter_name"`
+	InviterDescription      string `json:"inviter_description"`
+	InviterPublicIdentifier string `json:"inviter_public_identifier"`
+}
+
+// ReceivedInvitationListResponse represents the response from listing received invitations
+type ReceivedInvitationListResponse struct {
+	Object string               `json:"object"`
+	Items  []ReceivedInvitation `json:"items"`
+	Cursor *string              `json:"cursor"`
+}
+
+// ErrUnipileInvitationNotFound is returned when an invitation is not found
+var ErrUnipileInvitationNotFound = errors.New("invitation not found")
@@ -0,0 +1,154 @@
+package service
+
+import "errors"
+
+// Chat represents a Unipile chat
+type Chat struct {
+	Object             string `json:"object"`
+	ID                 string `json:"id"`
+	AccountID          string `json:"account_id"`
+	AccountType        string `json:"account_type"`
+	ProviderID         string `json:"provider_id"`
+	AttendeeProviderID string `json:"attendee_provider_id"`
+	Name               string `json:"name"`
+	Type               int    `json:"type"`      // 0 one-to-one, 1 group, 2 channel
+	Timestamp          string `json:"timestamp"` // Last activity
+	UnreadCount        int    `json:"unread_count"`
+	Archived           int    `json:"archived"`
+	ReadOnly           int    `json:"read_only"`
+	Subject            string `json:"subject,omitempty"`
+	ContentType        string `json:"content_type,omitempty"` // "inmail", "sponsored", "linkedin_offer"
+}
+
+// ListChatsRequest represents the filters and pagination parameters for listing chats
+type ListChatsRequest struct {
+	AccountID string
+	Limit     int    // Page size (1-250), 0 uses the Unipile default
+	Cursor    string // Cursor returned by the previous page, empty for the first page
+	Unread    *bool  // Only unread or read chats, nil for both
+	Before    string // ISO 8601 date, only chats with activity before it
+	After     string // ISO 8601 date, only chats with activity after it
+}
+
+// ChatListResponse represents the response from listing chats
+type ChatListResponse struct {
+	Object string  `json:"object"`
+	Items  []Chat  `json:"items"`
+	Cursor *string `json:"cursor"`
+}
+
+// ChatAttendee represents a participant of a Unipile chat
+type ChatAttendee struct {
+	Object     string `json:"object"`
+	ID         string `json:"id"`
+	AccountID  string `json:"account_id"`
+	ProviderID string `json:"provider_id"`
+	Name       string `json:"name"`
+	IsSelf     int    `json:"is_self"`
+	Hidden     int    `json:"hidden"`
+	PictureURL string `json:"picture_url,omitempty"`
+	ProfileURL string `json:"profile_url,omitempty"`
+}
+
+// ListChatAttendeesRequest represents the pagination parameters for listing the attendees of a chat
+type ListChatAttendeesRequest struct {
+	ChatID string
+	Limit  int
+	Cursor string
+}
+
+// ChatAttendeeListResponse represents the response from listing chat attendees
+type ChatAttendeeListResponse struct {
+	Object string         `json:"object"`
+	Items  []ChatAttendee `json:"items"`
+	Cursor *string        `json:"cursor"`
+}
+
+// Message represents a message of a Unipile chat
+type Message struct {
+	Object           string              `json:"object"`
+	ID               string              `json:"id"`
+	AccountID        string              `json:"account_id"`
+	ChatID           string              `json:"chat_id"`
+	ChatProviderID   string              `json:"chat_provider_id"`
+	ProviderID       string              `json:"provider_id"`
+	SenderID         string              `json:"sender_id"`
+	SenderAttendeeID string              `json:"sender_attendee_id"`
+	Text             string              `json:"text"`
+	Timestamp        string              `json:"timestamp"`
+	IsSender         int                 `json:"is_sender"` // 1 when the account sent the message
+	Attachments      []MessageAttachment `json:"attachments"`
+	Seen             int                 `json:"seen"`
+	Hidden           int                 `json:"hidden"`
+	Deleted          int                 `json:"deleted"`
+	Edited           int                 `json:"edited"`
+	IsEvent          int                 `json:"is_event"`
+	Delivered        int                 `json:"delivered"`
+}
+
+// MessageAttachment represents a file attached to a message
+type MessageAttachment struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // "img", "video", "audio", "file", "linkedin_post"
+	FileName    string `json:"file_name,omitempty"`
+	FileSize    int64  `json:"file_size,omitempty"`
+	MimeType    string `json:"mimetype,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Unavailable bool   `json:"unavailable"`
+}
+
+// ListMessagesRequest represents the filters and pagination parameters for listing the messages of a chat
+type ListMessagesRequest struct {
+	ChatID string
+	Limit  int
+	Cursor string
+	Before string // ISO 8601 date, only messages sent before it
+	After  string // ISO 8601 date, only messages sent after it
+}
+
+// MessageListResponse represents the response from listing messages
+type MessageListResponse struct {
+	Object string    `json:"object"`
+	Items  []Message `json:"items"`
+	Cursor *string   `json:"cursor"`
+}
+
+// Attachment is a file uploaded with a message
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// SendMessageRequest represents the request to send a message in an existing chat
+type SendMessageRequest struct {
+	ChatID      string
+	Text        string
+	Attachments []Attachment
+}
+
+// SendMessageResponse represents the response from sending a message
+type SendMessageResponse struct {
+	Object    string `json:"object"`
+	MessageID string `json:"message_id"`
+}
+
+// StartChatRequest represents the request to start a new chat with a first message
+type StartChatRequest struct {
+	AccountID           string
+	AttendeeProviderIDs []string // Provider IDs of the attendees, e.g. LinkedIn member IDs
+	Text                string
+	Subject             string // Subject of an InMail or email-like chat, optional
+	InMail              bool   // Send a LinkedIn InMail, for attendees outside the account network
+	Attachments         []Attachment
+}
+
+// StartChatResponse represents the response from starting a chat
+type StartChatResponse struct {
+	Object    string `json:"object"`
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+}
+
+// ErrUnipileChatNotFound is returned when a chat is not found
+var ErrUnipileChatNotFound = errors.New("chat not found")
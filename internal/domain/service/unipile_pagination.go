@@ -0,0 +1,42 @@
+package service
+
+import "context"
+
+// ForEachAccount walks every page of Unipile accounts and calls fn for each account.
+// It stops at the first error returned by fn or by the client, or when ctx is done.
+func ForEachAccount(ctx context.Context, client UnipileClient, limit int, fn func(account *Account) error) error {
+	req := &ListAccountsRequest{Limit: limit}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := client.ListAccounts(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for i := range page.Items {
+			if err := fn(&page.Items[i]); err != nil {
+				return err
+			}
+		}
+
+		if page.Cursor == nil || *page.Cursor == "" || *page.Cursor == req.Cursor {
+			return nil
+		}
+		req = &ListAccountsRequest{Limit: limit, Cursor: *page.Cursor}
+	}
+}
+
+// ListAllAccounts collects the accounts from every page of Unipile accounts
+func ListAllAccounts(ctx context.Context, client UnipileClient, limit int) ([]Account, error) {
+	var accounts []Account
+	if err := ForEachAccount(ctx, client, limit, func(account *Account) error {
+		accounts = append(accounts, *account)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
@@ -1,19 +1,35 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 )
 
 // UnipileClient handles communication with Unipile API
 type UnipileClient interface {
-	ListAccounts() (*AccountListResponse, error)
-	TestConnection() error
-	GetAccount(accountID string) (*Account, error)
-	GetAccountWithLongPolling(accountID string, timeout time.Duration) (*Account, error)
-	DeleteAccount(accountID string) error
-	ConnectLinkedIn(req *ConnectLinkedInRequest) (*ConnectLinkedInResponse, error)
-	SolveCheckpoint(req *SolveCheckpointRequest) (*SolveCheckpointResponse, error)
+	ListAccounts(ctx context.Context, req *ListAccountsRequest) (*AccountListResponse, error)
+	TestConnection(ctx context.Context) error
+	GetAccount(ctx context.Context, accountID string) (*Account, error)
+	GetAccountWithLongPolling(ctx context.Context, accountID string, timeout time.Duration) (*Account, error)
+	DeleteAccount(ctx context.Context, accountID string) error
+	ConnectAccount(ctx context.Context, req *ConnectAccountRequest) (*ConnectAccountResponse, error)
+	ConnectWhatsApp(ctx context.Context) (*ConnectAccountResponse, error)
+	SolveCheckpoint(ctx context.Context, req *SolveCheckpointRequest) (*SolveCheckpointResponse, error)
+	ReconnectAccount(ctx context.Context, accountID string, req *ConnectAccountRequest) (*ConnectAccountResponse, error)
+	ResendCheckpoint(ctx context.Context, req *ResendCheckpointRequest) (*ResendCheckpointResponse, error)
+	CreateHostedAuthLink(ctx context.Context, req *HostedAuthLinkRequest) (*HostedAuthLinkResponse, error)
+	ListChats(ctx context.Context, req *ListChatsRequest) (*ChatListResponse, error)
+	GetChat(ctx context.Context, chatID string) (*Chat, error)
+	ListChatAttendees(ctx context.Context, req *ListChatAttendeesRequest) (*ChatAttendeeListResponse, error)
+	ListMessages(ctx context.Context, req *ListMessagesRequest) (*MessageListResponse, error)
+	SendMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error)
+	StartChat(ctx context.Context, req *StartChatRequest) (*StartChatResponse, error)
+	GetUserProfile(ctx context.Context, accountID, identifier string) (*UserProfile, error)
+	SendInvitation(ctx context.Context, req *SendInvitationRequest) (*SendInvitationResponse, error)
+	ListSentInvitations(ctx context.Context, req *ListInvitationsRequest) (*SentInvitationListResponse, error)
+	ListReceivedInvitations(ctx context.Context, req *ListInvitationsRequest) (*ReceivedInvitationListResponse, error)
+	WithdrawInvitation(ctx context.Context, accountID, invitationID string) error
 }
 
 // Account represents a single account in the list
@@ -34,6 +50,12 @@ type AccountSource struct {
 	Status string `json:"status"`
 }
 
+// ListAccountsRequest represents the pagination parameters for listing accounts
+type ListAccountsRequest struct {
+	Limit  int    // Page size (1-250), 0 uses the Unipile default
+	Cursor string // Cursor returned by the previous page, empty for the first page
+}
+
 // AccountListResponse represents the response from listing accounts
 type AccountListResponse struct {
 	Object string    `json:"object"`
@@ -41,17 +63,18 @@ type AccountListResponse struct {
 	Cursor *string   `json:"cursor"`
 }
 
-// ConnectLinkedInRequest represents the request to connect LinkedIn account
-type ConnectLinkedInRequest struct {
-	Provider    string `json:"provider"` // "LINKEDIN"
+// ConnectAccountRequest represents the request to connect an account.
+// Only the fields the provider authenticates with are sent.
+type ConnectAccountRequest struct {
+	Provider    string `json:"provider"` // "LINKEDIN", "WHATSAPP", "INSTAGRAM", "TELEGRAM", "MESSENGER"
 	Username    string `json:"username,omitempty"`
 	Password    string `json:"password,omitempty"`
 	AccessToken string `json:"access_token,omitempty"`
 	UserAgent   string `json:"user_agent,omitempty"`
 }
 
-// ConnectLinkedInResponse represents the response from LinkedIn connection
-type ConnectLinkedInResponse struct {
+// ConnectAccountResponse represents the response from an account connection
+type ConnectAccountResponse struct {
 	Object     string      `json:"object"`
 	AccountID  string      `json:"account_id"`
 	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
@@ -59,23 +82,27 @@ type ConnectLinkedInResponse struct {
 	RowBody    string      `json:"row_body,omitempty"`
 }
 
-// Checkpoint represents a LinkedIn authentication checkpoint
+// Checkpoint represents an authentication checkpoint
 type Checkpoint struct {
-	Type   string `json:"type"`   // "2FA", "OTP", "IN_APP_VALIDATION", "CAPTCHA", "PHONE_REGISTER"
-	Source string `json:"source"` // "APP"
+	Type   string `json:"type"`             // "2FA", "OTP", "IN_APP_VALIDATION", "CAPTCHA", "PHONE_REGISTER", "QRCODE"
+	Source string `json:"source,omitempty"` // "APP"
+	QRCode string `json:"qrcode,omitempty"` // Content of the QR code to scan, set for QRCODE checkpoints
 }
 
 // SolveCheckpointRequest represents request to solve a checkpoint
 type SolveCheckpointRequest struct {
-	Provider  string `json:"provider"` // "LINKEDIN"
+	Provider  string `json:"provider"`
 	AccountID string `json:"account_id"`
 	Code      string `json:"code"`
 }
 
-// SolveCheckpointResponse represents response from checkpoint solving
+// SolveCheckpointResponse represents response from checkpoint solving.
+// Checkpoint is set when Unipile requires another checkpoint before the account is connected.
 type SolveCheckpointResponse struct {
-	Object    string `json:"object"`
-	AccountID string `json:"account_id"`
+	Object     string      `json:"object"`
+	AccountID  string      `json:"account_id"`
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+	RowBody    string      `json:"row_body,omitempty"`
 	// error response
 	Status int    `json:"status"`
 	Type   string `json:"type"`
@@ -83,8 +110,45 @@ type SolveCheckpointResponse struct {
 	Detail string `json:"detail"`
 }
 
+// ResendCheckpointRequest represents request to send a new checkpoint code
+type ResendCheckpointRequest struct {
+	Provider  string `json:"provider"`
+	AccountID string `json:"account_id"`
+}
+
+// ResendCheckpointResponse represents response from requesting a new checkpoint code
+type ResendCheckpointResponse struct {
+	Object     string      `json:"object"`
+	AccountID  string      `json:"account_id"`
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+	RowBody    string      `json:"row_body,omitempty"`
+}
+
+// HostedAuthLinkRequest represents the request to create a hosted auth wizard link
+type HostedAuthLinkRequest struct {
+	Type               string   `json:"type"`      // "create" or "reconnect"
+	Providers          any      `json:"providers"` // "*" or a list of providers, e.g. ["LINKEDIN", "WHATSAPP"]
+	APIURL             string   `json:"api_url"`
+	ExpiresOn          string   `json:"expiresOn"` // ISO 8601 date the link expires on
+	Name               string   `json:"name,omitempty"`
+	NotifyURL          string   `json:"notify_url,omitempty"`
+	SuccessRedirectURL string   `json:"success_redirect_url,omitempty"`
+	FailureRedirectURL string   `json:"failure_redirect_url,omitempty"`
+	ReconnectAccount   string   `json:"reconnect_account,omitempty"`
+	Groups             []string `json:"groups,omitempty"`
+}
+
+// HostedAuthLinkResponse represents the response from creating a hosted auth wizard link
+type HostedAuthLinkResponse struct {
+	Object string `json:"object"`
+	URL    string `json:"url"`
+}
+
 // ErrUnipileInvalidCodeOrExpiredCheckpoint is returned when the code is invalid or the checkpoint expired
 var ErrUnipileInvalidCodeOrExpiredCheckpoint = errors.New("invalid code or expired checkpoint")
 
+// ErrUnipileMissingQRCode is returned when Unipile answers a QR code connection without a QR code
+var ErrUnipileMissingQRCode = errors.New("missing QR code")
+
 // ErrUnipileAccountNotFound is returned when an account is not found
 var ErrUnipileAccountNotFound = errors.New("account not found")
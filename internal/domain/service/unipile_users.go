@@ -0,0 +1,45 @@
+package service
+
+import "errors"
+
+// UserProfile represents the profile of a provider user, as seen by the account it was looked up with
+type UserProfile struct {
+	Object            string           `json:"object"`
+	Provider          string           `json:"provider"`
+	ProviderID        string           `json:"provider_id"`
+	PublicIdentifier  string           `json:"public_identifier"`
+	FirstName         string           `json:"first_name"`
+	LastName          string           `json:"last_name"`
+	Headline          string           `json:"headline"`
+	Location          string           `json:"location"`
+	ProfilePictureURL string           `json:"profile_picture_url,omitempty"`
+	PublicProfileURL  string           `json:"public_profile_url,omitempty"`
+	NetworkDistance   string           `json:"network_distance,omitempty"` // "FIRST_DEGREE", "SECOND_DEGREE", "THIRD_DEGREE", "OUT_OF_NETWORK"
+	WorkExperience    []WorkExperience `json:"work_experience,omitempty"`
+}
+
+// WorkExperience represents a position of a LinkedIn profile
+type WorkExperience struct {
+	Position string `json:"position"`
+	Company  string `json:"company"`
+	Start    string `json:"start"`
+	End      string `json:"end"` // Empty for a current position
+}
+
+// CurrentCompany returns the company of the current position, or of the most recent one when none is current
+func (p *UserProfile) CurrentCompany() string {
+	for _, experience := range p.WorkExperience {
+		if experience.End == "" && experience.Company != "" {
+			return experience.Company
+		}
+	}
+	for _, experience := range p.WorkExperience {
+		if experience.Company != "" {
+			return experience.Company
+		}
+	}
+	return ""
+}
+
+// ErrUnipileUserNotFound is returned when no user matches the identifier
+var ErrUnipileUserNotFound = errors.New("user not found")
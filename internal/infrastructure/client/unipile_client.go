@@ -2,71 +2,87 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	neturl "net/url"
+	"strconv"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"unipile-connector/internal/domain/service"
 )
 
+// RetryPolicy controls how failed Unipile requests are retried
+type RetryPolicy struct {
+	MaxRetries int           // Number of retries after the first attempt, 0 disables retrying
+	BaseDelay  time.Duration // Backoff delay before the first retry
+	MaxDelay   time.Duration // Upper bound for a single backoff delay, including Retry-After
+}
+
 // UnipileClientImpl handles communication with Unipile API
 type UnipileClientImpl struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	logger      *logrus.Logger
 }
 
 // NewUnipileClient creates a new Unipile client
-func NewUnipileClient(baseURL, apiKey string) service.UnipileClient {
+func NewUnipileClient(baseURL, apiKey string, retryPolicy RetryPolicy, logger *logrus.Logger) service.UnipileClient {
 	return &UnipileClientImpl{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retryPolicy: retryPolicy,
+		logger:      logger,
 	}
 }
 
-// ListAccounts lists all accounts from Unipile API
-func (c *UnipileClientImpl) ListAccounts() (*service.AccountListResponse, error) {
+// ListAccounts lists one page of accounts from Unipile API
+func (c *UnipileClientImpl) ListAccounts(ctx context.Context, req *service.ListAccountsRequest) (*service.AccountListResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/accounts", c.baseURL)
-
-	httpReq, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	if req != nil {
+		query := neturl.Values{}
+		if req.Limit > 0 {
+			query.Set("limit", strconv.Itoa(req.Limit))
+		}
+		if req.Cursor != "" {
+			query.Set("cursor", req.Cursor)
+		}
+		if len(query) > 0 {
+			url += "?" + query.Encode()
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, c.httpClient, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+	if resp.statusCode != http.StatusOK {
+		return nil, newUnipileError(resp)
 	}
 
 	var response service.AccountListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := json.Unmarshal(resp.body, &response); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	return &response, nil
 }
 
-// TestConnection tests the connection to Unipile API by calling ListAccounts
-func (c *UnipileClientImpl) TestConnection() error {
-	_, err := c.ListAccounts()
+// TestConnection tests the connection to Unipile API by listing a single account
+func (c *UnipileClientImpl) TestConnection(ctx context.Context) error {
+	_, err := c.ListAccounts(ctx, &service.ListAccountsRequest{Limit: 1})
 	if err != nil {
 		return fmt.Errorf("unipile API health check failed: %w", err)
 	}
@@ -74,45 +90,28 @@ func (c *UnipileClientImpl) TestConnection() error {
 }
 
 // GetAccount gets the status of a LinkedIn account
-func (c *UnipileClientImpl) GetAccount(accountID string) (*service.Account, error) {
+func (c *UnipileClientImpl) GetAccount(ctx context.Context, accountID string) (*service.Account, error) {
 	url := fmt.Sprintf("%s/api/v1/accounts/%s", c.baseURL, accountID)
 
-	httpReq, err := http.NewRequest("GET", url, nil)
+	resp, err := c.do(ctx, c.httpClient, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	if resp.statusCode != http.StatusOK {
+		return nil, notFoundAs(newUnipileError(resp), service.ErrUnipileAccountNotFound)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	switch resp.StatusCode {
-	case http.StatusOK:
-		var response service.Account
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
-		return &response, nil
-	case http.StatusNotFound:
-		return nil, service.ErrUnipileAccountNotFound
-	default:
-		return nil, fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+	var response service.Account
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return &response, nil
 }
 
 // GetAccountWithLongPolling gets the status of a LinkedIn account with long polling
 // This method will wait until the account status changes to "OK" or timeout occurs
-func (c *UnipileClientImpl) GetAccountWithLongPolling(accountID string, timeout time.Duration) (*service.Account, error) {
+func (c *UnipileClientImpl) GetAccountWithLongPolling(ctx context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
 	url := fmt.Sprintf("%s/api/v1/accounts/%s", c.baseURL, accountID)
 
 	// Create a client with longer timeout for long polling
@@ -120,161 +119,376 @@ func (c *UnipileClientImpl) GetAccountWithLongPolling(accountID string, timeout
 		Timeout: timeout,
 	}
 
-	httpReq, err := http.NewRequest("GET", url, nil)
+	resp, err := c.do(ctx, longPollClient, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
-
-	resp, err := longPollClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	if resp.statusCode != http.StatusOK {
+		return nil, notFoundAs(newUnipileError(resp), service.ErrUnipileAccountNotFound)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	switch resp.StatusCode {
-	case http.StatusOK:
-		var response service.Account
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
-		return &response, nil
-	case http.StatusNotFound:
-		return nil, service.ErrUnipileAccountNotFound
-	default:
-		return nil, fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+	var response service.Account
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return &response, nil
 }
 
 // DeleteAccount deletes an account from Unipile API
-func (c *UnipileClientImpl) DeleteAccount(accountID string) error {
+func (c *UnipileClientImpl) DeleteAccount(ctx context.Context, accountID string) error {
 	url := fmt.Sprintf("%s/api/v1/accounts/%s", c.baseURL, accountID)
 
-	httpReq, err := http.NewRequest("DELETE", url, nil)
+	resp, err := c.do(ctx, c.httpClient, http.MethodDelete, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
 
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
+	if resp.statusCode != http.StatusOK {
+		return notFoundAs(newUnipileError(resp), service.ErrUnipileAccountNotFound)
+	}
+	return nil
+}
+
+// ConnectAccount connects an account of the request provider using Unipile
+func (c *UnipileClientImpl) ConnectAccount(ctx context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts", c.baseURL)
 
-	resp, err := c.httpClient.Do(httpReq)
+	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		return nil
-	case http.StatusNotFound:
-		return service.ErrUnipileAccountNotFound
+	// Handle different response status codes
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated:
 	default:
-		return fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newUnipileError(resp)
+	}
+
+	var response service.ConnectAccountResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	response.Status = resp.statusCode
+	response.RowBody = string(resp.body)
+
+	return &response, nil
+}
+
+// ConnectWhatsApp starts a WhatsApp connection.
+// Unipile answers with a QRCODE checkpoint the user scans with the WhatsApp app.
+func (c *UnipileClientImpl) ConnectWhatsApp(ctx context.Context) (*service.ConnectAccountResponse, error) {
+	response, err := c.ConnectAccount(ctx, &service.ConnectAccountRequest{Provider: "WHATSAPP"})
+	if err != nil {
+		return nil, err
+	}
+	if response.Checkpoint == nil || response.Checkpoint.QRCode == "" {
+		return nil, fmt.Errorf("%w for account %s", service.ErrUnipileMissingQRCode, response.AccountID)
 	}
+	return response, nil
 }
 
-// ConnectLinkedIn connects a LinkedIn account using Unipile
-func (c *UnipileClientImpl) ConnectLinkedIn(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/accounts", c.baseURL)
+// ReconnectAccount reconnects an existing account with new credentials.
+// The response is the same as ConnectAccount and may carry a checkpoint.
+func (c *UnipileClientImpl) ReconnectAccount(ctx context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts/%s", c.baseURL, accountID)
 
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	default:
+		return nil, notFoundAs(newUnipileError(resp), service.ErrUnipileAccountNotFound)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
+	var response service.ConnectAccountResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	response.Status = resp.statusCode
+	response.RowBody = string(resp.body)
 
-	resp, err := c.httpClient.Do(httpReq)
+	return &response, nil
+}
+
+// SolveCheckpoint solves an authentication checkpoint
+func (c *UnipileClientImpl) SolveCheckpoint(ctx context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts/checkpoint", c.baseURL)
+
+	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	var response service.ConnectLinkedInResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	// Handle different response status codes, 202 carries a follow-up checkpoint
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	case http.StatusUnauthorized:
+		// Unipile answers 401 when the code is wrong or the checkpoint is gone
+		return nil, fmt.Errorf("%w: %w", service.ErrUnipileInvalidCodeOrExpiredCheckpoint, newUnipileError(resp))
+	default:
+		return nil, newUnipileError(resp)
 	}
-	response.Status = resp.StatusCode
-	response.RowBody = string(body)
 
-	// Handle different response status codes
-	switch resp.StatusCode {
-	case http.StatusOK, http.StatusCreated:
-		return &response, nil
-	default:
-		return nil, fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+	var response service.SolveCheckpointResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	response.RowBody = string(resp.body)
+	return &response, nil
 }
 
-// SolveCheckpoint solves a LinkedIn authentication checkpoint
-func (c *UnipileClientImpl) SolveCheckpoint(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/accounts/checkpoint", c.baseURL)
+// ResendCheckpoint asks Unipile to send a new code for the pending checkpoint
+func (c *UnipileClientImpl) ResendCheckpoint(ctx context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/accounts/checkpoint/resend", c.baseURL)
 
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	case http.StatusUnauthorized:
+		// The checkpoint is gone on Unipile's side, the connect flow has to start over
+		return nil, fmt.Errorf("%w: %w", service.ErrUnipileInvalidCodeOrExpiredCheckpoint, newUnipileError(resp))
+	default:
+		return nil, newUnipileError(resp)
+	}
+
+	var response service.ResendCheckpointResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	response.RowBody = string(resp.body)
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-API-KEY", c.apiKey)
-	httpReq.Header.Set("accept", "application/json")
+	return &response, nil
+}
+
+// CreateHostedAuthLink creates a link to the Unipile hosted auth wizard.
+// The API URL defaults to the client base URL.
+func (c *UnipileClientImpl) CreateHostedAuthLink(ctx context.Context, req *service.HostedAuthLinkRequest) (*service.HostedAuthLinkResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/hosted/accounts/link", c.baseURL)
 
-	resp, err := c.httpClient.Do(httpReq)
+	body := *req
+	if body.APIURL == "" {
+		body.APIURL = c.baseURL
+	}
+	jsonData, err := json.Marshal(&body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	var response service.SolveCheckpointResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated:
+	default:
+		return nil, newUnipileError(resp)
+	}
+
+	var response service.HostedAuthLinkResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if response.URL == "" {
+		return nil, fmt.Errorf("missing hosted auth link url in response")
+	}
+	return &response, nil
+}
 
-	// Handle different response status codes
-	switch resp.StatusCode {
-	case http.StatusOK, http.StatusCreated:
-		return &response, nil
-	case http.StatusUnauthorized:
-		return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
+// apiResponse holds the parts of a Unipile response needed after the body is consumed
+type apiResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// newUnipileError builds a UnipileError from a problem+json (or plain) error response
+func newUnipileError(resp *apiResponse) *service.UnipileError {
+	apiErr := &service.UnipileError{}
+	_ = json.Unmarshal(resp.body, apiErr)
+	if apiErr.Type == "" && apiErr.Title == "" && apiErr.Detail == "" {
+		apiErr.Body = string(resp.body)
+	}
+	apiErr.Status = resp.statusCode
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.header.Get("X-Request-Id")
+	}
+	return apiErr
+}
+
+// notFoundAs wraps err in the not found error of the resource a call targets when Unipile answered not found
+func notFoundAs(err error, notFound error) error {
+	var apiErr *service.UnipileError
+	if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+		return fmt.Errorf("%w: %w", notFound, err)
+	}
+	return err
+}
+
+// do sends a request with a JSON payload to Unipile and returns the final response
+func (c *UnipileClientImpl) do(ctx context.Context, httpClient *http.Client, method, url string, payload []byte) (*apiResponse, error) {
+	return c.send(ctx, httpClient, method, url, payload, "application/json")
+}
+
+// send sends a request to Unipile and returns the final response.
+// Idempotent requests (GET/DELETE) are retried on transport errors and on 429/502/503/504.
+// Other requests are only retried on 429, where Unipile rejected them without processing.
+func (c *UnipileClientImpl) send(ctx context.Context, httpClient *http.Client, method, url string, payload []byte, contentType string) (*apiResponse, error) {
+	idempotent := method == http.MethodGet || method == http.MethodDelete
+
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if payload != nil {
+			httpReq.Header.Set("Content-Type", contentType)
+		}
+		httpReq.Header.Set("X-API-KEY", c.apiKey)
+		httpReq.Header.Set("accept", "application/json")
+
+		resp, err := httpClient.Do(httpReq)
+		if err != nil {
+			if !idempotent || ctx.Err() != nil || attempt >= c.retryPolicy.MaxRetries {
+				return nil, fmt.Errorf("failed to make request: %w", err)
+			}
+			delay := c.backoff(attempt)
+			c.logRetry(method, url, attempt, delay, 0, err)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, fmt.Errorf("failed to make request: %w", err)
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if !shouldRetryStatus(resp.StatusCode, idempotent) || attempt >= c.retryPolicy.MaxRetries {
+			return &apiResponse{statusCode: resp.StatusCode, header: resp.Header, body: body}, nil
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+			if c.retryPolicy.MaxDelay > 0 {
+				delay = min(delay, c.retryPolicy.MaxDelay)
+			}
+		}
+		c.logRetry(method, url, attempt, delay, resp.StatusCode, nil)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("failed to make request: %w", err)
+		}
+	}
+}
+
+// shouldRetryStatus reports whether a response with the given status code can be retried
+func shouldRetryStatus(statusCode int, idempotent bool) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
 	default:
-		if response.Type == "errors/authentication_intent_error" {
-			return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
+		return false
+	}
+}
+
+// backoff returns the jittered exponential delay before the given retry attempt
+func (c *UnipileClientImpl) backoff(attempt int) time.Duration {
+	delay := c.retryPolicy.BaseDelay << attempt
+	if c.retryPolicy.MaxDelay > 0 && (delay <= 0 || delay > c.retryPolicy.MaxDelay) {
+		delay = c.retryPolicy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Equal jitter: keep half of the delay and randomize the other half
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func (c *UnipileClientImpl) logRetry(method, url string, attempt int, delay time.Duration, statusCode int, err error) {
+	if c.logger == nil {
+		return
+	}
+	entry := c.logger.WithFields(logrus.Fields{
+		"method":     method,
+		"url":        url,
+		"attempt":    attempt + 1,
+		"maxRetries": c.retryPolicy.MaxRetries,
+		"delay":      delay,
+		"statusCode": statusCode,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Warn("Retrying Unipile request")
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
 		}
-		return nil, fmt.Errorf("unipile API error (status %d): %s", resp.StatusCode, string(body))
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for the given delay or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
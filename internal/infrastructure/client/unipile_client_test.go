@@ -1,12 +1,16 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
 	"unipile-connector/internal/domain/service"
@@ -23,7 +27,7 @@ func TestUnipileClient_ListAccounts(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "test-key", httpClient: server.Client()}
-	resp, err := c.ListAccounts()
+	resp, err := c.ListAccounts(context.Background(), nil)
 	require.NoError(t, err)
 	require.Equal(t, "list", resp.Object)
 }
@@ -35,7 +39,7 @@ func TestUnipileClient_ListAccounts_ErrorStatus(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	_, err := c.ListAccounts()
+	_, err := c.ListAccounts(context.Background(), nil)
 	require.Error(t, err)
 }
 
@@ -47,7 +51,7 @@ func TestUnipileClient_GetAccount(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	resp, err := c.GetAccount("123")
+	resp, err := c.GetAccount(context.Background(), "123")
 	require.NoError(t, err)
 	require.Equal(t, "123", resp.ID)
 }
@@ -59,8 +63,9 @@ func TestUnipileClient_DeleteAccount_NotFound(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	err := c.DeleteAccount("123")
+	err := c.DeleteAccount(context.Background(), "123")
 	require.ErrorIs(t, err, service.ErrUnipileAccountNotFound)
+	require.NotErrorIs(t, err, service.ErrUnipileChatNotFound)
 }
 
 func TestUnipileClient_DeleteAccount_OtherError(t *testing.T) {
@@ -70,11 +75,11 @@ func TestUnipileClient_DeleteAccount_OtherError(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	err := c.DeleteAccount("123")
+	err := c.DeleteAccount(context.Background(), "123")
 	require.Error(t, err)
 }
 
-func TestUnipileClient_ConnectLinkedIn(t *testing.T) {
+func TestUnipileClient_ConnectAccount(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, http.MethodPost, r.Method)
 		body, _ := io.ReadAll(r.Body)
@@ -85,11 +90,88 @@ func TestUnipileClient_ConnectLinkedIn(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	resp, err := c.ConnectLinkedIn(&service.ConnectLinkedInRequest{Provider: "LINKEDIN"})
+	resp, err := c.ConnectAccount(context.Background(), &service.ConnectAccountRequest{Provider: "LINKEDIN"})
 	require.NoError(t, err)
 	require.Equal(t, http.StatusCreated, resp.Status)
 }
 
+func TestUnipileClient_ConnectWhatsApp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/accounts", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.JSONEq(t, `{"provider":"WHATSAPP"}`, string(body))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"Checkpoint","account_id":"wa-1","checkpoint":{"type":"QRCODE","qrcode":"2@abc"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ConnectWhatsApp(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "wa-1", resp.AccountID)
+	require.Equal(t, "QRCODE", resp.Checkpoint.Type)
+	require.Equal(t, "2@abc", resp.Checkpoint.QRCode)
+}
+
+func TestUnipileClient_ConnectWhatsApp_MissingQRCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"AccountCreated","account_id":"wa-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.ConnectWhatsApp(context.Background())
+	require.ErrorIs(t, err, service.ErrUnipileMissingQRCode)
+}
+
+func TestUnipileClient_ReconnectAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v1/accounts/acc-1", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.Contains(t, string(body), "\"provider\":\"LINKEDIN\"")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"object":"Checkpoint","account_id":"acc-1","checkpoint":{"type":"2FA"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ReconnectAccount(context.Background(), "acc-1", &service.ConnectAccountRequest{Provider: "LINKEDIN"})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.Status)
+	require.Equal(t, "acc-1", resp.AccountID)
+	require.NotNil(t, resp.Checkpoint)
+	require.Equal(t, "2FA", resp.Checkpoint.Type)
+}
+
+func TestUnipileClient_ReconnectAccount_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.ReconnectAccount(context.Background(), "missing", &service.ConnectAccountRequest{Provider: "LINKEDIN"})
+	require.ErrorIs(t, err, service.ErrUnipileAccountNotFound)
+}
+
+func TestUnipileClient_SolveCheckpoint_FollowUpCheckpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/accounts/checkpoint", r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"object":"Checkpoint","account_id":"123","checkpoint":{"type":"IN_APP_VALIDATION"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.SolveCheckpoint(context.Background(), &service.SolveCheckpointRequest{AccountID: "123", Code: "123456"})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Checkpoint)
+	require.Equal(t, "IN_APP_VALIDATION", resp.Checkpoint.Type)
+	require.NotEmpty(t, resp.RowBody)
+}
+
 func TestUnipileClient_SolveCheckpoint_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -98,6 +180,301 @@ func TestUnipileClient_SolveCheckpoint_Error(t *testing.T) {
 	t.Cleanup(server.Close)
 
 	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
-	_, err := c.SolveCheckpoint(&service.SolveCheckpointRequest{AccountID: "123"})
+	_, err := c.SolveCheckpoint(context.Background(), &service.SolveCheckpointRequest{AccountID: "123"})
 	require.ErrorIs(t, err, service.ErrUnipileInvalidCodeOrExpiredCheckpoint)
 }
+
+func TestUnipileClient_ResendCheckpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v1/accounts/checkpoint/resend", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.JSONEq(t, `{"provider":"LINKEDIN","account_id":"acc-1"}`, string(body))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"object":"Checkpoint","account_id":"acc-1","checkpoint":{"type":"OTP"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ResendCheckpoint(context.Background(), &service.ResendCheckpointRequest{Provider: "LINKEDIN", AccountID: "acc-1"})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Checkpoint)
+	require.Equal(t, "OTP", resp.Checkpoint.Type)
+	require.NotEmpty(t, resp.RowBody)
+}
+
+func TestUnipileClient_ResendCheckpoint_Expired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.ResendCheckpoint(context.Background(), &service.ResendCheckpointRequest{Provider: "LINKEDIN", AccountID: "acc-1"})
+	require.ErrorIs(t, err, service.ErrUnipileInvalidCodeOrExpiredCheckpoint)
+}
+
+func TestUnipileClient_CreateHostedAuthLink(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v1/hosted/accounts/link", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "create", body["type"])
+		require.Equal(t, "*", body["providers"])
+		require.Equal(t, serverURL, body["api_url"])
+		require.Equal(t, "42", body["name"])
+		require.Equal(t, "https://app.example.com/notify?state=abc", body["notify_url"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"HostedAuthURL","url":"https://account.unipile.com/pqr"}`))
+	}))
+	t.Cleanup(server.Close)
+	serverURL = server.URL
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.CreateHostedAuthLink(context.Background(), &service.HostedAuthLinkRequest{
+		Type:      "create",
+		Providers: "*",
+		ExpiresOn: "2025-01-01T00:00:00.000Z",
+		Name:      "42",
+		NotifyURL: "https://app.example.com/notify?state=abc",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://account.unipile.com/pqr", resp.URL)
+}
+
+func TestUnipileClient_GetAccountWithLongPolling_Cancelled(t *testing.T) {
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(released)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.GetAccountWithLongPolling(ctx, "123", 5*time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("expected outbound request to be cancelled")
+	}
+}
+
+func TestUnipileClient_GetAccount_DeadlineExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	t.Cleanup(cancel)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.GetAccount(ctx, "123")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestUnipileClient_GetAccount_RetriesWithRetryAfter(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(service.Account{ID: "123"})
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{
+		baseURL:     server.URL,
+		apiKey:      "key",
+		httpClient:  server.Client(),
+		retryPolicy: RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour, MaxDelay: time.Hour},
+		logger:      logrus.New(),
+	}
+	resp, err := c.GetAccount(context.Background(), "123")
+	require.NoError(t, err)
+	require.Equal(t, "123", resp.ID)
+	require.Equal(t, 3, attempts)
+}
+
+func TestUnipileClient_DeleteAccount_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{
+		baseURL:     server.URL,
+		apiKey:      "key",
+		httpClient:  server.Client(),
+		retryPolicy: RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		logger:      logrus.New(),
+	}
+	err := c.DeleteAccount(context.Background(), "123")
+	require.Error(t, err)
+	require.Equal(t, 3, attempts)
+}
+
+func TestUnipileClient_ConnectAccount_DoesNotRetryServerErrors(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{
+		baseURL:     server.URL,
+		apiKey:      "key",
+		httpClient:  server.Client(),
+		retryPolicy: RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		logger:      logrus.New(),
+	}
+	_, err := c.ConnectAccount(context.Background(), &service.ConnectAccountRequest{Provider: "LINKEDIN"})
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestUnipileClient_ConnectAccount_RetriesRateLimited(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		require.Contains(t, string(body), "\"provider\":\"LINKEDIN\"")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"account_id":"acc-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{
+		baseURL:     server.URL,
+		apiKey:      "key",
+		httpClient:  server.Client(),
+		retryPolicy: RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		logger:      logrus.New(),
+	}
+	resp, err := c.ConnectAccount(context.Background(), &service.ConnectAccountRequest{Provider: "LINKEDIN"})
+	require.NoError(t, err)
+	require.Equal(t, "acc-1", resp.AccountID)
+	require.Equal(t, 2, attempts)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("2")
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("soon")
+	require.False(t, ok)
+
+	_, ok = parseRetryAfter("")
+	require.False(t, ok)
+}
+
+func TestUnipileClient_ProblemJSONError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"status":401,"type":"errors/disconnected_account","title":"Disconnected account","detail":"The account appears to be disconnected from the provider service."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.GetAccount(context.Background(), "123")
+	require.ErrorIs(t, err, service.ErrUnipileDisconnectedAccount)
+	require.NotErrorIs(t, err, service.ErrUnipileInvalidCredentials)
+
+	var apiErr *service.UnipileError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	require.Equal(t, "errors/disconnected_account", apiErr.Type)
+	require.Equal(t, "Disconnected account", apiErr.Title)
+	require.Equal(t, "req-42", apiErr.RequestID)
+}
+
+func TestUnipileClient_PlainTextError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.ListAccounts(context.Background(), nil)
+	require.ErrorIs(t, err, service.ErrUnipileRateLimited)
+	require.Contains(t, err.Error(), "slow down")
+}
+
+func TestUnipileClient_ListAccounts_Pagination(t *testing.T) {
+	pages := map[string]service.AccountListResponse{
+		"":   {Object: "AccountList", Items: []service.Account{{ID: "a"}, {ID: "b"}}, Cursor: stringPtr("c1")},
+		"c1": {Object: "AccountList", Items: []service.Account{{ID: "c"}}, Cursor: stringPtr("c2")},
+		"c2": {Object: "AccountList", Items: []service.Account{{ID: "d"}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "2", r.URL.Query().Get("limit"))
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		require.True(t, ok)
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	accounts, err := service.ListAllAccounts(context.Background(), c, 2)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, account := range accounts {
+		ids = append(ids, account.ID)
+	}
+	require.Equal(t, []string{"a", "b", "c", "d"}, ids)
+}
+
+func TestUnipileClient_ForEachAccount_StopsOnCancel(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(service.AccountListResponse{Items: []service.Account{{ID: "a"}}, Cursor: stringPtr(fmt.Sprintf("c%d", requests))})
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+
+	var seen int
+	err := service.ForEachAccount(ctx, c, 1, func(account *service.Account) error {
+		seen++
+		if seen == 2 {
+			cancel()
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 2, seen)
+	require.Equal(t, 2, requests)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+
+	"unipile-connector/internal/domain/service"
+)
+
+// SendInvitation sends a LinkedIn connection invitation with an optional note
+func (c *UnipileClientImpl) SendInvitation(ctx context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/users/invite", c.baseURL)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.do(ctx, c.httpClient, http.MethodPost, url, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated:
+	default:
+		return nil, notFoundAs(newUnipileError(resp), service.ErrUnipileUserNotFound)
+	}
+
+	var response service.SendInvitationResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &response, nil
+}
+
+// ListSentInvitations lists one page of the pending invitations sent by an account
+func (c *UnipileClientImpl) ListSentInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error) {
+	query := paginationQuery(req.Limit, req.Cursor)
+	query.Set("account_id", req.AccountID)
+
+	var response service.SentInvitationListResponse
+	if err := c.getJSON(ctx, withQuery(fmt.Sprintf("%s/api/v1/users/invite/sent", c.baseURL), query), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListReceivedInvitations lists one page of the pending invitations received by an account
+func (c *UnipileClientImpl) ListReceivedInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.ReceivedInvitationListResponse, error) {
+	query := paginationQuery(req.Limit, req.Cursor)
+	query.Set("account_id", req.AccountID)
+
+	var response service.ReceivedInvitationListResponse
+	if err := c.getJSON(ctx, withQuery(fmt.Sprintf("%s/api/v1/users/invite/received", c.baseURL), query), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// WithdrawInvitation withdraws a pending invitation sent by an account
+func (c *UnipileClientImpl) WithdrawInvitation(ctx context.Context, accountID, invitationID string) error {
+	url := fmt.Sprintf("%s/api/v1/users/invite/sent/%s", c.baseURL, neturl.PathEscape(invitationID))
+	query := neturl.Values{}
+	query.Set("account_id", accountID)
+
+	resp, err := c.do(ctx, c.httpClient, http.MethodDelete, withQuery(url, query), nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.statusCode != http.StatusOK {
+		return notFoundAs(newUnipileError(resp), service.ErrUnipileInvitationNotFound)
+	}
+	return nil
+}
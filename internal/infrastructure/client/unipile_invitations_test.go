@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/service"
+)
+
+func TestUnipileClient_SendInvitation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v1/users/invite", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]string{"account_id": "acc-1", "provider_id": "ACoAA", "message": "Hi Jane"}, body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"UserInvitationSent","invitation_id":"inv-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.SendInvitation(context.Background(), &service.SendInvitationRequest{AccountID: "acc-1", ProviderID: "ACoAA", Message: "Hi Jane"})
+	require.NoError(t, err)
+	require.Equal(t, "inv-1", resp.InvitationID)
+}
+
+func TestUnipileClient_ListSentInvitations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/v1/users/invite/sent", r.URL.Path)
+		require.Equal(t, "acc-1", r.URL.Query().Get("account_id"))
+		require.Equal(t, "page-2", r.URL.Query().Get("cursor"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"InvitationList","items":[{"object":"UserInvitation","id":"inv-1","parsed_datetime":"2025-01-01T10:00:00.000Z","invited_user":"Jane Doe","invited_user_id":"ACoAA","invited_user_public_id":"jane-doe","invitation_text":"Hi Jane"}],"cursor":null}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ListSentInvitations(context.Background(), &service.ListInvitationsRequest{AccountID: "acc-1", Cursor: "page-2"})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "ACoAA", resp.Items[0].InvitedUserID)
+	require.Equal(t, "jane-doe", resp.Items[0].InvitedUserPublicID)
+	require.Nil(t, resp.Cursor)
+}
+
+func TestUnipileClient_ListReceivedInvitations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/users/invite/received", r.URL.Path)
+		require.Equal(t, "acc-1", r.URL.Query().Get("account_id"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"InvitationList","items":[{"object":"UserInvitation","id":"inv-2","invitation_text":"Let's connect","inviter":{"inviter_id":"ACoBB","inviter_name":"John Smith","inviter_public_identifier":"john-smith"}}],"cursor":"page-2"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ListReceivedInvitations(context.Background(), &service.ListInvitationsRequest{AccountID: "acc-1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "ACoBB", resp.Items[0].Inviter.InviterID)
+	require.Equal(t, "John Smith", resp.Items[0].Inviter.InviterName)
+	require.NotNil(t, resp.Cursor)
+}
+
+func TestUnipileClient_WithdrawInvitation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "/api/v1/users/invite/sent/inv-1", r.URL.Path)
+		require.Equal(t, "acc-1", r.URL.Query().Get("account_id"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"UserInvitationCanceled"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	require.NoError(t, c.WithdrawInvitation(context.Background(), "acc-1", "inv-1"))
+}
+
+func TestUnipileClient_WithdrawInvitation_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"type":"errors/resource_not_found","title":"Resource not found."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	err := c.WithdrawInvitation(context.Background(), "acc-1", "missing")
+	require.ErrorIs(t, err, service.ErrUnipileInvitationNotFound)
+}
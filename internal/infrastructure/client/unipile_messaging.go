@@ -0,0 +1,211 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"unipile-connector/internal/domain/service"
+)
+
+// ListChats lists one page of chats from Unipile API
+func (c *UnipileClientImpl) ListChats(ctx context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+	query := paginationQuery(req.Limit, req.Cursor)
+	if req.AccountID != "" {
+		query.Set("account_id", req.AccountID)
+	}
+	if req.Unread != nil {
+		query.Set("unread", strconv.FormatBool(*req.Unread))
+	}
+	if req.Before != "" {
+		query.Set("before", req.Before)
+	}
+	if req.After != "" {
+		query.Set("after", req.After)
+	}
+
+	var response service.ChatListResponse
+	if err := c.getJSON(ctx, withQuery(fmt.Sprintf("%s/api/v1/chats", c.baseURL), query), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetChat gets a chat from Unipile API
+func (c *UnipileClientImpl) GetChat(ctx context.Context, chatID string) (*service.Chat, error) {
+	url := fmt.Sprintf("%s/api/v1/chats/%s", c.baseURL, neturl.PathEscape(chatID))
+
+	var response service.Chat
+	if err := c.getJSON(ctx, url, &response); err != nil {
+		return nil, notFoundAs(err, service.ErrUnipileChatNotFound)
+	}
+	return &response, nil
+}
+
+// ListChatAttendees lists one page of the attendees of a chat from Unipile API
+func (c *UnipileClientImpl) ListChatAttendees(ctx context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/chats/%s/attendees", c.baseURL, neturl.PathEscape(req.ChatID))
+
+	var response service.ChatAttendeeListResponse
+	if err := c.getJSON(ctx, withQuery(url, paginationQuery(req.Limit, req.Cursor)), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListMessages lists one page of the messages of a chat from Unipile API, newest first
+func (c *UnipileClientImpl) ListMessages(ctx context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/chats/%s/messages", c.baseURL, neturl.PathEscape(req.ChatID))
+	query := paginationQuery(req.Limit, req.Cursor)
+	if req.Before != "" {
+		query.Set("before", req.Before)
+	}
+	if req.After != "" {
+		query.Set("after", req.After)
+	}
+
+	var response service.MessageListResponse
+	if err := c.getJSON(ctx, withQuery(url, query), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SendMessage sends a message with optional attachments in an existing chat
+func (c *UnipileClientImpl) SendMessage(ctx context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/chats/%s/messages", c.baseURL, neturl.PathEscape(req.ChatID))
+
+	fields := neturl.Values{}
+	if req.Text != "" {
+		fields.Set("text", req.Text)
+	}
+
+	var response service.SendMessageResponse
+	if err := c.postMultipart(ctx, url, fields, req.Attachments, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StartChat starts a new chat with the attendees and sends the first message
+func (c *UnipileClientImpl) StartChat(ctx context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/chats", c.baseURL)
+
+	fields := neturl.Values{}
+	fields.Set("account_id", req.AccountID)
+	for _, attendeeID := range req.AttendeeProviderIDs {
+		fields.Add("attendees_ids", attendeeID)
+	}
+	if req.Text != "" {
+		fields.Set("text", req.Text)
+	}
+	if req.Subject != "" {
+		fields.Set("subject", req.Subject)
+	}
+	if req.InMail {
+		fields.Set("linkedin[inmail]", "true")
+	}
+
+	var response service.StartChatResponse
+	if err := c.postMultipart(ctx, url, fields, req.Attachments, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// postMultipart sends a multipart/form-data POST request and unmarshals a 200/201 response into response
+func (c *UnipileClientImpl) postMultipart(ctx context.Context, url string, fields neturl.Values, attachments []service.Attachment, response any) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			if err := writer.WriteField(name, value); err != nil {
+				return fmt.Errorf("failed to write form field: %w", err)
+			}
+		}
+	}
+	for _, attachment := range attachments {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachments"; filename="%s"`, escapeQuotes(attachment.FileName)))
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("failed to create attachment part: %w", err)
+		}
+		if _, err := part.Write(attachment.Content); err != nil {
+			return fmt.Errorf("failed to write attachment: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart body: %w", err)
+	}
+
+	resp, err := c.send(ctx, c.httpClient, http.MethodPost, url, body.Bytes(), writer.FormDataContentType())
+	if err != nil {
+		return err
+	}
+
+	switch resp.statusCode {
+	case http.StatusOK, http.StatusCreated:
+	default:
+		return newUnipileError(resp)
+	}
+
+	if err := json.Unmarshal(resp.body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// escapeQuotes escapes a file name for a Content-Disposition header, as mime/multipart does
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
+
+// getJSON sends a GET request and unmarshals a 200 response into response
+func (c *UnipileClientImpl) getJSON(ctx context.Context, url string, response any) error {
+	resp, err := c.do(ctx, c.httpClient, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.statusCode != http.StatusOK {
+		return newUnipileError(resp)
+	}
+
+	if err := json.Unmarshal(resp.body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// paginationQuery builds the query of a paginated list request
+func paginationQuery(limit int, cursor string) neturl.Values {
+	query := neturl.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return query
+}
+
+// withQuery appends the encoded query to url, if any
+func withQuery(url string, query neturl.Values) string {
+	if len(query) == 0 {
+		return url
+	}
+	return url + "?" + query.Encode()
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/service"
+)
+
+func TestUnipileClient_ListChats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/v1/chats", r.URL.Path)
+		require.Equal(t, "acc-1", r.URL.Query().Get("account_id"))
+		require.Equal(t, "20", r.URL.Query().Get("limit"))
+		require.Equal(t, "page-2", r.URL.Query().Get("cursor"))
+		require.Equal(t, "true", r.URL.Query().Get("unread"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"ChatList","items":[{"object":"Chat","id":"chat-1","account_id":"acc-1","name":null,"unread_count":2,"timestamp":"2025-01-01T10:00:00.000Z"}],"cursor":"page-3"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	unread := true
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ListChats(context.Background(), &service.ListChatsRequest{AccountID: "acc-1", Limit: 20, Cursor: "page-2", Unread: &unread})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "chat-1", resp.Items[0].ID)
+	require.Equal(t, 2, resp.Items[0].UnreadCount)
+	require.NotNil(t, resp.Cursor)
+	require.Equal(t, "page-3", *resp.Cursor)
+}
+
+func TestUnipileClient_GetChat_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"type":"errors/resource_not_found","title":"Resource not found."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.GetChat(context.Background(), "missing")
+	require.ErrorIs(t, err, service.ErrUnipileChatNotFound)
+	require.NotErrorIs(t, err, service.ErrUnipileAccountNotFound)
+}
+
+func TestUnipileClient_ListChatAttendees(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/chats/chat-1/attendees", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"ChatAttendeeList","items":[{"object":"ChatAttendee","id":"att-1","provider_id":"ACoAA","name":"Jane Doe","is_self":0}],"cursor":null}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ListChatAttendees(context.Background(), &service.ListChatAttendeesRequest{ChatID: "chat-1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "Jane Doe", resp.Items[0].Name)
+	require.Nil(t, resp.Cursor)
+}
+
+func TestUnipileClient_ListMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/chats/chat-1/messages", r.URL.Path)
+		require.Equal(t, "page-2", r.URL.Query().Get("cursor"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"MessageList","items":[{"object":"Message","id":"msg-1","chat_id":"chat-1","text":"Hello","is_sender":1,"attachments":[{"id":"att-1","type":"img","unavailable":false}]}],"cursor":null}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.ListMessages(context.Background(), &service.ListMessagesRequest{ChatID: "chat-1", Cursor: "page-2"})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "Hello", resp.Items[0].Text)
+	require.Equal(t, 1, resp.Items[0].IsSender)
+	require.Len(t, resp.Items[0].Attachments, 1)
+}
+
+func TestUnipileClient_SendMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/v1/chats/chat-1/messages", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		require.Equal(t, "Hello", r.FormValue("text"))
+		files := r.MultipartForm.File["attachments"]
+		require.Len(t, files, 1)
+		require.Equal(t, "deck.pdf", files[0].Filename)
+		require.Equal(t, "application/pdf", files[0].Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"MessageSent","message_id":"msg-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.SendMessage(context.Background(), &service.SendMessageRequest{
+		ChatID:      "chat-1",
+		Text:        "Hello",
+		Attachments: []service.Attachment{{FileName: "deck.pdf", ContentType: "application/pdf", Content: []byte("%PDF")}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "msg-1", resp.MessageID)
+}
+
+func TestUnipileClient_StartChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/chats", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		require.Equal(t, "acc-1", r.FormValue("account_id"))
+		require.Equal(t, []string{"ACoAA1", "ACoAA2"}, r.MultipartForm.Value["attendees_ids"])
+		require.Equal(t, "Hi there", r.FormValue("text"))
+		require.Equal(t, "true", r.FormValue("linkedin[inmail]"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"object":"ChatStarted","chat_id":"chat-9","message_id":"msg-9"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	resp, err := c.StartChat(context.Background(), &service.StartChatRequest{
+		AccountID:           "acc-1",
+		AttendeeProviderIDs: []string{"ACoAA1", "ACoAA2"},
+		Text:                "Hi there",
+		InMail:              true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "chat-9", resp.ChatID)
+	require.Equal(t, "msg-9", resp.MessageID)
+}
+
+func TestUnipileClient_StartChat_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"status":403,"type":"errors/insufficient_privileges","title":"Insufficient privileges"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.StartChat(context.Background(), &service.StartChatRequest{AccountID: "acc-1", AttendeeProviderIDs: []string{"ACoAA1"}, Text: "Hi"})
+	require.ErrorIs(t, err, service.ErrUnipileInsufficientPermissions)
+}
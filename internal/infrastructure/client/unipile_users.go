@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+
+	"unipile-connector/internal/domain/service"
+)
+
+// GetUserProfile gets the profile of a user from Unipile API, as seen by the account.
+// The identifier is the provider ID or the public identifier of the user, e.g. the LinkedIn vanity name.
+func (c *UnipileClientImpl) GetUserProfile(ctx context.Context, accountID, identifier string) (*service.UserProfile, error) {
+	url := fmt.Sprintf("%s/api/v1/users/%s", c.baseURL, neturl.PathEscape(identifier))
+	query := neturl.Values{}
+	query.Set("account_id", accountID)
+	// The experience section carries the company of a LinkedIn profile
+	query.Set("linkedin_sections", "experience")
+
+	var response service.UserProfile
+	if err := c.getJSON(ctx, withQuery(url, query), &response); err != nil {
+		return nil, notFoundAs(err, service.ErrUnipileUserNotFound)
+	}
+	return &response, nil
+}
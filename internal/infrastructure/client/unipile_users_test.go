@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"unipile-connector/internal/domain/service"
+)
+
+func TestUnipileClient_GetUserProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/v1/users/jane-doe", r.URL.Path)
+		require.Equal(t, "acc-1", r.URL.Query().Get("account_id"))
+		require.Equal(t, "experience", r.URL.Query().Get("linkedin_sections"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"UserProfile","provider":"LINKEDIN","provider_id":"ACoAA","public_identifier":"jane-doe","first_name":"Jane","last_name":"Doe","headline":"Engineer","location":"Paris","network_distance":"SECOND_DEGREE","work_experience":[{"position":"Intern","company":"Old Co","start":"1/1/2018","end":"1/1/2019"},{"position":"Engineer","company":"Acme","start":"1/1/2020","end":null}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	profile, err := c.GetUserProfile(context.Background(), "acc-1", "jane-doe")
+	require.NoError(t, err)
+	require.Equal(t, "ACoAA", profile.ProviderID)
+	require.Equal(t, "jane-doe", profile.PublicIdentifier)
+	require.Equal(t, "Engineer", profile.Headline)
+	require.Equal(t, "Paris", profile.Location)
+	require.Equal(t, "Acme", profile.CurrentCompany())
+}
+
+func TestUnipileClient_GetUserProfile_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"type":"errors/resource_not_found","title":"Resource not found."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &UnipileClientImpl{baseURL: server.URL, apiKey: "key", httpClient: server.Client()}
+	_, err := c.GetUserProfile(context.Background(), "acc-1", "missing")
+	require.ErrorIs(t, err, service.ErrUnipileUserNotFound)
+}
@@ -5,24 +5,27 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application (for backward compatibility)
 type Config struct {
-	Server   ServerConfig
-	Log      LogConfig
-	Database DatabaseConfig
-	Unipile  UnipileConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
+	Server     ServerConfig
+	Log        LogConfig
+	Database   DatabaseConfig
+	Unipile    UnipileConfig
+	Checkpoint CheckpointConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port      string
+	Host      string
+	PublicURL string // URL the server is reachable at from outside, used in links handed to Unipile
 }
 
 // LogConfig holds log configuration
@@ -42,8 +45,29 @@ type DatabaseConfig struct {
 
 // UnipileConfig holds Unipile API configuration
 type UnipileConfig struct {
-	BaseURL string
-	APIKey  string
+	BaseURL        string
+	APIKey         string
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	ReconcileInterval time.Duration // Interval between account reconciliation runs
+	ChatSyncInterval  time.Duration // Interval between chat and message sync runs
+	WebhookSecret     string        // Shared secret Unipile sends with webhook calls
+
+	HostedAuthSecret  string        // Secret signing the state of hosted auth callbacks, must differ from the JWT secret
+	HostedAuthLinkTTL time.Duration // Lifetime of hosted auth wizard links
+
+	ProfileCacheTTL time.Duration // How long looked up user profiles are served from the local cache
+}
+
+// CheckpointConfig holds authentication checkpoint configuration
+type CheckpointConfig struct {
+	TTL                   time.Duration            // Default checkpoint lifetime
+	TTLs                  map[string]time.Duration // Checkpoint lifetime per checkpoint type, overrides TTL
+	SweepInterval         time.Duration            // Interval between expired checkpoint sweeps
+	DeleteExpiredAccounts bool                     // Delete the Unipile account when its checkpoint expires
+	MaxAttempts           int                      // Failed code submissions before a checkpoint is locked
 }
 
 // RedisConfig holds Redis configuration
@@ -98,6 +122,10 @@ func Load(path string) (*Config, error) {
 	if len(config.Server.Host) == 0 {
 		config.Server.Host = "0.0.0.0"
 	}
+	config.Server.PublicURL = strings.TrimRight(v.GetString("server_public_url"), "/")
+	if config.Server.PublicURL == "" {
+		config.Server.PublicURL = "http://localhost:" + config.Server.Port
+	}
 
 	// log
 	config.Log.Level = v.GetString("log_level")
@@ -137,6 +165,59 @@ func Load(path string) (*Config, error) {
 	if config.Unipile.BaseURL == "" {
 		config.Unipile.BaseURL = "https://api.unipile.com"
 	}
+	config.Unipile.MaxRetries = v.GetInt("unipile_max_retries")
+	config.Unipile.RetryBaseDelay = v.GetDuration("unipile_retry_base_delay")
+	config.Unipile.RetryMaxDelay = v.GetDuration("unipile_retry_max_delay")
+	if !v.IsSet("unipile_max_retries") {
+		config.Unipile.MaxRetries = 3
+	}
+	if config.Unipile.RetryBaseDelay == 0 {
+		config.Unipile.RetryBaseDelay = 500 * time.Millisecond
+	}
+	if config.Unipile.RetryMaxDelay == 0 {
+		config.Unipile.RetryMaxDelay = 10 * time.Second
+	}
+	config.Unipile.WebhookSecret = v.GetString("unipile_webhook_secret")
+	config.Unipile.ReconcileInterval = v.GetDuration("unipile_reconcile_interval")
+	if config.Unipile.ReconcileInterval == 0 {
+		config.Unipile.ReconcileInterval = 15 * time.Minute
+	}
+	config.Unipile.ChatSyncInterval = v.GetDuration("unipile_chat_sync_interval")
+	if config.Unipile.ChatSyncInterval == 0 {
+		config.Unipile.ChatSyncInterval = 5 * time.Minute
+	}
+	config.Unipile.HostedAuthSecret = v.GetString("unipile_hosted_auth_secret")
+	config.Unipile.HostedAuthLinkTTL = v.GetDuration("unipile_hosted_auth_link_ttl")
+	if config.Unipile.HostedAuthLinkTTL == 0 {
+		config.Unipile.HostedAuthLinkTTL = time.Hour
+	}
+	config.Unipile.ProfileCacheTTL = v.GetDuration("unipile_profile_cache_ttl")
+	if config.Unipile.ProfileCacheTTL == 0 {
+		config.Unipile.ProfileCacheTTL = 24 * time.Hour
+	}
+
+	// checkpoint
+	config.Checkpoint.TTL = v.GetDuration("checkpoint_ttl")
+	if config.Checkpoint.TTL == 0 {
+		config.Checkpoint.TTL = 270 * time.Second // 4.5 minutes
+	}
+	ttls, err := parseDurationMap(v.GetString("checkpoint_ttls"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHECKPOINT_TTLS: %w", err)
+	}
+	config.Checkpoint.TTLs = ttls
+	config.Checkpoint.SweepInterval = v.GetDuration("checkpoint_sweep_interval")
+	if config.Checkpoint.SweepInterval == 0 {
+		config.Checkpoint.SweepInterval = time.Minute
+	}
+	config.Checkpoint.DeleteExpiredAccounts = v.GetBool("checkpoint_delete_expired_accounts")
+	if !v.IsSet("checkpoint_delete_expired_accounts") {
+		config.Checkpoint.DeleteExpiredAccounts = true
+	}
+	config.Checkpoint.MaxAttempts = v.GetInt("checkpoint_max_attempts")
+	if config.Checkpoint.MaxAttempts <= 0 {
+		config.Checkpoint.MaxAttempts = 5
+	}
 
 	// redis
 	config.Redis.Host = v.GetString("redis_host")
@@ -156,3 +237,24 @@ func Load(path string) (*Config, error) {
 
 	return &config, nil
 }
+
+// parseDurationMap parses a comma separated list of key=duration pairs, e.g. "OTP=5m,IN_APP_VALIDATION=10m"
+func parseDurationMap(value string) (map[string]time.Duration, error) {
+	durations := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, rawDuration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=duration, got %q", pair)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(rawDuration))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for %s: %w", key, err)
+		}
+		durations[strings.TrimSpace(key)] = duration
+	}
+	return durations, nil
+}
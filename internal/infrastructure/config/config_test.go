@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,6 +14,7 @@ func TestLoadWithDefaults(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "0.0.0.0", config.Server.Host)
 	require.Equal(t, "8080", config.Server.Port)
+	require.Equal(t, "http://localhost:8080", config.Server.PublicURL)
 	require.Equal(t, "warn", config.Log.Level)
 	require.Equal(t, "localhost", config.Database.Host)
 	require.Equal(t, 5432, config.Database.Port)
@@ -21,6 +23,18 @@ func TestLoadWithDefaults(t *testing.T) {
 	require.Equal(t, "unipile_connector", config.Database.DBName)
 	require.Equal(t, "disable", config.Database.SSLMode)
 	require.Equal(t, "https://api.unipile.com", config.Unipile.BaseURL)
+	require.Equal(t, 3, config.Unipile.MaxRetries)
+	require.Equal(t, 500*time.Millisecond, config.Unipile.RetryBaseDelay)
+	require.Equal(t, 10*time.Second, config.Unipile.RetryMaxDelay)
+	require.Equal(t, 15*time.Minute, config.Unipile.ReconcileInterval)
+	require.Equal(t, 5*time.Minute, config.Unipile.ChatSyncInterval)
+	require.Equal(t, time.Hour, config.Unipile.HostedAuthLinkTTL)
+	require.Equal(t, 24*time.Hour, config.Unipile.ProfileCacheTTL)
+	require.Equal(t, 270*time.Second, config.Checkpoint.TTL)
+	require.Empty(t, config.Checkpoint.TTLs)
+	require.Equal(t, time.Minute, config.Checkpoint.SweepInterval)
+	require.True(t, config.Checkpoint.DeleteExpiredAccounts)
+	require.Equal(t, 5, config.Checkpoint.MaxAttempts)
 	require.Equal(t, "localhost", config.Redis.Host)
 	require.Equal(t, 6379, config.Redis.Port)
 }
@@ -30,6 +44,7 @@ func TestLoadFromFile(t *testing.T) {
 	configPath := filepath.Join(dir, ".env")
 	envContent := `SERVER_HOST=127.0.0.1
 SERVER_PORT=3000
+SERVER_PUBLIC_URL=https://connector.example.com/
 LOG_LEVEL=info
 DB_HOST=db.example.com
 DB_PORT=6543
@@ -39,6 +54,20 @@ DB_NAME=custom_db
 DB_SSLMODE=require
 UNIPILE_BASE_URL=https://custom.unipile
 UNIPILE_API_KEY=apikey
+UNIPILE_MAX_RETRIES=0
+UNIPILE_RETRY_BASE_DELAY=250ms
+UNIPILE_RETRY_MAX_DELAY=5s
+UNIPILE_RECONCILE_INTERVAL=1m
+UNIPILE_CHAT_SYNC_INTERVAL=2m
+UNIPILE_WEBHOOK_SECRET=webhooksecret
+UNIPILE_HOSTED_AUTH_SECRET=hostedauthsecret
+UNIPILE_HOSTED_AUTH_LINK_TTL=30m
+UNIPILE_PROFILE_CACHE_TTL=6h
+CHECKPOINT_TTL=3m
+CHECKPOINT_TTLS=IN_APP_VALIDATION=10m, OTP=90s
+CHECKPOINT_SWEEP_INTERVAL=30s
+CHECKPOINT_DELETE_EXPIRED_ACCOUNTS=false
+CHECKPOINT_MAX_ATTEMPTS=3
 REDIS_HOST=redis.example.com
 REDIS_PORT=6380
 REDIS_PASSWORD=redispass
@@ -52,6 +81,7 @@ JWT_ISSUER=test-issuer
 	require.NoError(t, err)
 	require.Equal(t, "127.0.0.1", config.Server.Host)
 	require.Equal(t, "3000", config.Server.Port)
+	require.Equal(t, "https://connector.example.com", config.Server.PublicURL)
 	require.Equal(t, "info", config.Log.Level)
 	require.Equal(t, "db.example.com", config.Database.Host)
 	require.Equal(t, 6543, config.Database.Port)
@@ -61,6 +91,20 @@ JWT_ISSUER=test-issuer
 	require.Equal(t, "require", config.Database.SSLMode)
 	require.Equal(t, "https://custom.unipile", config.Unipile.BaseURL)
 	require.Equal(t, "apikey", config.Unipile.APIKey)
+	require.Equal(t, 0, config.Unipile.MaxRetries)
+	require.Equal(t, 250*time.Millisecond, config.Unipile.RetryBaseDelay)
+	require.Equal(t, 5*time.Second, config.Unipile.RetryMaxDelay)
+	require.Equal(t, time.Minute, config.Unipile.ReconcileInterval)
+	require.Equal(t, 2*time.Minute, config.Unipile.ChatSyncInterval)
+	require.Equal(t, "webhooksecret", config.Unipile.WebhookSecret)
+	require.Equal(t, "hostedauthsecret", config.Unipile.HostedAuthSecret)
+	require.Equal(t, 30*time.Minute, config.Unipile.HostedAuthLinkTTL)
+	require.Equal(t, 6*time.Hour, config.Unipile.ProfileCacheTTL)
+	require.Equal(t, 3*time.Minute, config.Checkpoint.TTL)
+	require.Equal(t, map[string]time.Duration{"IN_APP_VALIDATION": 10 * time.Minute, "OTP": 90 * time.Second}, config.Checkpoint.TTLs)
+	require.Equal(t, 30*time.Second, config.Checkpoint.SweepInterval)
+	require.False(t, config.Checkpoint.DeleteExpiredAccounts)
+	require.Equal(t, 3, config.Checkpoint.MaxAttempts)
 	require.Equal(t, "redis.example.com", config.Redis.Host)
 	require.Equal(t, 6380, config.Redis.Port)
 	require.Equal(t, "redispass", config.Redis.Password)
@@ -68,3 +112,12 @@ JWT_ISSUER=test-issuer
 	require.Equal(t, "supersecret", config.JWT.SecretKey)
 	require.Equal(t, "test-issuer", config.JWT.Issuer)
 }
+
+func TestLoadInvalidCheckpointTTLs(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(configPath, []byte("CHECKPOINT_TTLS=OTP\n"), 0o600))
+
+	_, err := Load(configPath)
+	require.Error(t, err)
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AccountStatusHistoryAudit adds the transition details to the account status history
+var AccountStatusHistoryAudit = &gormigrate.Migration{
+
+	ID: "002_account_status_history_audit",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+					ALTER TABLE account_status_histories
+						ADD COLUMN IF NOT EXISTS previous_status VARCHAR(100),
+						ADD COLUMN IF NOT EXISTS cause VARCHAR(50),
+						ADD COLUMN IF NOT EXISTS payload JSONB;
+				`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+					ALTER TABLE account_status_histories
+						DROP COLUMN IF EXISTS previous_status,
+						DROP COLUMN IF EXISTS cause,
+						DROP COLUMN IF EXISTS payload;
+				`).Error
+	},
+}
@@ -0,0 +1,99 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// ChatsAndMessages adds the local store of the chats and messages synced from Unipile
+var ChatsAndMessages = &gormigrate.Migration{
+
+	ID: "004_chats_and_messages",
+	Migrate: func(tx *gorm.DB) error {
+		// Create chats table
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS chats (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						chat_id VARCHAR(255) NOT NULL,
+						provider_id VARCHAR(255),
+						attendee_provider_id VARCHAR(255),
+						name TEXT,
+						type INTEGER NOT NULL DEFAULT 0,
+						subject TEXT,
+						content_type VARCHAR(100),
+						unread_count INTEGER NOT NULL DEFAULT 0,
+						archived BOOLEAN NOT NULL DEFAULT FALSE,
+						read_only BOOLEAN NOT NULL DEFAULT FALSE,
+						last_message_at TIMESTAMP NOT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create messages table
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS messages (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+						message_id VARCHAR(255) NOT NULL,
+						provider_id VARCHAR(255),
+						sender_id VARCHAR(255),
+						sender_attendee_id VARCHAR(255),
+						text TEXT,
+						sent_at TIMESTAMP NOT NULL,
+						is_sender BOOLEAN NOT NULL DEFAULT FALSE,
+						seen BOOLEAN NOT NULL DEFAULT FALSE,
+						edited BOOLEAN NOT NULL DEFAULT FALSE,
+						deleted BOOLEAN NOT NULL DEFAULT FALSE,
+						attachments JSONB,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create chat_sync_states table
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS chat_sync_states (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						cursor TEXT,
+						backfilled_at TIMESTAMP NULL,
+						last_synced_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create indexes, the unique ones back the upserts of the sync
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_chats_account_id_chat_id ON chats(account_id, chat_id);`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_chats_account_id_last_message_at ON chats(account_id, last_message_at DESC, id DESC);`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_chat_id_message_id ON messages(chat_id, message_id);`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_chat_id_sent_at ON messages(chat_id, sent_at DESC, id DESC);`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_sync_states_account_id ON chat_sync_states(account_id);`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Exec(`DROP TABLE IF EXISTS messages;`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DROP TABLE IF EXISTS chats;`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`DROP TABLE IF EXISTS chat_sync_states;`).Error
+	},
+}
@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// CheckpointAttempts adds the failed code submission counters of checkpoints
+var CheckpointAttempts = &gormigrate.Migration{
+
+	ID: "003_checkpoint_attempts",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS checkpoint_attempts (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						status_history_id INTEGER NOT NULL REFERENCES account_status_histories(id) ON DELETE CASCADE,
+						checkpoint VARCHAR(100) NOT NULL,
+						failed_attempts INTEGER NOT NULL DEFAULT 0,
+						locked_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_checkpoint_attempts_status_history_id ON checkpoint_attempts(status_history_id);`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS checkpoint_attempts;`).Error
+	},
+}
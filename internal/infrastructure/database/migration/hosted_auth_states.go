@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// HostedAuthStates adds the states issued with hosted auth links, so each link attaches a single account
+var HostedAuthStates = &gormigrate.Migration{
+
+	ID: "008_hosted_auth_states",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS hosted_auth_states (
+						id SERIAL PRIMARY KEY,
+						nonce VARCHAR(64) NOT NULL,
+						user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+						account_id VARCHAR(255) NOT NULL DEFAULT '',
+						expires_at TIMESTAMP NOT NULL,
+						used_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_hosted_auth_states_nonce ON hosted_auth_states(nonce);`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS hosted_auth_states;`).Error
+	},
+}
@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// Invitations adds the local tracking of the connection invitations sent and received by the accounts
+var Invitations = &gormigrate.Migration{
+
+	ID: "007_invitations",
+	Migrate: func(tx *gorm.DB) error {
+		// Create invitations table
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS invitations (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						invitation_id VARCHAR(255) NOT NULL,
+						direction VARCHAR(20) NOT NULL,
+						status VARCHAR(20) NOT NULL,
+						provider_id VARCHAR(255),
+						public_identifier VARCHAR(255),
+						name TEXT,
+						headline TEXT,
+						message TEXT,
+						sent_at TIMESTAMP NOT NULL,
+						accepted_at TIMESTAMP NULL,
+						withdrawn_at TIMESTAMP NULL,
+						closed_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create indexes
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_account_id_invitation_id ON invitations(account_id, invitation_id);`).Error; err != nil {
+			return err
+		}
+		// Pending invitations are looked up by invited user when a new relation is reported
+		return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_invitations_account_id_provider_id ON invitations(account_id, provider_id);`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS invitations;`).Error
+	},
+}
@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// MessageSearch adds the full-text search of message texts and chat attendee names.
+// The 'simple' configuration does not stem words, so names and companies match as typed in any language.
+var MessageSearch = &gormigrate.Migration{
+
+	ID: "005_message_search",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE chats ADD COLUMN IF NOT EXISTS attendee_names TEXT;`).Error; err != nil {
+			return err
+		}
+
+		// Create search vectors, generated so every write keeps them current
+		if err := tx.Exec(`
+					ALTER TABLE chats ADD COLUMN IF NOT EXISTS search_vector tsvector
+						GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(attendee_names, ''))) STORED;
+				`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+					ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+						GENERATED ALWAYS AS (to_tsvector('simple', coalesce(text, ''))) STORED;
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create indexes
+		if err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_chats_search_vector ON chats USING GIN(search_vector);`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_search_vector ON messages USING GIN(search_vector);`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`ALTER TABLE chats DROP COLUMN IF EXISTS search_vector;`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`ALTER TABLE chats DROP COLUMN IF EXISTS attendee_names;`).Error
+	},
+}
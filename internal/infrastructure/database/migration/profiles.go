@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// Profiles adds the local cache of the user profiles looked up through the accounts
+var Profiles = &gormigrate.Migration{
+
+	ID: "006_profiles",
+	Migrate: func(tx *gorm.DB) error {
+		// Create profiles table
+		if err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS profiles (
+						id SERIAL PRIMARY KEY,
+						account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+						provider VARCHAR(50),
+						provider_id VARCHAR(255) NOT NULL,
+						public_identifier VARCHAR(255),
+						first_name TEXT,
+						last_name TEXT,
+						headline TEXT,
+						company TEXT,
+						location TEXT,
+						profile_picture_url TEXT,
+						public_profile_url TEXT,
+						network_distance VARCHAR(50),
+						fetched_at TIMESTAMP NOT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error; err != nil {
+			return err
+		}
+
+		// Create indexes, profiles are looked up by provider ID or by public identifier ignoring case
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_profiles_account_id_provider_id ON profiles(account_id, provider_id);`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE INDEX IF NOT EXISTS idx_profiles_account_id_public_identifier ON profiles(account_id, lower(public_identifier));`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS profiles;`).Error
+	},
+}
@@ -43,6 +43,13 @@ func RunMigrations(db *gorm.DB, log *logrus.Logger) error {
 
 	if err := gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{
 		migration.InitialSchema,
+		migration.AccountStatusHistoryAudit,
+		migration.CheckpointAttempts,
+		migration.ChatsAndMessages,
+		migration.MessageSearch,
+		migration.Profiles,
+		migration.Invitations,
+		migration.HostedAuthStates,
 	}).Migrate(); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
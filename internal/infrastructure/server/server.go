@@ -70,6 +70,11 @@ func (s *Impl) setupRoutes() {
 		api.POST("/auth/login", s.handlers.AuthHandler.Login)
 		api.POST("/auth/refresh", s.handlers.AuthHandler.RefreshToken)
 
+		// Webhook routes (authenticated with a shared secret)
+		api.POST("/webhooks/unipile", s.middlewares.WebhookAuthMiddleware, s.handlers.WebhookHandler.UnipileWebhook)
+		// Hosted auth callback (authenticated with the signed state in the query string)
+		api.POST("/webhooks/unipile/hosted-auth", s.handlers.WebhookHandler.HostedAuthCallback)
+
 		// Protected routes
 		protected := api.Group("/")
 		protected.Use(s.middlewares.JWTMiddleware)
@@ -79,10 +84,32 @@ func (s *Impl) setupRoutes() {
 			protected.POST("/auth/logout", s.handlers.AuthHandler.Logout)
 			// Account routes
 			protected.GET("/accounts", s.handlers.AccountHandler.ListUserAccounts)
-			protected.POST("/accounts/linkedin/connect", s.handlers.AccountHandler.ConnectLinkedIn)
-			protected.POST("/accounts/linkedin/checkpoint", s.handlers.AccountHandler.SolveCheckpoint)
-			protected.POST("/accounts/linkedin/wait-validation", s.handlers.AccountHandler.WaitForAccountValidation)
+			protected.GET("/accounts/events", s.handlers.EventHandler.StreamAccountEvents)
+			protected.GET("/accounts/providers", s.handlers.AccountHandler.ListProviders)
+			protected.POST("/accounts/hosted-link", s.handlers.AccountHandler.CreateHostedAuthLink)
+			// Provider routes, the former /accounts/linkedin/... routes are served with provider "linkedin".
+			// Gin allows one wildcard name per path segment, so :id is the provider name here and the account ID on account routes.
+			protected.POST("/accounts/:id/connect", s.handlers.AccountHandler.ConnectAccount)
+			protected.POST("/accounts/:id/checkpoint", s.handlers.AccountHandler.SolveCheckpoint)
+			protected.POST("/accounts/:id/checkpoint/resend", s.handlers.AccountHandler.ResendCheckpoint)
+			protected.POST("/accounts/:id/wait-validation", s.handlers.AccountHandler.WaitForAccountValidation)
+			protected.POST("/accounts/:id/reconnect", s.handlers.AccountHandler.ReconnectAccount)
+			protected.GET("/accounts/:id/qrcode", s.handlers.AccountHandler.GetQRCode)
+			protected.POST("/accounts/:id/disconnect", s.handlers.AccountHandler.DisconnectAccount)
 			protected.DELETE("/accounts/linkedin", s.handlers.AccountHandler.DisconnectLinkedIn)
+			// Account routes
+			protected.GET("/inbox", s.handlers.MessagingHandler.Inbox)
+			protected.GET("/messages/search", s.handlers.MessagingHandler.SearchMessages)
+			protected.GET("/accounts/:id/chats", s.handlers.MessagingHandler.ListChats)
+			protected.GET("/accounts/:id/chats/:chatId/attendees", s.handlers.MessagingHandler.ListChatAttendees)
+			protected.GET("/accounts/:id/chats/:chatId/messages", s.handlers.MessagingHandler.ListMessages)
+			protected.POST("/accounts/:id/chats", s.handlers.MessagingHandler.StartChat)
+			protected.POST("/accounts/:id/chats/:chatId/messages", s.handlers.MessagingHandler.SendMessage)
+			protected.GET("/accounts/:id/profiles/:identifier", s.handlers.ProfileHandler.GetProfile)
+			protected.POST("/accounts/:id/invitations", s.handlers.InvitationHandler.SendInvitation)
+			protected.GET("/accounts/:id/invitations/sent", s.handlers.InvitationHandler.ListSentInvitations)
+			protected.GET("/accounts/:id/invitations/received", s.handlers.InvitationHandler.ListReceivedInvitations)
+			protected.DELETE("/accounts/:id/invitations/sent/:invitationId", s.handlers.InvitationHandler.WithdrawInvitation)
 		}
 	}
 }
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Worker runs a background task until it is stopped
+type Worker interface {
+	// Start starts the worker goroutine
+	Start(ctx context.Context)
+	// Stop cancels the running task and waits for the worker goroutine to exit
+	Stop()
+}
+
+// PeriodicWorker runs a task on a fixed interval
+type PeriodicWorker struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+	logger   *logrus.Logger
+
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPeriodicWorker creates a worker that runs task every interval
+func NewPeriodicWorker(name string, interval time.Duration, task func(ctx context.Context) error, logger *logrus.Logger) Worker {
+	return &PeriodicWorker{
+		name:     name,
+		interval: interval,
+		task:     task,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts the worker goroutine, the first run happens after one interval
+func (w *PeriodicWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.run(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the running task and waits for the worker goroutine to exit
+func (w *PeriodicWorker) Stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel == nil {
+			close(w.done)
+			return
+		}
+		w.cancel()
+		<-w.done
+	})
+}
+
+func (w *PeriodicWorker) run(ctx context.Context) {
+	start := time.Now()
+	logFields := logrus.Fields{"worker": w.name}
+
+	if err := w.task(ctx); err != nil {
+		if ctx.Err() != nil {
+			w.logger.WithFields(logFields).Info("Worker run cancelled")
+			return
+		}
+		w.logger.WithError(err).WithFields(logFields).Error("Worker run failed")
+		return
+	}
+	w.logger.WithFields(logFields).WithField("duration", time.Since(start)).Debug("Worker run completed")
+}
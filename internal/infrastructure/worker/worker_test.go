@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestPeriodicWorker_RunsTask(t *testing.T) {
+	var runs atomic.Int32
+	w := NewPeriodicWorker("test", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("keeps running after errors")
+	}, newTestLogger())
+
+	w.Start(context.Background())
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	w.Stop()
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, stopped, runs.Load())
+}
+
+func TestPeriodicWorker_StopCancelsRunningTask(t *testing.T) {
+	started := make(chan struct{})
+	w := NewPeriodicWorker("test", time.Millisecond, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, newTestLogger())
+
+	w.Start(context.Background())
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Stop to cancel the running task")
+	}
+
+	// Stop is idempotent
+	w.Stop()
+}
+
+func TestPeriodicWorker_StopWithoutStart(t *testing.T) {
+	w := NewPeriodicWorker("test", time.Second, func(ctx context.Context) error { return nil }, newTestLogger())
+	w.Stop()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -12,35 +13,99 @@ import (
 	"unipile-connector/internal/domain/errs"
 	"unipile-connector/internal/domain/repository"
 	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
 )
 
 // Usecase handles account business logic
 type Usecase interface {
 	ListUserAccounts(ctx context.Context, userID uint) ([]*entity.Account, error)
-	DisconnectLinkedIn(ctx context.Context, userID uint, accountID string) error
-	ConnectLinkedInAccount(ctx context.Context, userID uint, req *ConnectLinkedInRequest) (*entity.Account, error)
+	DisconnectAccount(ctx context.Context, userID uint, provider, accountID string) error
+	ConnectAccount(ctx context.Context, userID uint, req *ConnectAccountRequest) (*entity.Account, error)
 	SolveCheckpoint(ctx context.Context, userID uint, req *SolveCheckpointRequest) (*entity.Account, error)
-	WaitForAccountValidation(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error)
+	WaitForAccountValidation(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error)
+	ReconcileAccounts(ctx context.Context) error
+	HandleAccountStatusEvent(ctx context.Context, event *AccountStatusEvent) (*entity.Account, error)
+	ExpireCheckpoints(ctx context.Context) error
+	ReconnectAccount(ctx context.Context, userID uint, accountID string, req *ConnectAccountRequest) (*entity.Account, error)
+	ResendCheckpoint(ctx context.Context, userID uint, provider, accountID string) (*entity.Account, error)
+	GetQRCode(ctx context.Context, userID uint, provider, accountID string) (*QRCode, error)
+	CreateHostedAuthLink(ctx context.Context, userID uint, providers []string) (*HostedAuthLink, error)
+	HandleHostedAuthNotification(ctx context.Context, state string, notification *HostedAuthNotification) (*entity.Account, error)
+	GetProvider(name string) (*Provider, error)
+	ListProviders() []Provider
 }
 
 // UsecaseImpl handles account business logic
 type UsecaseImpl struct {
-	txRepo        repository.TxRepository
-	accountRepo   repository.AccountRepository
-	unipileClient service.UnipileClient
-	logger        *logrus.Logger
+	txRepo           repository.TxRepository
+	accountRepo      repository.AccountRepository
+	unipileClient    service.UnipileClient
+	eventHub         service.AccountEventHub
+	providers        ProviderRegistry
+	checkpointConfig CheckpointConfig
+	hostedAuthState  service.HostedAuthStateService
+	hostedAuthConfig HostedAuthConfig
+	logger           *logrus.Logger
+}
+
+// defaultCheckpointTTL is used when no checkpoint lifetime is configured
+const defaultCheckpointTTL = 270 * time.Second // 4.5 minutes
+
+// defaultCheckpointMaxAttempts is used when no attempt limit is configured
+const defaultCheckpointMaxAttempts = 5
+
+// CheckpointConfig controls the lifetime of authentication checkpoints
+type CheckpointConfig struct {
+	TTL                   time.Duration                           // Default checkpoint lifetime
+	TTLs                  map[entity.CheckpointType]time.Duration // Lifetime per checkpoint type, overrides TTL
+	DeleteExpiredAccounts bool                                    // Delete the Unipile account when its checkpoint expires
+	MaxAttempts           int                                     // Failed code submissions before a checkpoint is locked
+}
+
+// checkpointTTL returns the lifetime of a checkpoint of the given type
+func (c CheckpointConfig) checkpointTTL(checkpointType entity.CheckpointType) time.Duration {
+	if ttl := c.TTLs[checkpointType]; ttl > 0 {
+		return ttl
+	}
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return defaultCheckpointTTL
+}
+
+// maxAttempts returns the number of failed code submissions before a checkpoint is locked
+func (c CheckpointConfig) maxAttempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return defaultCheckpointMaxAttempts
 }
 
 // NewAccountUsecase creates a new account usecase
-func NewAccountUsecase(txRepo repository.TxRepository, accountRepo repository.AccountRepository, unipileClient service.UnipileClient, logger *logrus.Logger) Usecase {
+func NewAccountUsecase(txRepo repository.TxRepository, accountRepo repository.AccountRepository, unipileClient service.UnipileClient, eventHub service.AccountEventHub, checkpointConfig CheckpointConfig, hostedAuthState service.HostedAuthStateService, hostedAuthConfig HostedAuthConfig, logger *logrus.Logger) Usecase {
 	return &UsecaseImpl{
-		txRepo:        txRepo,
-		accountRepo:   accountRepo,
-		unipileClient: unipileClient,
-		logger:        logger,
+		txRepo:           txRepo,
+		accountRepo:      accountRepo,
+		unipileClient:    unipileClient,
+		eventHub:         eventHub,
+		providers:        NewProviderRegistry(DefaultProviders()...),
+		checkpointConfig: checkpointConfig,
+		hostedAuthState:  hostedAuthState,
+		hostedAuthConfig: hostedAuthConfig,
+		logger:           logger,
 	}
 }
 
+// GetProvider returns the provider with the given name, or ErrUnsupportedProvider
+func (a *UsecaseImpl) GetProvider(name string) (*Provider, error) {
+	return a.providers.Get(name)
+}
+
+// ListProviders returns the providers accounts can be connected with
+func (a *UsecaseImpl) ListProviders() []Provider {
+	return a.providers.List()
+}
+
 // ListUserAccounts retrieves all accounts for a user
 func (a *UsecaseImpl) ListUserAccounts(ctx context.Context, userID uint) ([]*entity.Account, error) {
 	accounts, err := a.accountRepo.GetByUserID(ctx, userID)
@@ -50,134 +115,246 @@ func (a *UsecaseImpl) ListUserAccounts(ctx context.Context, userID uint) ([]*ent
 	return accounts, nil
 }
 
-// DisconnectLinkedIn disconnects LinkedIn account for a user
-func (a *UsecaseImpl) DisconnectLinkedIn(ctx context.Context, userID uint, accountID string) error {
-	return a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+// DisconnectAccount disconnects an account of the provider for a user, deleting it locally and on Unipile
+func (a *UsecaseImpl) DisconnectAccount(ctx context.Context, userID uint, provider, accountID string) error {
+	var account *entity.Account
+
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		// Only accounts owned by the user are deleted, locally and on Unipile
+		locked, err := lockProviderAccount(ctx, repos, userID, provider, accountID)
+		if err != nil {
+			return err
+		}
+		// Record the transition before the account row is soft deleted, terminal accounts have nothing to record
+		if !locked.CurrentStatus.IsTerminal() {
+			if err := setAccountStatus(ctx, repos, locked, entity.AccountStatusDeleted, entity.StatusCauseUserAction, nil); err != nil {
+				return err
+			}
+		}
+
 		if err := repos.Account.DeleteByUserIDAndAccountID(ctx, userID, accountID); err != nil {
 			return errs.WrapInternalError(err, "Failed to delete account")
 		}
-		if err := a.unipileClient.DeleteAccount(accountID); err != nil && err != service.ErrUnipileAccountNotFound {
-			return errs.WrapInternalError(err, "Failed to delete account on Unipile")
+		if err := a.unipileClient.DeleteAccount(ctx, accountID); err != nil && !errors.Is(err, service.ErrUnipileAccountNotFound) {
+			return a.unipileError(err, "Failed to delete account on Unipile")
 		}
+		account = locked
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	account.CurrentStatus = entity.AccountStatusDeleted
+	a.publishAccountStatus(account)
+	return nil
 }
 
-// ConnectLinkedInRequest represents request to connect LinkedIn account via Unipile
-type ConnectLinkedInRequest struct {
+// ConnectAccountRequest represents request to connect an account via Unipile
+type ConnectAccountRequest struct {
+	Provider    string
+	Type        string // Auth method type, see Provider.AuthMethods
 	Username    string
 	Password    string
 	AccessToken string
 	UserAgent   string
 }
 
-// ConnectLinkedInAccount connects a LinkedIn account for a user
-func (a *UsecaseImpl) ConnectLinkedInAccount(ctx context.Context, userID uint, req *ConnectLinkedInRequest) (*entity.Account, error) {
+// authField returns the value of an auth field
+func (r *ConnectAccountRequest) authField(field string) string {
+	switch field {
+	case AuthFieldUsername:
+		return r.Username
+	case AuthFieldPassword:
+		return r.Password
+	case AuthFieldAccessToken:
+		return r.AccessToken
+	default:
+		return ""
+	}
+}
 
-	resp, err := a.unipileClient.ConnectLinkedIn(&service.ConnectLinkedInRequest{
-		Provider:    "LINKEDIN",
-		Username:    req.Username,
-		Password:    req.Password,
-		AccessToken: req.AccessToken,
-		UserAgent:   req.UserAgent,
-	})
+// unipileRequest builds the Unipile connect request for the provider
+func (r *ConnectAccountRequest) unipileRequest(provider string) *service.ConnectAccountRequest {
+	return &service.ConnectAccountRequest{
+		Provider:    provider,
+		Username:    r.Username,
+		Password:    r.Password,
+		AccessToken: r.AccessToken,
+		UserAgent:   r.UserAgent,
+	}
+}
+
+// ConnectAccount connects an account of the request provider for a user
+func (a *UsecaseImpl) ConnectAccount(ctx context.Context, userID uint, req *ConnectAccountRequest) (*entity.Account, error) {
+	provider, err := a.providers.Get(req.Provider)
 	if err != nil {
 		return nil, err
 	}
 
-	account := &entity.Account{
-		UserID:        userID,
-		Provider:      "LINKEDIN",
-		AccountID:     resp.AccountID,
-		CurrentStatus: "PENDING",
+	var resp *service.ConnectAccountResponse
+	if provider.Name == ProviderWhatsApp {
+		resp, err = a.unipileClient.ConnectWhatsApp(ctx)
+	} else {
+		resp, err = a.unipileClient.ConnectAccount(ctx, req.unipileRequest(provider.Name))
+	}
+	if err != nil {
+		return nil, a.unipileError(err, "Failed to connect account on Unipile")
 	}
 
-	if resp.Checkpoint == nil {
-		account.CurrentStatus = "OK"
-		if err := a.accountRepo.Create(ctx, account); err != nil {
-			return nil, errs.WrapInternalError(err, "Failed to create account")
-		}
-		return account, nil
+	account := &entity.Account{
+		UserID:    userID,
+		Provider:  provider.Name,
+		AccountID: resp.AccountID,
 	}
 
-	checkpointBody, err := json.Marshal(resp.Checkpoint)
+	history, err := a.checkpointStatusHistory(provider.Name, resp.Checkpoint, entity.StatusCauseUserAction, resp.RowBody)
 	if err != nil {
-		return nil, errs.WrapInternalError(err, "Failed to marshal checkpoint")
+		return nil, err
 	}
-
-	account.AccountStatusHistories = append(account.AccountStatusHistories, entity.AccountStatusHistory{
-		Checkpoint:          resp.Checkpoint.Type,
-		CheckpointMetadata:  checkpointBody,
-		CheckpointExpiresAt: time.Now().Add(270 * time.Second), // 4.5 minutes
-		Status:              "PENDING",
-	})
+	history.PreviousStatus = account.CurrentStatus
+	if err := account.TransitionTo(history.Status); err != nil {
+		return nil, errs.WrapBusinessError(err, "Invalid account status transition")
+	}
+	account.AccountStatusHistories = append(account.AccountStatusHistories, *history)
 
 	if err := a.accountRepo.Create(ctx, account); err != nil {
 		return nil, errs.WrapInternalError(err, "Failed to create account")
 	}
+	a.publishAccountStatus(account)
 
 	return account, nil
 }
 
+// checkpointStatusHistory builds the status history row for a Unipile answer that may carry a checkpoint.
+// Accounts without a checkpoint are OK, otherwise they stay PENDING until the checkpoint is solved or expires.
+func (a *UsecaseImpl) checkpointStatusHistory(providerName string, checkpoint *service.Checkpoint, cause string, rowBody string) (*entity.AccountStatusHistory, error) {
+	var payload json.RawMessage
+	if rowBody != "" {
+		payload = json.RawMessage(rowBody)
+	}
+
+	if checkpoint == nil {
+		history, err := newStatusHistory(entity.AccountStatusOK, cause, payload)
+		if err != nil {
+			return nil, errs.WrapInternalError(err, "Failed to marshal status history")
+		}
+		return history, nil
+	}
+
+	checkpointBody, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to marshal checkpoint")
+	}
+
+	checkpointType := entity.CheckpointType(checkpoint.Type)
+	if provider, err := a.providers.Get(providerName); err == nil && !provider.SupportsCheckpoint(checkpointType) {
+		a.logger.WithFields(logrus.Fields{
+			"provider":   providerName,
+			"checkpoint": checkpointType,
+		}).Warn("Unipile returned a checkpoint the provider does not declare")
+	}
+	return &entity.AccountStatusHistory{
+		Checkpoint:          checkpointType,
+		CheckpointMetadata:  checkpointBody,
+		CheckpointExpiresAt: time.Now().Add(a.checkpointConfig.checkpointTTL(checkpointType)),
+		Status:              entity.AccountStatusPending,
+		Cause:               cause,
+		Payload:             payload,
+	}, nil
+}
+
 // SolveCheckpointRequest represents request to solve a checkpoint
 type SolveCheckpointRequest struct {
+	Provider  string // Provider the account must belong to, empty for any
 	AccountID string
 	Code      string
 }
 
-// SolveCheckpoint solves a LinkedIn authentication checkpoint
+// SolveCheckpoint solves an authentication checkpoint.
+// The account only becomes OK once Unipile confirms it without another checkpoint,
+// a follow-up checkpoint is recorded as a new PENDING history row and returned with the account.
 func (a *UsecaseImpl) SolveCheckpoint(ctx context.Context, userID uint, req *SolveCheckpointRequest) (*entity.Account, error) {
 
 	var account *entity.Account
+	// Failed attempts are committed with the transaction, the rejection is returned after it
+	var rejected error
 
 	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
 		var err error
 
-		account, err = repos.Account.GetByUserIDAndAccountIDForUpdate(ctx, userID, req.AccountID)
+		account, err = lockProviderAccount(ctx, repos, userID, req.Provider, req.AccountID)
 		if err != nil {
-			if errors.Is(err, repository.ErrAccountNotFound) {
-				return errs.WrapValidationError(errors.New("account not found"), "Account not found")
-			}
-			return errs.WrapInternalError(err, "Failed to get account")
+			return err
 		}
-		if account.CurrentStatus == "OK" {
+		if account.CurrentStatus == entity.AccountStatusOK {
 			return nil
 		}
+		if err := account.CurrentStatus.ValidateTransition(entity.AccountStatusOK); err != nil {
+			return errs.WrapBusinessError(err, "Invalid account status transition")
+		}
 
-		account.CurrentStatus = "OK"
-		if err := repos.Account.Update(ctx, account); err != nil {
-			return errs.WrapInternalError(err, "Failed to update account")
+		attempt, err := checkpointAttempt(ctx, repos, account)
+		if err != nil {
+			return err
+		}
+		if attempt != nil && attempt.IsLocked() {
+			return errs.ErrCheckpointLocked
 		}
 
-		if _, err := a.unipileClient.SolveCheckpoint(&service.SolveCheckpointRequest{
+		resp, err := a.unipileClient.SolveCheckpoint(ctx, &service.SolveCheckpointRequest{
 			Provider:  account.Provider,
 			AccountID: req.AccountID,
 			Code:      req.Code,
-		}); err != nil {
+		})
+		if err != nil {
 			if errors.Is(err, service.ErrUnipileInvalidCodeOrExpiredCheckpoint) {
-				return errs.ErrInvalidCodeOrExpiredCheckpoint
+				locked, err := a.recordFailedAttempt(ctx, repos, account, attempt)
+				if err != nil {
+					return err
+				}
+				rejected = errs.ErrInvalidCodeOrExpiredCheckpoint
+				if locked {
+					rejected = errs.ErrCheckpointLocked
+				}
+				return nil
 			}
-			return errs.WrapInternalError(err, "Failed to solve checkpoint")
+			return a.unipileError(err, "Failed to solve checkpoint")
 		}
 
+		// Unipile answers with a follow-up checkpoint when more verification is needed, e.g. 2FA then IN_APP_VALIDATION
+		history, err := a.checkpointStatusHistory(account.Provider, resp.Checkpoint, entity.StatusCauseUserAction, resp.RowBody)
+		if err != nil {
+			return err
+		}
+		if err := applyStatusHistory(ctx, repos, account, history); err != nil {
+			return err
+		}
+		account.AccountStatusHistories = append(account.AccountStatusHistories, *history)
 		return nil
 	}); err != nil {
 		return nil, err
 	}
+	if rejected != nil {
+		return nil, rejected
+	}
+	a.publishAccountStatus(account)
 
 	return account, nil
 }
 
-// WaitForAccountValidation waits for IN_APP_VALIDATION checkpoint to be resolved using long polling
-func (a *UsecaseImpl) WaitForAccountValidation(ctx context.Context, userID uint, accountID string, timeout time.Duration) (*entity.Account, error) {
-	accountWithStatus, err := a.accountRepo.GetWithStatus(ctx, userID, accountID, "IN_APP_VALIDATION")
+// WaitForAccountValidation waits for an IN_APP_VALIDATION or QRCODE checkpoint to be resolved using long polling
+func (a *UsecaseImpl) WaitForAccountValidation(ctx context.Context, userID uint, provider, accountID string, timeout time.Duration) (*entity.Account, error) {
+	accountWithStatus, err := a.accountRepo.GetWithStatus(ctx, userID, accountID, entity.CheckpointTypeInAppValidation, entity.CheckpointTypeQRCode)
+	if err == nil && !matchesProvider(&accountWithStatus.Account, provider) {
+		err = repository.ErrAccountNotFound
+	}
 	if err != nil {
 		if errors.Is(err, repository.ErrAccountNotFound) {
-			return nil, errs.WrapValidationError(errors.New("account not found, expired, or not in IN_APP_VALIDATION state"), "Account not found, expired, or not in IN_APP_VALIDATION state")
+			return nil, errs.WrapValidationError(errors.New("account not found, expired, or not in IN_APP_VALIDATION or QRCODE state"), "Account not found, expired, or not in IN_APP_VALIDATION or QRCODE state")
 		}
 		return nil, errs.WrapInternalError(err, "Failed to get account")
 	}
-	if accountWithStatus.CurrentStatus == "OK" {
+	if accountWithStatus.CurrentStatus == entity.AccountStatusOK {
 		return &accountWithStatus.Account, nil
 	}
 
@@ -186,28 +363,36 @@ func (a *UsecaseImpl) WaitForAccountValidation(ctx context.Context, userID uint,
 	if pollTimeout <= 0 {
 		pollTimeout = 5 * time.Minute // Default fallback
 	}
+	// Stop polling when the checkpoint expires, so the expiry sweep cannot expire the account mid-poll
+	if expiresAt := accountWithStatus.CheckpointExpiresAt; !expiresAt.IsZero() {
+		remaining := time.Until(expiresAt)
+		if remaining <= 0 {
+			return nil, errs.ErrInvalidCodeOrExpiredCheckpoint
+		}
+		pollTimeout = min(pollTimeout, remaining)
+	}
 
 	logFields := logrus.Fields{
 		"userID":    userID,
 		"accountID": accountID,
 		"timeout":   pollTimeout,
 	}
-	a.logger.WithFields(logFields).Info("Starting long polling for IN_APP_VALIDATION")
+	a.logger.WithFields(logFields).Infof("Starting long polling for %s", accountWithStatus.Checkpoint)
 
 	// Use long polling to wait for account status change
-	unipileAccount, err := a.unipileClient.GetAccountWithLongPolling(accountID, pollTimeout)
+	unipileAccount, err := a.unipileClient.GetAccountWithLongPolling(ctx, accountID, pollTimeout)
 	if err != nil {
 		a.logger.WithError(err).WithFields(logFields).Error("Long polling failed")
 		if errors.Is(err, service.ErrUnipileAccountNotFound) {
 			return nil, errs.WrapValidationError(errors.New("account not found"), "Account not found")
 		}
-		return nil, errs.WrapInternalError(err, "Failed to check account status")
+		return nil, a.unipileError(err, "Failed to check account status")
 	}
 
 	// Check if any source has status "OK"
 	accountStatusOK := false
 	for _, source := range unipileAccount.Sources {
-		if source.Status == "OK" {
+		if entity.AccountStatus(source.Status) == entity.AccountStatusOK {
 			accountStatusOK = true
 			break
 		}
@@ -216,11 +401,66 @@ func (a *UsecaseImpl) WaitForAccountValidation(ctx context.Context, userID uint,
 		return nil, errs.WrapValidationError(errors.New("account validation failed"), "Account validation failed")
 	}
 
-	account := accountWithStatus.Account
-	account.CurrentStatus = "OK"
-	if err := a.accountRepo.Update(ctx, &account); err != nil {
-		return nil, errs.WrapInternalError(err, "Failed to update account status")
+	var account *entity.Account
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		var err error
+
+		account, err = repos.Account.GetByUserIDAndAccountIDForUpdate(ctx, userID, accountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return errs.WrapValidationError(errors.New("account not found"), "Account not found")
+			}
+			return errs.WrapInternalError(err, "Failed to get account")
+		}
+		// A webhook may have confirmed the account while polling
+		if account.CurrentStatus == entity.AccountStatusOK {
+			return nil
+		}
+
+		return setAccountStatus(ctx, repos, account, entity.AccountStatusOK, entity.StatusCauseUserAction, unipileAccount)
+	}); err != nil {
+		return nil, err
+	}
+	a.publishAccountStatus(account)
+
+	return account, nil
+}
+
+// lockProviderAccount locks the account of the user for update, accounts of another provider are reported as not found
+func lockProviderAccount(ctx context.Context, repos *repository.Repositories, userID uint, provider, accountID string) (*entity.Account, error) {
+	account, err := repos.Account.GetByUserIDAndAccountIDForUpdate(ctx, userID, accountID)
+	if err == nil && !matchesProvider(account, provider) {
+		err = repository.ErrAccountNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, errs.WrapValidationError(errors.New("account not found"), "Account not found")
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get account")
 	}
+	return account, nil
+}
 
-	return &account, nil
+// matchesProvider reports whether the account belongs to the provider, an empty provider matches any account
+func matchesProvider(account *entity.Account, provider string) bool {
+	return provider == "" || strings.EqualFold(account.Provider, provider)
+}
+
+// publishAccountStatus pushes the account status to every open session of the account owner
+func (a *UsecaseImpl) publishAccountStatus(account *entity.Account) {
+	a.eventHub.Publish(account.UserID, service.AccountEvent{
+		Type:      service.AccountEventTypeStatus,
+		AccountID: account.AccountID,
+		Provider:  account.Provider,
+		Status:    string(account.CurrentStatus),
+	})
+}
+
+// unipileError maps a Unipile client error, invalid credentials being the ones the user submitted
+func (a *UsecaseImpl) unipileError(err error, msg string) error {
+	mapped := unipileerr.Map(a.logger, err, msg)
+	if errors.Is(err, service.ErrUnipileInvalidCredentials) {
+		return errs.ErrInvalidProviderCredentials
+	}
+	return mapped
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,10 +21,21 @@ import (
 type mockAccountRepo struct {
 	createFunc                       func(ctx context.Context, account *entity.Account) error
 	getByUserIDFunc                  func(ctx context.Context, userID uint) ([]*entity.Account, error)
+	listFunc                         func(ctx context.Context) ([]*entity.Account, error)
+	getByUserIDAndAccountIDFunc      func(ctx context.Context, userID uint, accountID string) (*entity.Account, error)
 	getByUserIDAndAccountIDForUpdate func(ctx context.Context, userID uint, accountID string) (*entity.Account, error)
-	getWithStatusFunc                func(ctx context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error)
+	getByAccountIDForUpdateFunc      func(ctx context.Context, accountID string) (*entity.Account, error)
+	listPendingWithExpiredCheckpoint func(ctx context.Context, now time.Time) ([]*entity.Account, error)
+	getWithStatusFunc                func(ctx context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error)
 	updateFunc                       func(ctx context.Context, account *entity.Account) error
 	deleteByUserIDAndAccountIDFunc   func(ctx context.Context, userID uint, accountID string) error
+	createStatusHistoryFunc          func(ctx context.Context, history *entity.AccountStatusHistory) error
+	updateCheckpointMetadataFunc     func(ctx context.Context, history *entity.AccountStatusHistory) error
+	getCheckpointAttemptFunc         func(ctx context.Context, statusHistoryID uint) (*entity.CheckpointAttempt, error)
+	saveCheckpointAttemptFunc        func(ctx context.Context, attempt *entity.CheckpointAttempt) error
+	createHostedAuthStateFunc        func(ctx context.Context, state *entity.HostedAuthState) error
+	getHostedAuthStateForUpdateFunc  func(ctx context.Context, nonce string) (*entity.HostedAuthState, error)
+	saveHostedAuthStateFunc          func(ctx context.Context, state *entity.HostedAuthState) error
 }
 
 func (m *mockAccountRepo) Create(ctx context.Context, account *entity.Account) error {
@@ -38,6 +52,20 @@ func (m *mockAccountRepo) GetByUserID(ctx context.Context, userID uint) ([]*enti
 	return nil, nil
 }
 
+func (m *mockAccountRepo) List(ctx context.Context) ([]*entity.Account, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) GetByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	if m.getByUserIDAndAccountIDFunc != nil {
+		return m.getByUserIDAndAccountIDFunc(ctx, userID, accountID)
+	}
+	return nil, nil
+}
+
 func (m *mockAccountRepo) GetByUserIDAndAccountIDForUpdate(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
 	if m.getByUserIDAndAccountIDForUpdate != nil {
 		return m.getByUserIDAndAccountIDForUpdate(ctx, userID, accountID)
@@ -45,9 +73,23 @@ func (m *mockAccountRepo) GetByUserIDAndAccountIDForUpdate(ctx context.Context,
 	return nil, nil
 }
 
-func (m *mockAccountRepo) GetWithStatus(ctx context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
+func (m *mockAccountRepo) GetByAccountIDForUpdate(ctx context.Context, accountID string) (*entity.Account, error) {
+	if m.getByAccountIDForUpdateFunc != nil {
+		return m.getByAccountIDForUpdateFunc(ctx, accountID)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) ListPendingWithExpiredCheckpoint(ctx context.Context, now time.Time) ([]*entity.Account, error) {
+	if m.listPendingWithExpiredCheckpoint != nil {
+		return m.listPendingWithExpiredCheckpoint(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) GetWithStatus(ctx context.Context, userID uint, accountID string, checkpoints ...entity.CheckpointType) (*entity.AccountWithStatus, error) {
 	if m.getWithStatusFunc != nil {
-		return m.getWithStatusFunc(ctx, userID, accountID, checkpoint)
+		return m.getWithStatusFunc(ctx, userID, accountID, checkpoints)
 	}
 	return nil, nil
 }
@@ -66,6 +108,55 @@ func (m *mockAccountRepo) DeleteByUserIDAndAccountID(ctx context.Context, userID
 	return nil
 }
 
+func (m *mockAccountRepo) CreateStatusHistory(ctx context.Context, history *entity.AccountStatusHistory) error {
+	if m.createStatusHistoryFunc != nil {
+		return m.createStatusHistoryFunc(ctx, history)
+	}
+	return nil
+}
+
+func (m *mockAccountRepo) UpdateCheckpointMetadata(ctx context.Context, history *entity.AccountStatusHistory) error {
+	if m.updateCheckpointMetadataFunc != nil {
+		return m.updateCheckpointMetadataFunc(ctx, history)
+	}
+	return nil
+}
+
+func (m *mockAccountRepo) GetCheckpointAttempt(ctx context.Context, statusHistoryID uint) (*entity.CheckpointAttempt, error) {
+	if m.getCheckpointAttemptFunc != nil {
+		return m.getCheckpointAttemptFunc(ctx, statusHistoryID)
+	}
+	return nil, repository.ErrCheckpointAttemptNotFound
+}
+
+func (m *mockAccountRepo) SaveCheckpointAttempt(ctx context.Context, attempt *entity.CheckpointAttempt) error {
+	if m.saveCheckpointAttemptFunc != nil {
+		return m.saveCheckpointAttemptFunc(ctx, attempt)
+	}
+	return nil
+}
+
+func (m *mockAccountRepo) CreateHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error {
+	if m.createHostedAuthStateFunc != nil {
+		return m.createHostedAuthStateFunc(ctx, state)
+	}
+	return nil
+}
+
+func (m *mockAccountRepo) GetHostedAuthStateForUpdate(ctx context.Context, nonce string) (*entity.HostedAuthState, error) {
+	if m.getHostedAuthStateForUpdateFunc != nil {
+		return m.getHostedAuthStateForUpdateFunc(ctx, nonce)
+	}
+	return nil, repository.ErrHostedAuthStateNotFound
+}
+
+func (m *mockAccountRepo) SaveHostedAuthState(ctx context.Context, state *entity.HostedAuthState) error {
+	if m.saveHostedAuthStateFunc != nil {
+		return m.saveHostedAuthStateFunc(ctx, state)
+	}
+	return nil
+}
+
 type mockTxRepo struct {
 	doFunc func(ctx context.Context, fn func(*repository.Repositories) error) error
 }
@@ -77,66 +168,161 @@ func (m *mockTxRepo) Do(ctx context.Context, fn func(*repository.Repositories) e
 	return fn(&repository.Repositories{})
 }
 
+type mockEventHub struct {
+	published []service.AccountEvent
+	userIDs   []uint
+}
+
+func (m *mockEventHub) Subscribe(userID uint) (<-chan service.AccountEvent, func()) {
+	ch := make(chan service.AccountEvent)
+	return ch, func() {}
+}
+
+func (m *mockEventHub) Publish(userID uint, event service.AccountEvent) {
+	m.userIDs = append(m.userIDs, userID)
+	m.published = append(m.published, event)
+}
+
+func (m *mockEventHub) Close() {}
+
 type mockUnipileClient struct {
-	listAccountsFunc              func() (*service.AccountListResponse, error)
-	testConnectionFunc            func() error
-	getAccountFunc                func(accountID string) (*service.Account, error)
-	getAccountWithLongPollingFunc func(accountID string, timeout time.Duration) (*service.Account, error)
-	deleteAccountFunc             func(accountID string) error
-	connectLinkedInFunc           func(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error)
-	solveCheckpointFunc           func(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error)
+	listAccountsFunc              func(ctx context.Context, req *service.ListAccountsRequest) (*service.AccountListResponse, error)
+	testConnectionFunc            func(ctx context.Context) error
+	getAccountFunc                func(ctx context.Context, accountID string) (*service.Account, error)
+	getAccountWithLongPollingFunc func(ctx context.Context, accountID string, timeout time.Duration) (*service.Account, error)
+	deleteAccountFunc             func(ctx context.Context, accountID string) error
+	connectAccountFunc            func(ctx context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error)
+	connectWhatsAppFunc           func(ctx context.Context) (*service.ConnectAccountResponse, error)
+	solveCheckpointFunc           func(ctx context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error)
+	reconnectAccountFunc          func(ctx context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error)
+	resendCheckpointFunc          func(ctx context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error)
+	createHostedAuthLinkFunc      func(ctx context.Context, req *service.HostedAuthLinkRequest) (*service.HostedAuthLinkResponse, error)
 }
 
-func (m *mockUnipileClient) ListAccounts() (*service.AccountListResponse, error) {
+func (m *mockUnipileClient) ListAccounts(ctx context.Context, req *service.ListAccountsRequest) (*service.AccountListResponse, error) {
 	if m.listAccountsFunc != nil {
-		return m.listAccountsFunc()
+		return m.listAccountsFunc(ctx, req)
 	}
 	return nil, nil
 }
 
-func (m *mockUnipileClient) TestConnection() error {
+func (m *mockUnipileClient) TestConnection(ctx context.Context) error {
 	if m.testConnectionFunc != nil {
-		return m.testConnectionFunc()
+		return m.testConnectionFunc(ctx)
 	}
 	return nil
 }
 
-func (m *mockUnipileClient) GetAccount(accountID string) (*service.Account, error) {
+func (m *mockUnipileClient) GetAccount(ctx context.Context, accountID string) (*service.Account, error) {
 	if m.getAccountFunc != nil {
-		return m.getAccountFunc(accountID)
+		return m.getAccountFunc(ctx, accountID)
 	}
 	return nil, nil
 }
 
-func (m *mockUnipileClient) GetAccountWithLongPolling(accountID string, timeout time.Duration) (*service.Account, error) {
+func (m *mockUnipileClient) GetAccountWithLongPolling(ctx context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
 	if m.getAccountWithLongPollingFunc != nil {
-		return m.getAccountWithLongPollingFunc(accountID, timeout)
+		return m.getAccountWithLongPollingFunc(ctx, accountID, timeout)
 	}
 	return nil, nil
 }
 
-func (m *mockUnipileClient) DeleteAccount(accountID string) error {
+func (m *mockUnipileClient) DeleteAccount(ctx context.Context, accountID string) error {
 	if m.deleteAccountFunc != nil {
-		return m.deleteAccountFunc(accountID)
+		return m.deleteAccountFunc(ctx, accountID)
 	}
 	return nil
 }
 
-func (m *mockUnipileClient) ConnectLinkedIn(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error) {
-	if m.connectLinkedInFunc != nil {
-		return m.connectLinkedInFunc(req)
+func (m *mockUnipileClient) ConnectAccount(ctx context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+	if m.connectAccountFunc != nil {
+		return m.connectAccountFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ConnectWhatsApp(ctx context.Context) (*service.ConnectAccountResponse, error) {
+	if m.connectWhatsAppFunc != nil {
+		return m.connectWhatsAppFunc(ctx)
 	}
 	return nil, nil
 }
 
-func (m *mockUnipileClient) SolveCheckpoint(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+func (m *mockUnipileClient) SolveCheckpoint(ctx context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
 	if m.solveCheckpointFunc != nil {
-		return m.solveCheckpointFunc(req)
+		return m.solveCheckpointFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ReconnectAccount(ctx context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+	if m.reconnectAccountFunc != nil {
+		return m.reconnectAccountFunc(ctx, accountID, req)
+	}
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ResendCheckpoint(ctx context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+	if m.resendCheckpointFunc != nil {
+		return m.resendCheckpointFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *mockUnipileClient) CreateHostedAuthLink(ctx context.Context, req *service.HostedAuthLinkRequest) (*service.HostedAuthLinkResponse, error) {
+	if m.createHostedAuthLinkFunc != nil {
+		return m.createHostedAuthLinkFunc(ctx, req)
 	}
 	return nil, nil
 }
 
-func TestConnectLinkedInAccount_SuccessWithoutCheckpoint(t *testing.T) {
+// Messaging calls are not used by the account usecase
+
+func (m *mockUnipileClient) ListChats(ctx context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) GetChat(ctx context.Context, chatID string) (*service.Chat, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ListChatAttendees(ctx context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ListMessages(ctx context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) SendMessage(ctx context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) StartChat(ctx context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) GetUserProfile(ctx context.Context, accountID, identifier string) (*service.UserProfile, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) SendInvitation(ctx context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ListSentInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) ListReceivedInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.ReceivedInvitationListResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUnipileClient) WithdrawInvitation(ctx context.Context, accountID, invitationID string) error {
+	return nil
+}
+
+func TestConnectAccount_SuccessWithoutCheckpoint(t *testing.T) {
 	ctx := context.Background()
 	var createdAccount *entity.Account
 
@@ -148,19 +334,19 @@ func TestConnectLinkedInAccount_SuccessWithoutCheckpoint(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		connectLinkedInFunc: func(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error) {
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
 			if req.Provider != "LINKEDIN" {
 				t.Fatalf("expected provider LINKEDIN, got %s", req.Provider)
 			}
-			return &service.ConnectLinkedInResponse{AccountID: "acc-123"}, nil
+			return &service.ConnectAccountResponse{AccountID: "acc-123"}, nil
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	account, err := uc.ConnectLinkedInAccount(ctx, 42, &ConnectLinkedInRequest{Username: "user", Password: "pass"})
+	account, err := uc.ConnectAccount(ctx, 42, &ConnectAccountRequest{Provider: "LINKEDIN", Username: "user", Password: "pass"})
 	if err != nil {
-		t.Fatalf("ConnectLinkedInAccount returned error: %v", err)
+		t.Fatalf("ConnectAccount returned error: %v", err)
 	}
 
 	if createdAccount == nil {
@@ -179,12 +365,16 @@ func TestConnectLinkedInAccount_SuccessWithoutCheckpoint(t *testing.T) {
 		t.Fatalf("expected user ID 42, got %d", account.UserID)
 	}
 
-	if len(account.AccountStatusHistories) != 0 {
-		t.Fatalf("expected no status histories, got %d", len(account.AccountStatusHistories))
+	if len(account.AccountStatusHistories) != 1 {
+		t.Fatalf("expected one status history entry, got %d", len(account.AccountStatusHistories))
+	}
+	history := account.AccountStatusHistories[0]
+	if history.PreviousStatus != "" || history.Status != "OK" || history.Cause != entity.StatusCauseUserAction {
+		t.Fatalf("unexpected status history: %+v", history)
 	}
 }
 
-func TestConnectLinkedInAccount_SuccessWithCheckpoint(t *testing.T) {
+func TestConnectAccount_SuccessWithCheckpoint(t *testing.T) {
 	ctx := context.Background()
 	var createdAccount *entity.Account
 	start := time.Now()
@@ -197,17 +387,17 @@ func TestConnectLinkedInAccount_SuccessWithCheckpoint(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		connectLinkedInFunc: func(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error) {
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
 			checkpoint := &service.Checkpoint{Type: "OTP", Source: "APP"}
-			return &service.ConnectLinkedInResponse{AccountID: "acc-otp", Checkpoint: checkpoint}, nil
+			return &service.ConnectAccountResponse{AccountID: "acc-otp", Checkpoint: checkpoint}, nil
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	account, err := uc.ConnectLinkedInAccount(ctx, 7, &ConnectLinkedInRequest{AccessToken: "token", UserAgent: "agent"})
+	account, err := uc.ConnectAccount(ctx, 7, &ConnectAccountRequest{Provider: "LINKEDIN", AccessToken: "token", UserAgent: "agent"})
 	if err != nil {
-		t.Fatalf("ConnectLinkedInAccount returned error: %v", err)
+		t.Fatalf("ConnectAccount returned error: %v", err)
 	}
 
 	if account.CurrentStatus != "PENDING" {
@@ -234,6 +424,9 @@ func TestConnectLinkedInAccount_SuccessWithCheckpoint(t *testing.T) {
 	if history.Status != "PENDING" {
 		t.Fatalf("expected history status PENDING, got %s", history.Status)
 	}
+	if history.Cause != entity.StatusCauseUserAction {
+		t.Fatalf("expected history cause %s, got %s", entity.StatusCauseUserAction, history.Cause)
+	}
 
 	lowerBound := start.Add(260 * time.Second)
 	upperBound := start.Add(280 * time.Second)
@@ -246,28 +439,65 @@ func TestConnectLinkedInAccount_SuccessWithCheckpoint(t *testing.T) {
 	}
 }
 
-func TestConnectLinkedInAccount_ClientError(t *testing.T) {
+func TestConnectAccount_ClientError(t *testing.T) {
 	ctx := context.Background()
 
 	wantErr := errors.New("connect failed")
 	unipileClient := &mockUnipileClient{
-		connectLinkedInFunc: func(req *service.ConnectLinkedInRequest) (*service.ConnectLinkedInResponse, error) {
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
 			return nil, wantErr
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	_, err := uc.ConnectLinkedInAccount(ctx, 1, &ConnectLinkedInRequest{})
-	if err != wantErr {
+	_, err := uc.ConnectAccount(ctx, 1, &ConnectAccountRequest{Provider: "LINKEDIN"})
+	if !errors.Is(err, wantErr) {
 		t.Fatalf("expected error %v, got %v", wantErr, err)
 	}
+
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.SystemErrorKind {
+		t.Fatalf("expected system coded error, got %v", err)
+	}
+}
+
+func TestConnectAccount_UnipileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiErr  *service.UnipileError
+		wantErr error
+	}{
+		{"invalid credentials", &service.UnipileError{Status: 401, Type: service.UnipileErrorTypeInvalidCredentials}, errs.ErrInvalidProviderCredentials},
+		{"disconnected", &service.UnipileError{Status: 401, Type: service.UnipileErrorTypeDisconnectedAccount}, errs.ErrProviderAccountDisconnected},
+		{"insufficient permissions", &service.UnipileError{Status: 403, Type: service.UnipileErrorTypeInsufficientPrivileges}, errs.ErrProviderPermissionDenied},
+		{"rate limited", &service.UnipileError{Status: 429}, errs.ErrProviderRateLimited},
+		{"unavailable", &service.UnipileError{Status: 503}, errs.ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unipileClient := &mockUnipileClient{
+				connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+					return nil, tt.apiErr
+				},
+			}
+
+			uc := NewAccountUsecase(&mockTxRepo{}, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+			_, err := uc.ConnectAccount(context.Background(), 1, &ConnectAccountRequest{Provider: "LINKEDIN"})
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
 }
 
 func TestSolveCheckpoint_Success(t *testing.T) {
 	ctx := context.Background()
-	accountForUpdate := &entity.Account{UserID: 4, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: "PENDING"}
+	accountForUpdate := &entity.Account{ID: 11, UserID: 4, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: "PENDING"}
 	var updatedAccount *entity.Account
+	var history *entity.AccountStatusHistory
 	var solveCalled bool
 
 	accountRepo := &mockAccountRepo{
@@ -281,6 +511,10 @@ func TestSolveCheckpoint_Success(t *testing.T) {
 			updatedAccount = account
 			return nil
 		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
 	}
 
 	txRepo := &mockTxRepo{
@@ -290,16 +524,16 @@ func TestSolveCheckpoint_Success(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		solveCheckpointFunc: func(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
 			solveCalled = true
 			if req.AccountID != "acc-1" || req.Code != "123456" {
 				t.Fatalf("unexpected solve request: %+v", req)
 			}
-			return &service.SolveCheckpointResponse{}, nil
+			return &service.SolveCheckpointResponse{Object: "AccountCreated", AccountID: "acc-1", RowBody: `{"object":"AccountCreated"}`}, nil
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
 	account, err := uc.SolveCheckpoint(ctx, 4, &SolveCheckpointRequest{AccountID: "acc-1", Code: "123456"})
 	if err != nil {
@@ -317,6 +551,79 @@ func TestSolveCheckpoint_Success(t *testing.T) {
 	if account.CurrentStatus != "OK" {
 		t.Fatalf("expected account status OK, got %s", account.CurrentStatus)
 	}
+
+	if history == nil {
+		t.Fatalf("expected status history to be recorded")
+	}
+	if history.AccountID != 11 || history.PreviousStatus != "PENDING" || history.Status != "OK" || history.Cause != entity.StatusCauseUserAction {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+	if len(history.Payload) == 0 {
+		t.Fatalf("expected Unipile payload to be recorded")
+	}
+}
+
+func TestSolveCheckpoint_FollowUpCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	var history *entity.AccountStatusHistory
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{
+				ID: 11, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending,
+				AccountStatusHistories: []entity.AccountStatusHistory{{ID: 1, Checkpoint: entity.CheckpointType2FA, Status: entity.AccountStatusPending}},
+			}, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			return &service.SolveCheckpointResponse{
+				Object:     "Checkpoint",
+				AccountID:  req.AccountID,
+				Checkpoint: &service.Checkpoint{Type: "IN_APP_VALIDATION"},
+				RowBody:    `{"object":"Checkpoint"}`,
+			}, nil
+		},
+	}
+	eventHub := &mockEventHub{}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, eventHub, CheckpointConfig{TTL: time.Minute}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.SolveCheckpoint(ctx, 4, &SolveCheckpointRequest{AccountID: "acc-1", Code: "123456"})
+	if err != nil {
+		t.Fatalf("SolveCheckpoint returned error: %v", err)
+	}
+
+	if account.CurrentStatus != entity.AccountStatusPending {
+		t.Fatalf("expected account to stay PENDING, got %s", account.CurrentStatus)
+	}
+	if history == nil || history.PreviousStatus != entity.AccountStatusPending || history.Status != entity.AccountStatusPending {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+	if history.Checkpoint != entity.CheckpointTypeInAppValidation || len(history.CheckpointMetadata) == 0 {
+		t.Fatalf("expected follow-up checkpoint to be recorded, got %+v", history)
+	}
+	if expiresIn := history.CheckpointExpiresAt.Sub(start); expiresIn < time.Minute || expiresIn > time.Minute+5*time.Second {
+		t.Fatalf("expected follow-up checkpoint to expire in 1m, got %v", expiresIn)
+	}
+	latest := account.AccountStatusHistories[len(account.AccountStatusHistories)-1]
+	if latest.Checkpoint != entity.CheckpointTypeInAppValidation {
+		t.Fatalf("expected follow-up checkpoint to be returned with the account, got %s", latest.Checkpoint)
+	}
+	if len(eventHub.published) != 1 || eventHub.published[0].Status != string(entity.AccountStatusPending) {
+		t.Fatalf("expected PENDING event to be published, got %+v", eventHub.published)
+	}
 }
 
 func TestSolveCheckpoint_AlreadyOK(t *testing.T) {
@@ -342,13 +649,13 @@ func TestSolveCheckpoint_AlreadyOK(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		solveCheckpointFunc: func(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
 			solveCalled = true
 			return &service.SolveCheckpointResponse{}, nil
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
 	account, err := uc.SolveCheckpoint(ctx, 4, &SolveCheckpointRequest{AccountID: "acc-2", Code: "000000"})
 	if err != nil {
@@ -385,12 +692,12 @@ func TestSolveCheckpoint_InvalidCode(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		solveCheckpointFunc: func(req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
 			return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
 	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{AccountID: "acc-invalid", Code: "bad"})
 	if err == nil {
@@ -402,6 +709,47 @@ func TestSolveCheckpoint_InvalidCode(t *testing.T) {
 	}
 }
 
+func TestSolveCheckpoint_UnipileFailureKeepsStatus(t *testing.T) {
+	ctx := context.Background()
+	accountForUpdate := &entity.Account{ID: 11, UserID: 4, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending}
+
+	// The mock transaction does not roll back, so any write before the Unipile answer would show
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return accountForUpdate, nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			t.Fatalf("expected the account not to be updated, got status %s", account.CurrentStatus)
+			return nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			t.Fatalf("expected no status history, got %+v", h)
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			return nil, &service.UnipileError{Status: 500}
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if _, err := uc.SolveCheckpoint(ctx, 4, &SolveCheckpointRequest{AccountID: "acc-1", Code: "123456"}); err == nil {
+		t.Fatalf("expected error but got nil")
+	}
+	if accountForUpdate.CurrentStatus != entity.AccountStatusPending {
+		t.Fatalf("expected account to stay PENDING, got %s", accountForUpdate.CurrentStatus)
+	}
+}
+
 func TestSolveCheckpoint_AccountNotFound(t *testing.T) {
 	ctx := context.Background()
 
@@ -417,7 +765,7 @@ func TestSolveCheckpoint_AccountNotFound(t *testing.T) {
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, &mockUnipileClient{}, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
 	_, err := uc.SolveCheckpoint(ctx, 10, &SolveCheckpointRequest{AccountID: "missing", Code: "000"})
 	if err == nil {
@@ -434,12 +782,23 @@ func TestSolveCheckpoint_AccountNotFound(t *testing.T) {
 	}
 }
 
-func TestDisconnectLinkedIn_Success(t *testing.T) {
+func TestDisconnectAccount_Success(t *testing.T) {
 	ctx := context.Background()
 	var deleteCalled bool
 	var deleteAccountCalled bool
+	var history *entity.AccountStatusHistory
 
 	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 3, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: "OK"}, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			if deleteCalled {
+				t.Fatalf("expected status history before the account is deleted")
+			}
+			history = h
+			return nil
+		},
 		deleteByUserIDAndAccountIDFunc: func(_ context.Context, userID uint, accountID string) error {
 			deleteCalled = true
 			if userID != 9 || accountID != "acc-9" {
@@ -450,7 +809,7 @@ func TestDisconnectLinkedIn_Success(t *testing.T) {
 	}
 
 	unipileClient := &mockUnipileClient{
-		deleteAccountFunc: func(accountID string) error {
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
 			deleteAccountCalled = true
 			if accountID != "acc-9" {
 				t.Fatalf("unexpected account ID %s", accountID)
@@ -465,10 +824,10 @@ func TestDisconnectLinkedIn_Success(t *testing.T) {
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	if err := uc.DisconnectLinkedIn(ctx, 9, "acc-9"); err != nil {
-		t.Fatalf("DisconnectLinkedIn returned error: %v", err)
+	if err := uc.DisconnectAccount(ctx, 9, "LINKEDIN", "acc-9"); err != nil {
+		t.Fatalf("DisconnectAccount returned error: %v", err)
 	}
 
 	if !deleteCalled {
@@ -478,17 +837,24 @@ func TestDisconnectLinkedIn_Success(t *testing.T) {
 	if !deleteAccountCalled {
 		t.Fatalf("expected unipile delete account to be called")
 	}
+
+	if history == nil || history.AccountID != 3 || history.PreviousStatus != "OK" || history.Status != "DELETED" || history.Cause != entity.StatusCauseUserAction {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
 }
 
-func TestDisconnectLinkedIn_UnipileAccountNotFound(t *testing.T) {
+func TestDisconnectAccount_UnipileAccountNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 4, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: "OK"}, nil
+		},
 		deleteByUserIDAndAccountIDFunc: func(_ context.Context, userID uint, accountID string) error { return nil },
 	}
 
 	unipileClient := &mockUnipileClient{
-		deleteAccountFunc: func(accountID string) error {
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
 			return service.ErrUnipileAccountNotFound
 		},
 	}
@@ -499,13 +865,91 @@ func TestDisconnectLinkedIn_UnipileAccountNotFound(t *testing.T) {
 		},
 	}
 
-	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	if err := uc.DisconnectLinkedIn(ctx, 1, "unknown"); err != nil {
+	if err := uc.DisconnectAccount(ctx, 1, "LINKEDIN", "unknown"); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
 }
 
+func TestDisconnectAccount_OtherUsersAccount(t *testing.T) {
+	ctx := context.Background()
+
+	// acc-a belongs to user 1, user 2 tries to disconnect it
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			if userID != 1 {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &entity.Account{ID: 1, UserID: 1, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: "OK"}, nil
+		},
+		deleteByUserIDAndAccountIDFunc: func(_ context.Context, userID uint, accountID string) error {
+			t.Fatalf("expected no local delete of another user's account")
+			return nil
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
+			t.Fatalf("expected no Unipile delete of another user's account")
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	eventHub := &mockEventHub{}
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, eventHub, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	err := uc.DisconnectAccount(ctx, 2, "LINKEDIN", "acc-a")
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(eventHub.published) != 0 {
+		t.Fatalf("expected no account event, got %+v", eventHub.published)
+	}
+}
+
+func TestDisconnectAccount_OtherProvider(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 1, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: "OK"}, nil
+		},
+		deleteByUserIDAndAccountIDFunc: func(_ context.Context, userID uint, accountID string) error {
+			t.Fatalf("expected no local delete of an account of another provider")
+			return nil
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
+			t.Fatalf("expected no Unipile delete of an account of another provider")
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	err := uc.DisconnectAccount(ctx, 1, ProviderWhatsApp, "acc-1")
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
 func TestListUserAccounts(t *testing.T) {
 	ctx := context.Background()
 	expected := []*entity.Account{{AccountID: "a"}, {AccountID: "b"}}
@@ -521,7 +965,7 @@ func TestListUserAccounts(t *testing.T) {
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
 	accounts, err := uc.ListUserAccounts(ctx, 77)
 	if err != nil {
@@ -548,26 +992,41 @@ func TestWaitForAccountValidation_Success(t *testing.T) {
 		},
 		CurrentStatus:       "PENDING",
 		Checkpoint:          "IN_APP_VALIDATION",
-		CheckpointExpiresAt: time.Now().Add(5 * time.Minute),
+		CheckpointExpiresAt: time.Now().Add(10 * time.Minute),
 	}
 	var updatedAccount *entity.Account
+	var history *entity.AccountStatusHistory
 
 	accountRepo := &mockAccountRepo{
-		getWithStatusFunc: func(_ context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
-			if userID != 1 || accountID != "acc-123" || checkpoint != "IN_APP_VALIDATION" {
-				t.Fatalf("unexpected lookup params userID=%d accountID=%s checkpoint=%s", userID, accountID, checkpoint)
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
+			if userID != 1 || accountID != "acc-123" || len(checkpoints) != 2 || checkpoints[0] != "IN_APP_VALIDATION" || checkpoints[1] != "QRCODE" {
+				t.Fatalf("unexpected lookup params userID=%d accountID=%s checkpoints=%v", userID, accountID, checkpoints)
 			}
 			return accountWithStatus, nil
 		},
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			account := accountWithStatus.Account
+			return &account, nil
+		},
 		updateFunc: func(_ context.Context, account *entity.Account) error {
 			updatedAccount = account
 			return nil
 		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
 	}
 
-	unipileClient := &mockUnipileClient{
-		getAccountWithLongPollingFunc: func(accountID string, timeout time.Duration) (*service.Account, error) {
-			if accountID != "acc-123" {
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		getAccountWithLongPollingFunc: func(_ context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
+			if accountID != "acc-123" {
 				t.Fatalf("unexpected account ID %s", accountID)
 			}
 			if timeout != 300*time.Second {
@@ -581,9 +1040,9 @@ func TestWaitForAccountValidation_Success(t *testing.T) {
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, logrus.New())
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	account, err := uc.WaitForAccountValidation(ctx, 1, "acc-123", 300*time.Second)
+	account, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 300*time.Second)
 	if err != nil {
 		t.Fatalf("WaitForAccountValidation returned error: %v", err)
 	}
@@ -595,20 +1054,66 @@ func TestWaitForAccountValidation_Success(t *testing.T) {
 	if account.CurrentStatus != "OK" {
 		t.Fatalf("expected account status OK, got %s", account.CurrentStatus)
 	}
+
+	if history == nil || history.PreviousStatus != "PENDING" || history.Status != "OK" || history.Cause != entity.StatusCauseUserAction {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+}
+
+func TestWaitForAccountValidation_CapsTimeoutAtCheckpointExpiry(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+	accountRepo := &mockAccountRepo{
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
+			return &entity.AccountWithStatus{
+				Account:             entity.Account{UserID: 1, AccountID: "acc-123", Provider: "LINKEDIN", CurrentStatus: "PENDING"},
+				CurrentStatus:       "PENDING",
+				Checkpoint:          "IN_APP_VALIDATION",
+				CheckpointExpiresAt: expiresAt,
+			}, nil
+		},
+	}
+
+	var polledFor time.Duration
+	unipileClient := &mockUnipileClient{
+		getAccountWithLongPollingFunc: func(_ context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
+			polledFor = timeout
+			return &service.Account{Sources: []service.AccountSource{{Status: "CONNECTING"}}}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if _, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 5*time.Minute); err == nil {
+		t.Fatalf("expected validation to fail")
+	}
+	if polledFor <= 0 || polledFor > time.Minute {
+		t.Fatalf("expected polling to stop at the checkpoint expiry, polled for %v", polledFor)
+	}
+
+	// An expired checkpoint is not polled
+	expiresAt = time.Now().Add(-time.Second)
+	polledFor = 0
+	if _, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 5*time.Minute); !errors.Is(err, errs.ErrInvalidCodeOrExpiredCheckpoint) {
+		t.Fatalf("expected ErrInvalidCodeOrExpiredCheckpoint, got %v", err)
+	}
+	if polledFor != 0 {
+		t.Fatalf("expected no polling, polled for %v", polledFor)
+	}
 }
 
 func TestWaitForAccountValidation_AccountNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	accountRepo := &mockAccountRepo{
-		getWithStatusFunc: func(_ context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
 			return nil, repository.ErrAccountNotFound
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	_, err := uc.WaitForAccountValidation(ctx, 1, "missing", 300*time.Second)
+	_, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "missing", 300*time.Second)
 	if err == nil {
 		t.Fatalf("expected error but got nil")
 	}
@@ -636,14 +1141,14 @@ func TestWaitForAccountValidation_AlreadyOK(t *testing.T) {
 	}
 
 	accountRepo := &mockAccountRepo{
-		getWithStatusFunc: func(_ context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
 			return accountWithStatus, nil
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	account, err := uc.WaitForAccountValidation(ctx, 1, "acc-123", 300*time.Second)
+	account, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 300*time.Second)
 	if err != nil {
 		t.Fatalf("WaitForAccountValidation returned error: %v", err)
 	}
@@ -668,20 +1173,20 @@ func TestWaitForAccountValidation_UnipileAccountNotFound(t *testing.T) {
 	}
 
 	accountRepo := &mockAccountRepo{
-		getWithStatusFunc: func(_ context.Context, userID uint, accountID, checkpoint string) (*entity.AccountWithStatus, error) {
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
 			return accountWithStatus, nil
 		},
 	}
 
 	unipileClient := &mockUnipileClient{
-		getAccountWithLongPollingFunc: func(accountID string, timeout time.Duration) (*service.Account, error) {
+		getAccountWithLongPollingFunc: func(_ context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
 			return nil, service.ErrUnipileAccountNotFound
 		},
 	}
 
-	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, logrus.New())
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
 
-	_, err := uc.WaitForAccountValidation(ctx, 1, "acc-123", 300*time.Second)
+	_, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 300*time.Second)
 	if err == nil {
 		t.Fatalf("expected error but got nil")
 	}
@@ -695,3 +1200,1511 @@ func TestWaitForAccountValidation_UnipileAccountNotFound(t *testing.T) {
 		t.Fatalf("expected validation error kind, got %s", codedErr.Kind)
 	}
 }
+
+func TestWaitForAccountValidation_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	accountWithStatus := &entity.AccountWithStatus{
+		Account: entity.Account{
+			UserID:        1,
+			AccountID:     "acc-123",
+			Provider:      "LINKEDIN",
+			CurrentStatus: "PENDING",
+		},
+		CurrentStatus:       "PENDING",
+		Checkpoint:          "IN_APP_VALIDATION",
+		CheckpointExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+
+	accountRepo := &mockAccountRepo{
+		getWithStatusFunc: func(_ context.Context, userID uint, accountID string, checkpoints []entity.CheckpointType) (*entity.AccountWithStatus, error) {
+			return accountWithStatus, nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			t.Fatalf("expected update not to be called when long polling is cancelled")
+			return nil
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		getAccountWithLongPollingFunc: func(ctx context.Context, accountID string, timeout time.Duration) (*service.Account, error) {
+			cancel()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.WaitForAccountValidation(ctx, 1, "LINKEDIN", "acc-123", 300*time.Second)
+	if err == nil {
+		t.Fatalf("expected error but got nil")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+}
+
+func TestReconcileAccounts(t *testing.T) {
+	ctx := context.Background()
+	local := []*entity.Account{
+		{ID: 1, UserID: 1, AccountID: "acc-ok", CurrentStatus: "OK"},
+		{ID: 2, UserID: 1, AccountID: "acc-credentials", CurrentStatus: "OK"},
+		{ID: 3, UserID: 2, AccountID: "acc-missing", CurrentStatus: "OK"},
+		{ID: 4, UserID: 2, AccountID: "acc-pending", CurrentStatus: "PENDING"},
+		{ID: 5, UserID: 2, AccountID: "acc-no-sources", CurrentStatus: "OK"},
+		{ID: 6, UserID: 2, AccountID: "acc-permissions", CurrentStatus: "OK"},
+	}
+	byAccountID := make(map[string]*entity.Account)
+	for _, account := range local {
+		copied := *account
+		byAccountID[account.AccountID] = &copied
+	}
+
+	updated := make(map[string]entity.AccountStatus)
+	var histories []*entity.AccountStatusHistory
+	accountRepo := &mockAccountRepo{
+		listFunc: func(_ context.Context) ([]*entity.Account, error) {
+			return local, nil
+		},
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return byAccountID[accountID], nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			updated[account.AccountID] = account.CurrentStatus
+			return nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, history *entity.AccountStatusHistory) error {
+			histories = append(histories, history)
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	cursor := "next"
+	unipileClient := &mockUnipileClient{
+		listAccountsFunc: func(_ context.Context, req *service.ListAccountsRequest) (*service.AccountListResponse, error) {
+			if req.Cursor == "" {
+				return &service.AccountListResponse{
+					Items:  []service.Account{{ID: "acc-ok", Sources: []service.AccountSource{{Status: "OK"}}}},
+					Cursor: &cursor,
+				}, nil
+			}
+			return &service.AccountListResponse{
+				Items: []service.Account{
+					{ID: "acc-credentials", Sources: []service.AccountSource{{Status: "OK"}, {Status: "CREDENTIALS"}}},
+					{ID: "acc-pending", Sources: []service.AccountSource{{Status: "CONNECTING"}}},
+					{ID: "acc-no-sources"},
+					{ID: "acc-permissions", Sources: []service.AccountSource{{Status: "PERMISSIONS"}}},
+				},
+			}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if err := uc.ReconcileAccounts(ctx); err != nil {
+		t.Fatalf("ReconcileAccounts returned error: %v", err)
+	}
+
+	expected := map[string]entity.AccountStatus{
+		"acc-credentials": "CREDENTIALS",
+		"acc-missing":     "DELETED",
+		"acc-permissions": "ERROR",
+	}
+	if len(updated) != len(expected) {
+		t.Fatalf("expected %d updates, got %v", len(expected), updated)
+	}
+	for accountID, status := range expected {
+		if updated[accountID] != status {
+			t.Fatalf("expected %s to become %s, got %s", accountID, status, updated[accountID])
+		}
+	}
+
+	if len(histories) != 3 {
+		t.Fatalf("expected 3 status histories, got %d", len(histories))
+	}
+	if histories[0].AccountID != 2 || histories[0].Status != "CREDENTIALS" {
+		t.Fatalf("unexpected history: %+v", histories[0])
+	}
+	if histories[1].AccountID != 3 || histories[1].Status != "DELETED" {
+		t.Fatalf("unexpected history: %+v", histories[1])
+	}
+	if histories[2].AccountID != 6 || histories[2].Status != "ERROR" {
+		t.Fatalf("unexpected history: %+v", histories[2])
+	}
+	for _, history := range histories {
+		if history.Cause != entity.StatusCauseReconciliation {
+			t.Fatalf("expected cause %s, got %s", entity.StatusCauseReconciliation, history.Cause)
+		}
+	}
+	if len(histories[0].Payload) == 0 || histories[1].Payload != nil {
+		t.Fatalf("expected Unipile payload only for accounts found upstream")
+	}
+}
+
+func TestReconcileAccounts_UnipileError(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		listFunc: func(_ context.Context) ([]*entity.Account, error) {
+			return []*entity.Account{{AccountID: "acc-1", CurrentStatus: "OK"}}, nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			t.Fatalf("expected no updates when Unipile listing fails")
+			return nil
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		listAccountsFunc: func(_ context.Context, req *service.ListAccountsRequest) (*service.AccountListResponse, error) {
+			return nil, &service.UnipileError{Status: 503}
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if err := uc.ReconcileAccounts(ctx); err != errs.ErrProviderUnavailable {
+		t.Fatalf("expected provider unavailable error, got %v", err)
+	}
+}
+
+func TestUnipileAccountStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []service.AccountSource
+		want    entity.AccountStatus
+		wantOK  bool
+	}{
+		{name: "all sources OK", sources: []service.AccountSource{{Status: "OK"}, {Status: "OK"}}, want: entity.AccountStatusOK, wantOK: true},
+		{name: "first source not OK wins", sources: []service.AccountSource{{Status: "OK"}, {Status: "STOPPED"}, {Status: "CREDENTIALS"}}, want: entity.AccountStatusStopped, wantOK: true},
+		{name: "connecting", sources: []service.AccountSource{{Status: "CONNECTING"}}, want: entity.AccountStatusConnecting, wantOK: true},
+		{name: "permissions", sources: []service.AccountSource{{Status: "PERMISSIONS"}}, want: entity.AccountStatusError, wantOK: true},
+		{name: "no sources", sources: nil},
+		{name: "unknown source status", sources: []service.AccountSource{{Status: "CREDENTIALS"}, {Status: "SYNCING"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ok := unipileAccountStatus(&service.Account{Sources: tt.sources})
+			if ok != tt.wantOK || status != tt.want {
+				t.Fatalf("expected (%q, %v), got (%q, %v)", tt.want, tt.wantOK, status, ok)
+			}
+		})
+	}
+}
+
+func TestHandleAccountStatusEvent_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	stored := &entity.Account{ID: 5, UserID: 1, AccountID: "acc-5", Provider: "LINKEDIN", CurrentStatus: "PENDING"}
+	var histories []*entity.AccountStatusHistory
+
+	accountRepo := &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			if accountID != "acc-5" {
+				return nil, repository.ErrAccountNotFound
+			}
+			copied := *stored
+			return &copied, nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			stored.CurrentStatus = account.CurrentStatus
+			return nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, history *entity.AccountStatusHistory) error {
+			histories = append(histories, history)
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	eventHub := &mockEventHub{}
+	uc := NewAccountUsecase(txRepo, accountRepo, &mockUnipileClient{}, eventHub, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	event := &AccountStatusEvent{AccountID: "acc-5", AccountType: "LINKEDIN", Message: "CREATION_SUCCESS"}
+	account, err := uc.HandleAccountStatusEvent(ctx, event)
+	if err != nil {
+		t.Fatalf("HandleAccountStatusEvent returned error: %v", err)
+	}
+	if account == nil || account.CurrentStatus != "OK" {
+		t.Fatalf("expected account to become OK, got %+v", account)
+	}
+
+	// Duplicate delivery and an equivalent status do not add history rows
+	for _, message := range []string{"CREATION_SUCCESS", "SYNC_SUCCESS"} {
+		account, err = uc.HandleAccountStatusEvent(ctx, &AccountStatusEvent{AccountID: "acc-5", Message: message})
+		if err != nil {
+			t.Fatalf("HandleAccountStatusEvent returned error: %v", err)
+		}
+		if account != nil {
+			t.Fatalf("expected %s event to be ignored", message)
+		}
+	}
+
+	account, err = uc.HandleAccountStatusEvent(ctx, &AccountStatusEvent{AccountID: "acc-5", Message: "CREDENTIALS"})
+	if err != nil {
+		t.Fatalf("HandleAccountStatusEvent returned error: %v", err)
+	}
+	if account == nil || account.CurrentStatus != "CREDENTIALS" {
+		t.Fatalf("expected account to become CREDENTIALS, got %+v", account)
+	}
+
+	if len(histories) != 2 {
+		t.Fatalf("expected 2 status histories, got %d", len(histories))
+	}
+	// Only actual status changes are pushed to the dashboard
+	if len(eventHub.published) != 2 {
+		t.Fatalf("expected 2 published events, got %d", len(eventHub.published))
+	}
+	if eventHub.userIDs[1] != 1 || eventHub.published[1].Status != "CREDENTIALS" || eventHub.published[1].AccountID != "acc-5" {
+		t.Fatalf("unexpected published event: %+v", eventHub.published[1])
+	}
+	if histories[0].Status != "OK" || histories[1].Status != "CREDENTIALS" {
+		t.Fatalf("unexpected histories: %+v, %+v", histories[0], histories[1])
+	}
+	if histories[1].PreviousStatus != "OK" || histories[1].Cause != entity.StatusCauseWebhook {
+		t.Fatalf("unexpected transition: %+v", histories[1])
+	}
+	var payload AccountStatusEvent
+	if err := json.Unmarshal(histories[1].Payload, &payload); err != nil || payload.Message != "CREDENTIALS" {
+		t.Fatalf("unexpected webhook payload %s: %v", histories[1].Payload, err)
+	}
+}
+
+func TestHandleAccountStatusEvent_IgnoredEvents(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			return nil, repository.ErrAccountNotFound
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			t.Fatalf("expected no updates for ignored events")
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	for _, event := range []*AccountStatusEvent{
+		{AccountID: "unknown", Message: "OK"},
+		{AccountID: "acc-1", Message: "CONNECTING"},
+	} {
+		account, err := uc.HandleAccountStatusEvent(ctx, event)
+		if err != nil {
+			t.Fatalf("HandleAccountStatusEvent returned error: %v", err)
+		}
+		if account != nil {
+			t.Fatalf("expected event %+v to be ignored", event)
+		}
+	}
+}
+
+func TestHandleAccountStatusEvent_RejectedTransition(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 1, AccountID: accountID, CurrentStatus: entity.AccountStatusDeleted}, nil
+		},
+		updateFunc: func(_ context.Context, account *entity.Account) error {
+			t.Fatalf("expected no update for a rejected transition")
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	// DELETED is terminal, a late RECONNECTED event is acknowledged but not applied
+	account, err := uc.HandleAccountStatusEvent(ctx, &AccountStatusEvent{AccountID: "acc-1", Message: "RECONNECTED"})
+	if err != nil {
+		t.Fatalf("HandleAccountStatusEvent returned error: %v", err)
+	}
+	if account != nil {
+		t.Fatalf("expected event to be ignored, got %+v", account)
+	}
+}
+
+func TestSolveCheckpoint_RejectedTransition(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 1, UserID: userID, AccountID: accountID, CurrentStatus: entity.AccountStatusDeleted}, nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			t.Fatalf("expected Unipile not to be called for a rejected transition")
+			return nil, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{AccountID: "acc-1", Code: "123456"})
+	if !errors.Is(err, entity.ErrInvalidAccountStatusTransition) {
+		t.Fatalf("expected ErrInvalidAccountStatusTransition, got %v", err)
+	}
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.BusinessErrorKind {
+		t.Fatalf("expected business error, got %v", err)
+	}
+}
+
+func TestConnectAccount_CheckpointTTLByType(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+
+	unipileClient := &mockUnipileClient{
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+			return &service.ConnectAccountResponse{AccountID: "acc-1", Checkpoint: &service.Checkpoint{Type: "IN_APP_VALIDATION"}}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{
+		TTL:  time.Minute,
+		TTLs: map[entity.CheckpointType]time.Duration{entity.CheckpointTypeInAppValidation: 10 * time.Minute},
+	}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.ConnectAccount(ctx, 1, &ConnectAccountRequest{Provider: "LINKEDIN", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("ConnectAccount returned error: %v", err)
+	}
+
+	expiresIn := account.AccountStatusHistories[0].CheckpointExpiresAt.Sub(start)
+	if expiresIn < 10*time.Minute || expiresIn > 10*time.Minute+5*time.Second {
+		t.Fatalf("expected IN_APP_VALIDATION checkpoint to expire in 10m, got %v", expiresIn)
+	}
+}
+
+func TestExpireCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	expiredAt := time.Now().Add(-time.Minute)
+
+	stored := map[string]*entity.Account{
+		"acc-expired": {ID: 1, UserID: 1, AccountID: "acc-expired", CurrentStatus: entity.AccountStatusPending, AccountStatusHistories: []entity.AccountStatusHistory{
+			{ID: 1, Checkpoint: entity.CheckpointTypeOTP, CheckpointExpiresAt: expiredAt, Status: entity.AccountStatusPending},
+		}},
+		// Solved between listing and locking
+		"acc-solved": {ID: 2, UserID: 1, AccountID: "acc-solved", CurrentStatus: entity.AccountStatusOK},
+	}
+
+	var histories []*entity.AccountStatusHistory
+	accountRepo := &mockAccountRepo{
+		listPendingWithExpiredCheckpoint: func(_ context.Context, now time.Time) ([]*entity.Account, error) {
+			return []*entity.Account{
+				{ID: 1, UserID: 1, AccountID: "acc-expired", CurrentStatus: entity.AccountStatusPending},
+				{ID: 2, UserID: 1, AccountID: "acc-solved", CurrentStatus: entity.AccountStatusPending},
+			}, nil
+		},
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return stored[accountID], nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, history *entity.AccountStatusHistory) error {
+			histories = append(histories, history)
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	var deleted []string
+	unipileClient := &mockUnipileClient{
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
+			deleted = append(deleted, accountID)
+			return nil
+		},
+	}
+
+	eventHub := &mockEventHub{}
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, eventHub, CheckpointConfig{DeleteExpiredAccounts: true}, nil, HostedAuthConfig{}, logrus.New())
+
+	if err := uc.ExpireCheckpoints(ctx); err != nil {
+		t.Fatalf("ExpireCheckpoints returned error: %v", err)
+	}
+
+	if stored["acc-expired"].CurrentStatus != entity.AccountStatusExpired {
+		t.Fatalf("expected account to be EXPIRED, got %s", stored["acc-expired"].CurrentStatus)
+	}
+	if stored["acc-solved"].CurrentStatus != entity.AccountStatusOK {
+		t.Fatalf("expected solved account to stay OK, got %s", stored["acc-solved"].CurrentStatus)
+	}
+
+	if len(histories) != 1 {
+		t.Fatalf("expected 1 status history, got %d", len(histories))
+	}
+	history := histories[0]
+	if history.PreviousStatus != entity.AccountStatusPending || history.Status != entity.AccountStatusExpired ||
+		history.Cause != entity.StatusCauseExpiry || history.Checkpoint != entity.CheckpointTypeOTP {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+
+	if len(deleted) != 1 || deleted[0] != "acc-expired" {
+		t.Fatalf("expected expired Unipile account to be deleted, got %v", deleted)
+	}
+	if len(eventHub.published) != 1 || eventHub.published[0].Status != string(entity.AccountStatusExpired) {
+		t.Fatalf("unexpected published events: %+v", eventHub.published)
+	}
+}
+
+func TestExpireCheckpoints_KeepsUnipileAccount(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		listPendingWithExpiredCheckpoint: func(_ context.Context, now time.Time) ([]*entity.Account, error) {
+			return []*entity.Account{{ID: 1, UserID: 1, AccountID: "acc-1", CurrentStatus: entity.AccountStatusPending}}, nil
+		},
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 1, UserID: 1, AccountID: "acc-1", CurrentStatus: entity.AccountStatusPending}, nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
+			t.Fatalf("expected Unipile account to be kept")
+			return nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if err := uc.ExpireCheckpoints(ctx); err != nil {
+		t.Fatalf("ExpireCheckpoints returned error: %v", err)
+	}
+}
+
+func TestExpireCheckpoints_ReconnectCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+
+	stored := &entity.Account{ID: 1, UserID: 1, AccountID: "acc-1", CurrentStatus: entity.AccountStatusPending, AccountStatusHistories: []entity.AccountStatusHistory{
+		{ID: 1, Status: entity.AccountStatusOK, Cause: entity.StatusCauseUserAction, CreatedAt: start},
+		{ID: 2, PreviousStatus: entity.AccountStatusOK, Status: entity.AccountStatusCredentials, Cause: entity.StatusCauseWebhook, CreatedAt: start.Add(time.Minute)},
+		{ID: 3, PreviousStatus: entity.AccountStatusCredentials, Status: entity.AccountStatusPending, Cause: entity.StatusCauseReconnect,
+			Checkpoint: entity.CheckpointTypeOTP, CheckpointExpiresAt: start.Add(5 * time.Minute), CreatedAt: start.Add(2 * time.Minute)},
+		{ID: 4, PreviousStatus: entity.AccountStatusPending, Status: entity.AccountStatusPending, Cause: entity.StatusCauseResend,
+			Checkpoint: entity.CheckpointTypeOTP, CheckpointExpiresAt: start.Add(10 * time.Minute), CreatedAt: start.Add(3 * time.Minute)},
+	}}
+
+	var histories []*entity.AccountStatusHistory
+	accountRepo := &mockAccountRepo{
+		listPendingWithExpiredCheckpoint: func(_ context.Context, now time.Time) ([]*entity.Account, error) {
+			return []*entity.Account{{ID: 1, UserID: 1, AccountID: "acc-1", CurrentStatus: entity.AccountStatusPending}}, nil
+		},
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return stored, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, history *entity.AccountStatusHistory) error {
+			histories = append(histories, history)
+			return nil
+		},
+	}
+
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		deleteAccountFunc: func(_ context.Context, accountID string) error {
+			t.Fatalf("expected reconnected Unipile account to be kept")
+			return nil
+		},
+	}
+
+	eventHub := &mockEventHub{}
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, eventHub, CheckpointConfig{DeleteExpiredAccounts: true}, nil, HostedAuthConfig{}, logrus.New())
+
+	if err := uc.ExpireCheckpoints(ctx); err != nil {
+		t.Fatalf("ExpireCheckpoints returned error: %v", err)
+	}
+
+	if stored.CurrentStatus != entity.AccountStatusCredentials {
+		t.Fatalf("expected account to go back to CREDENTIALS, got %s", stored.CurrentStatus)
+	}
+	if len(histories) != 1 {
+		t.Fatalf("expected 1 status history, got %d", len(histories))
+	}
+	history := histories[0]
+	if history.PreviousStatus != entity.AccountStatusPending || history.Status != entity.AccountStatusCredentials ||
+		history.Cause != entity.StatusCauseExpiry || history.Checkpoint != entity.CheckpointTypeOTP {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+	if len(eventHub.published) != 1 || eventHub.published[0].Status != string(entity.AccountStatusCredentials) {
+		t.Fatalf("unexpected published events: %+v", eventHub.published)
+	}
+}
+
+func TestReconnectAccount_WithCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	accountForUpdate := &entity.Account{ID: 5, UserID: 2, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusCredentials}
+	var history *entity.AccountStatusHistory
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return accountForUpdate, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		reconnectAccountFunc: func(_ context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+			if accountID != "acc-1" || req.Provider != "LINKEDIN" || req.Username != "user" {
+				t.Fatalf("unexpected reconnect request: %s %+v", accountID, req)
+			}
+			return &service.ConnectAccountResponse{AccountID: "acc-1", Checkpoint: &service.Checkpoint{Type: "2FA"}, RowBody: `{"object":"Checkpoint"}`}, nil
+		},
+	}
+	eventHub := &mockEventHub{}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, eventHub, CheckpointConfig{TTL: time.Minute}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.ReconnectAccount(ctx, 2, "acc-1", &ConnectAccountRequest{Provider: "LINKEDIN", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("ReconnectAccount returned error: %v", err)
+	}
+
+	if account.ID != 5 || account.CurrentStatus != entity.AccountStatusPending {
+		t.Fatalf("expected existing account to be PENDING, got %+v", account)
+	}
+	if history == nil {
+		t.Fatalf("expected status history to be recorded")
+	}
+	if history.AccountID != 5 || history.PreviousStatus != entity.AccountStatusCredentials || history.Status != entity.AccountStatusPending || history.Cause != entity.StatusCauseReconnect {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+	if history.Checkpoint != entity.CheckpointType2FA || len(history.CheckpointMetadata) == 0 {
+		t.Fatalf("expected 2FA checkpoint to be recorded, got %+v", history)
+	}
+	if expiresIn := history.CheckpointExpiresAt.Sub(start); expiresIn < time.Minute || expiresIn > time.Minute+5*time.Second {
+		t.Fatalf("expected checkpoint to expire in 1m, got %v", expiresIn)
+	}
+	if len(account.AccountStatusHistories) != 1 || account.AccountStatusHistories[0].Checkpoint != entity.CheckpointType2FA {
+		t.Fatalf("expected checkpoint to be returned with the account, got %+v", account.AccountStatusHistories)
+	}
+	if len(eventHub.published) != 1 || eventHub.published[0].Status != string(entity.AccountStatusPending) {
+		t.Fatalf("expected PENDING event to be published, got %+v", eventHub.published)
+	}
+}
+
+func TestReconnectAccount_WithoutCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	var history *entity.AccountStatusHistory
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 5, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusError}, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		reconnectAccountFunc: func(_ context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+			return &service.ConnectAccountResponse{Object: "AccountReconnected", AccountID: accountID}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.ReconnectAccount(ctx, 2, "acc-1", &ConnectAccountRequest{Provider: "LINKEDIN", AccessToken: "token"})
+	if err != nil {
+		t.Fatalf("ReconnectAccount returned error: %v", err)
+	}
+
+	if account.CurrentStatus != entity.AccountStatusOK {
+		t.Fatalf("expected account status OK, got %s", account.CurrentStatus)
+	}
+	if history == nil || history.PreviousStatus != entity.AccountStatusError || history.Status != entity.AccountStatusOK || history.Cause != entity.StatusCauseReconnect {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+}
+
+func TestReconnectAccount_NotReconnectable(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []entity.AccountStatus{entity.AccountStatusOK, entity.AccountStatusPending, entity.AccountStatusDeleted} {
+		accountRepo := &mockAccountRepo{
+			getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+				return &entity.Account{ID: 1, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: status}, nil
+			},
+		}
+		txRepo := &mockTxRepo{
+			doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+				return fn(&repository.Repositories{Account: accountRepo})
+			},
+		}
+		unipileClient := &mockUnipileClient{
+			reconnectAccountFunc: func(_ context.Context, accountID string, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+				t.Fatalf("expected Unipile not to be called for a %s account", status)
+				return nil, nil
+			},
+		}
+
+		uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+		_, err := uc.ReconnectAccount(ctx, 1, "acc-1", &ConnectAccountRequest{Provider: "LINKEDIN", AccessToken: "token"})
+		if !errors.Is(err, errs.ErrAccountNotReconnectable) {
+			t.Fatalf("expected ErrAccountNotReconnectable for %s, got %v", status, err)
+		}
+	}
+}
+
+func TestResendCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	accountForUpdate := &entity.Account{
+		ID: 3, UserID: 1, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending,
+		AccountStatusHistories: []entity.AccountStatusHistory{
+			{ID: 1, Checkpoint: entity.CheckpointType2FA, CheckpointMetadata: json.RawMessage(`{"type":"2FA"}`), CheckpointExpiresAt: start.Add(-time.Minute), Status: entity.AccountStatusPending},
+		},
+	}
+	var history *entity.AccountStatusHistory
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return accountForUpdate, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			history = h
+			return nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	unipileClient := &mockUnipileClient{
+		resendCheckpointFunc: func(_ context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+			if req.AccountID != "acc-1" || req.Provider != "LINKEDIN" {
+				t.Fatalf("unexpected resend request: %+v", req)
+			}
+			return &service.ResendCheckpointResponse{Object: "CheckpointResent", AccountID: "acc-1"}, nil
+		},
+	}
+	eventHub := &mockEventHub{}
+
+	uc := NewAccountUsecase(txRepo, &mockAccountRepo{}, unipileClient, eventHub, CheckpointConfig{TTL: 2 * time.Minute}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.ResendCheckpoint(ctx, 1, "LINKEDIN", "acc-1")
+	if err != nil {
+		t.Fatalf("ResendCheckpoint returned error: %v", err)
+	}
+
+	if history == nil {
+		t.Fatalf("expected status history to be recorded")
+	}
+	if history.AccountID != 3 || history.PreviousStatus != entity.AccountStatusPending || history.Status != entity.AccountStatusPending || history.Cause != entity.StatusCauseResend {
+		t.Fatalf("unexpected status history: %+v", history)
+	}
+	if history.Checkpoint != entity.CheckpointType2FA || string(history.CheckpointMetadata) != `{"type":"2FA"}` {
+		t.Fatalf("expected pending checkpoint to be kept, got %+v", history)
+	}
+	if expiresIn := history.CheckpointExpiresAt.Sub(start); expiresIn < 2*time.Minute || expiresIn > 2*time.Minute+5*time.Second {
+		t.Fatalf("expected a fresh expiry in 2m, got %v", expiresIn)
+	}
+	if len(account.AccountStatusHistories) != 2 {
+		t.Fatalf("expected new checkpoint to be returned with the account, got %d histories", len(account.AccountStatusHistories))
+	}
+	if len(eventHub.published) != 1 {
+		t.Fatalf("expected one event to be published, got %d", len(eventHub.published))
+	}
+}
+
+func TestResendCheckpoint_NoPendingCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 3, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusOK}, nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		resendCheckpointFunc: func(_ context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+			t.Fatalf("expected Unipile not to be called without a pending checkpoint")
+			return nil, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.ResendCheckpoint(ctx, 1, "LINKEDIN", "acc-1")
+	if !errors.Is(err, errs.ErrNoPendingCheckpoint) {
+		t.Fatalf("expected ErrNoPendingCheckpoint, got %v", err)
+	}
+}
+
+func TestResendCheckpoint_ExpiredOnUnipile(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{
+				ID: 3, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending,
+				AccountStatusHistories: []entity.AccountStatusHistory{{Checkpoint: entity.CheckpointTypeOTP, Status: entity.AccountStatusPending}},
+			}, nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		resendCheckpointFunc: func(_ context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+			return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.ResendCheckpoint(ctx, 1, "LINKEDIN", "acc-1")
+	if !errors.Is(err, errs.ErrInvalidCodeOrExpiredCheckpoint) {
+		t.Fatalf("expected ErrInvalidCodeOrExpiredCheckpoint, got %v", err)
+	}
+}
+
+// newCheckpointAttemptRepo returns a repository holding a PENDING account with a 2FA checkpoint and the given attempts
+func newCheckpointAttemptRepo(attempt *entity.CheckpointAttempt) *mockAccountRepo {
+	return &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{
+				ID: 8, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending,
+				AccountStatusHistories: []entity.AccountStatusHistory{{ID: 21, Checkpoint: entity.CheckpointType2FA, Status: entity.AccountStatusPending}},
+			}, nil
+		},
+		getCheckpointAttemptFunc: func(_ context.Context, statusHistoryID uint) (*entity.CheckpointAttempt, error) {
+			if statusHistoryID != 21 || attempt == nil {
+				return nil, repository.ErrCheckpointAttemptNotFound
+			}
+			return attempt, nil
+		},
+	}
+}
+
+func TestSolveCheckpoint_CountsFailedAttempts(t *testing.T) {
+	ctx := context.Background()
+	var saved *entity.CheckpointAttempt
+
+	accountRepo := newCheckpointAttemptRepo(nil)
+	accountRepo.saveCheckpointAttemptFunc = func(_ context.Context, attempt *entity.CheckpointAttempt) error {
+		saved = attempt
+		return nil
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{MaxAttempts: 3}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{AccountID: "acc-1", Code: "000000"})
+	if !errors.Is(err, errs.ErrInvalidCodeOrExpiredCheckpoint) {
+		t.Fatalf("expected ErrInvalidCodeOrExpiredCheckpoint, got %v", err)
+	}
+	if saved == nil || saved.AccountID != 8 || saved.StatusHistoryID != 21 || saved.Checkpoint != entity.CheckpointType2FA {
+		t.Fatalf("unexpected checkpoint attempt: %+v", saved)
+	}
+	if saved.FailedAttempts != 1 || saved.IsLocked() {
+		t.Fatalf("expected one failed attempt without lock, got %+v", saved)
+	}
+}
+
+func TestSolveCheckpoint_LocksAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	var history *entity.AccountStatusHistory
+
+	attempt := &entity.CheckpointAttempt{ID: 2, AccountID: 8, StatusHistoryID: 21, Checkpoint: entity.CheckpointType2FA, FailedAttempts: 2}
+	accountRepo := newCheckpointAttemptRepo(attempt)
+	accountRepo.createStatusHistoryFunc = func(_ context.Context, h *entity.AccountStatusHistory) error {
+		history = h
+		return nil
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			return nil, service.ErrUnipileInvalidCodeOrExpiredCheckpoint
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{MaxAttempts: 3}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{AccountID: "acc-1", Code: "000000"})
+	if !errors.Is(err, errs.ErrCheckpointLocked) {
+		t.Fatalf("expected ErrCheckpointLocked, got %v", err)
+	}
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.BusinessErrorKind {
+		t.Fatalf("expected business error, got %v", err)
+	}
+	if attempt.FailedAttempts != 3 || !attempt.IsLocked() {
+		t.Fatalf("expected checkpoint to be locked after 3 attempts, got %+v", attempt)
+	}
+	if history == nil || history.Cause != entity.StatusCauseLocked || history.Status != entity.AccountStatusPending || history.Checkpoint != "" {
+		t.Fatalf("expected lock audit entry, got %+v", history)
+	}
+	if len(history.Payload) == 0 {
+		t.Fatalf("expected lock audit entry to record the attempts")
+	}
+}
+
+func TestSolveCheckpoint_LockedCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	lockedAt := time.Now()
+	accountRepo := newCheckpointAttemptRepo(&entity.CheckpointAttempt{ID: 2, StatusHistoryID: 21, FailedAttempts: 5, LockedAt: &lockedAt})
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			t.Fatalf("expected Unipile not to be called for a locked checkpoint")
+			return nil, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{AccountID: "acc-1", Code: "123456"})
+	if !errors.Is(err, errs.ErrCheckpointLocked) {
+		t.Fatalf("expected ErrCheckpointLocked, got %v", err)
+	}
+}
+
+func TestProviderRegistry_Get(t *testing.T) {
+	registry := NewProviderRegistry(DefaultProviders()...)
+
+	provider, err := registry.Get("linkedin")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if provider.Name != "LINKEDIN" {
+		t.Fatalf("expected LINKEDIN, got %s", provider.Name)
+	}
+	if !provider.SupportsCheckpoint(entity.CheckpointTypeInAppValidation) {
+		t.Fatal("expected LinkedIn to declare IN_APP_VALIDATION")
+	}
+
+	if _, err := registry.Get("myspace"); !errors.Is(err, errs.ErrUnsupportedProvider) {
+		t.Fatalf("expected ErrUnsupportedProvider, got %v", err)
+	}
+}
+
+func TestProvider_ValidateAuth(t *testing.T) {
+	registry := NewProviderRegistry(DefaultProviders()...)
+
+	tests := []struct {
+		name     string
+		provider string
+		req      ConnectAccountRequest
+		message  string
+	}{
+		{"credentials", "LINKEDIN", ConnectAccountRequest{Type: AuthTypeCredentials, Username: "user", Password: "pass"}, ""},
+		{"cookie", "LINKEDIN", ConnectAccountRequest{Type: AuthTypeCookie, AccessToken: "token"}, ""},
+		{"missing password", "LINKEDIN", ConnectAccountRequest{Type: AuthTypeCredentials, Username: "user"}, "Username and password required for credentials type"},
+		{"missing access token", "LINKEDIN", ConnectAccountRequest{Type: AuthTypeCookie}, "Access token required for cookie type"},
+		{"unknown type", "LINKEDIN", ConnectAccountRequest{Type: "oauth"}, "Type must be 'credentials' or 'cookie'"},
+		{"type required with several methods", "LINKEDIN", ConnectAccountRequest{Username: "user", Password: "pass"}, "Type must be 'credentials' or 'cookie'"},
+		{"single method without type", "WHATSAPP", ConnectAccountRequest{}, ""},
+		{"unsupported method", "WHATSAPP", ConnectAccountRequest{Type: AuthTypeCredentials, Username: "user", Password: "pass"}, "Type must be 'qrcode'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := registry.Get(tt.provider)
+			if err != nil {
+				t.Fatalf("Get returned error: %v", err)
+			}
+
+			err = provider.ValidateAuth(&tt.req)
+			if tt.message == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var codedErr *errs.CodedError
+			if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if codedErr.Message != tt.message {
+				t.Fatalf("expected message %q, got %q", tt.message, codedErr.Message)
+			}
+		})
+	}
+}
+
+func TestConnectAccount_UsesRequestProvider(t *testing.T) {
+	ctx := context.Background()
+
+	var created *entity.Account
+	accountRepo := &mockAccountRepo{
+		createFunc: func(_ context.Context, account *entity.Account) error {
+			created = account
+			return nil
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+			if req.Provider != "INSTAGRAM" {
+				t.Fatalf("expected INSTAGRAM provider, got %s", req.Provider)
+			}
+			return &service.ConnectAccountResponse{AccountID: "ig-1"}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	if _, err := uc.ConnectAccount(ctx, 1, &ConnectAccountRequest{Provider: "instagram", Username: "user", Password: "pass"}); err != nil {
+		t.Fatalf("ConnectAccount returned error: %v", err)
+	}
+	if created == nil || created.Provider != "INSTAGRAM" {
+		t.Fatalf("expected INSTAGRAM account to be created, got %+v", created)
+	}
+
+	_, err := uc.ConnectAccount(ctx, 1, &ConnectAccountRequest{Provider: "myspace"})
+	if !errors.Is(err, errs.ErrUnsupportedProvider) {
+		t.Fatalf("expected ErrUnsupportedProvider, got %v", err)
+	}
+}
+
+func TestSolveCheckpoint_OtherProviderAccount(t *testing.T) {
+	ctx := context.Background()
+
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{ID: 1, UserID: userID, AccountID: accountID, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusPending}, nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		solveCheckpointFunc: func(_ context.Context, req *service.SolveCheckpointRequest) (*service.SolveCheckpointResponse, error) {
+			t.Fatal("expected Unipile not to be called for an account of another provider")
+			return nil, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.SolveCheckpoint(ctx, 1, &SolveCheckpointRequest{Provider: "WHATSAPP", AccountID: "acc-1", Code: "123456"})
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Message != "Account not found" {
+		t.Fatalf("expected account not found error, got %v", err)
+	}
+}
+
+func TestConnectAccount_WhatsAppQRCode(t *testing.T) {
+	ctx := context.Background()
+
+	var created *entity.Account
+	accountRepo := &mockAccountRepo{
+		createFunc: func(_ context.Context, account *entity.Account) error {
+			created = account
+			return nil
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		connectAccountFunc: func(_ context.Context, req *service.ConnectAccountRequest) (*service.ConnectAccountResponse, error) {
+			t.Fatal("expected the WhatsApp connect call to be used")
+			return nil, nil
+		},
+		connectWhatsAppFunc: func(_ context.Context) (*service.ConnectAccountResponse, error) {
+			return &service.ConnectAccountResponse{
+				AccountID:  "wa-1",
+				Checkpoint: &service.Checkpoint{Type: "QRCODE", QRCode: "2@abc"},
+			}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	account, err := uc.ConnectAccount(ctx, 1, &ConnectAccountRequest{Provider: "whatsapp"})
+	if err != nil {
+		t.Fatalf("ConnectAccount returned error: %v", err)
+	}
+	if created == nil || account.Provider != ProviderWhatsApp || account.CurrentStatus != entity.AccountStatusPending {
+		t.Fatalf("unexpected account: %+v", account)
+	}
+	history := account.AccountStatusHistories[0]
+	if history.Checkpoint != entity.CheckpointTypeQRCode {
+		t.Fatalf("expected QRCODE checkpoint, got %s", history.Checkpoint)
+	}
+	var checkpoint service.Checkpoint
+	if err := json.Unmarshal(history.CheckpointMetadata, &checkpoint); err != nil || checkpoint.QRCode != "2@abc" {
+		t.Fatalf("expected QR code in checkpoint metadata, got %s", history.CheckpointMetadata)
+	}
+}
+
+func TestGetQRCode(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(3 * time.Minute)
+	recentlyRefreshed := fmt.Sprintf(`{"type":"QRCODE","qrcode":"2@old","refreshed_at":%q}`, time.Now().Format(time.RFC3339Nano))
+
+	newAccount := func(issuedAt time.Time, metadata string) *entity.Account {
+		return &entity.Account{
+			ID: 4, UserID: 1, AccountID: "wa-1", Provider: ProviderWhatsApp, CurrentStatus: entity.AccountStatusPending,
+			AccountStatusHistories: []entity.AccountStatusHistory{{
+				ID: 1, Checkpoint: entity.CheckpointTypeQRCode, CheckpointMetadata: json.RawMessage(metadata),
+				CheckpointExpiresAt: expiresAt, Status: entity.AccountStatusPending, CreatedAt: issuedAt,
+			}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		issuedAt    time.Time
+		metadata    string
+		rotatedCode string
+		wantCode    string
+		wantRefresh bool
+		wantHistory bool
+	}{
+		{"fresh code", time.Now(), `{"type":"QRCODE","qrcode":"2@old"}`, "", "2@old", false, false},
+		{"rotated code", time.Now().Add(-time.Minute), `{"type":"QRCODE","qrcode":"2@old"}`, "2@new", "2@new", true, true},
+		{"code not rotated yet", time.Now().Add(-time.Minute), `{"type":"QRCODE","qrcode":"2@old"}`, "2@old", "2@old", true, false},
+		{"code refreshed recently", time.Now().Add(-time.Minute), recentlyRefreshed, "2@new", "2@old", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := newAccount(tt.issuedAt, tt.metadata)
+			var history *entity.AccountStatusHistory
+			var claimed json.RawMessage
+			accountRepo := &mockAccountRepo{
+				getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+					return account, nil
+				},
+				updateCheckpointMetadataFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+					claimed = h.CheckpointMetadata
+					return nil
+				},
+				createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+					history = h
+					return nil
+				},
+			}
+			var inTx bool
+			txRepo := &mockTxRepo{
+				doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+					inTx = true
+					defer func() { inTx = false }()
+					return fn(&repository.Repositories{Account: accountRepo})
+				},
+			}
+			var refreshed bool
+			unipileClient := &mockUnipileClient{
+				resendCheckpointFunc: func(_ context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+					refreshed = true
+					if inTx {
+						t.Fatalf("expected Unipile to be called outside the account lock")
+					}
+					if req.Provider != ProviderWhatsApp || req.AccountID != "wa-1" {
+						t.Fatalf("unexpected refresh request: %+v", req)
+					}
+					return &service.ResendCheckpointResponse{
+						AccountID:  "wa-1",
+						Checkpoint: &service.Checkpoint{Type: "QRCODE", QRCode: tt.rotatedCode},
+					}, nil
+				},
+			}
+
+			uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+			qrCode, err := uc.GetQRCode(ctx, 1, ProviderWhatsApp, "wa-1")
+			if err != nil {
+				t.Fatalf("GetQRCode returned error: %v", err)
+			}
+			if qrCode.Code != tt.wantCode {
+				t.Fatalf("expected code %s, got %s", tt.wantCode, qrCode.Code)
+			}
+			if refreshed != tt.wantRefresh {
+				t.Fatalf("unexpected refresh: %v", refreshed)
+			}
+			// A refresh is recorded on the checkpoint before Unipile is called, so the next polls are throttled
+			if tt.wantRefresh != (claimed != nil) || (claimed != nil && !strings.Contains(string(claimed), `"refreshed_at"`)) {
+				t.Fatalf("unexpected checkpoint metadata update: %s", claimed)
+			}
+			if (history != nil) != tt.wantHistory {
+				t.Fatalf("unexpected status history: %+v", history)
+			}
+			if history != nil {
+				if history.Cause != entity.StatusCauseQRCodeRefresh || history.Status != entity.AccountStatusPending || !history.CheckpointExpiresAt.Equal(expiresAt) {
+					t.Fatalf("unexpected status history: %+v", history)
+				}
+			}
+		})
+	}
+}
+
+func TestGetQRCode_RotatedConcurrently(t *testing.T) {
+	expiresAt := time.Now().Add(3 * time.Minute)
+	account := &entity.Account{
+		ID: 4, UserID: 1, AccountID: "wa-1", Provider: ProviderWhatsApp, CurrentStatus: entity.AccountStatusPending,
+		AccountStatusHistories: []entity.AccountStatusHistory{{
+			ID: 1, Checkpoint: entity.CheckpointTypeQRCode, CheckpointMetadata: json.RawMessage(`{"type":"QRCODE","qrcode":"2@old"}`),
+			CheckpointExpiresAt: expiresAt, Status: entity.AccountStatusPending, CreatedAt: time.Now().Add(-time.Minute),
+		}},
+	}
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return account, nil
+		},
+		createStatusHistoryFunc: func(_ context.Context, h *entity.AccountStatusHistory) error {
+			t.Fatalf("expected no status history for a checkpoint rotated by another poll")
+			return nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		resendCheckpointFunc: func(_ context.Context, req *service.ResendCheckpointRequest) (*service.ResendCheckpointResponse, error) {
+			// Another poll records a rotated code while Unipile is called
+			account.AccountStatusHistories = append(account.AccountStatusHistories, entity.AccountStatusHistory{
+				ID: 2, Checkpoint: entity.CheckpointTypeQRCode, CheckpointMetadata: json.RawMessage(`{"type":"QRCODE","qrcode":"2@other"}`),
+				CheckpointExpiresAt: expiresAt, Status: entity.AccountStatusPending, CreatedAt: time.Now(),
+			})
+			return &service.ResendCheckpointResponse{AccountID: "wa-1", Checkpoint: &service.Checkpoint{Type: "QRCODE", QRCode: "2@new"}}, nil
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	qrCode, err := uc.GetQRCode(context.Background(), 1, ProviderWhatsApp, "wa-1")
+	if err != nil {
+		t.Fatalf("GetQRCode returned error: %v", err)
+	}
+	if qrCode.Code != "2@other" {
+		t.Fatalf("expected the code recorded by the other poll, got %s", qrCode.Code)
+	}
+}
+
+func TestGetQRCode_NoQRCodeCheckpoint(t *testing.T) {
+	accountRepo := &mockAccountRepo{
+		getByUserIDAndAccountIDForUpdate: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			return &entity.Account{
+				ID: 1, UserID: userID, AccountID: accountID, Provider: ProviderWhatsApp, CurrentStatus: entity.AccountStatusPending,
+				AccountStatusHistories: []entity.AccountStatusHistory{{Checkpoint: entity.CheckpointType2FA, Status: entity.AccountStatusPending}},
+			}, nil
+		},
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	uc := NewAccountUsecase(txRepo, accountRepo, &mockUnipileClient{}, &mockEventHub{}, CheckpointConfig{}, nil, HostedAuthConfig{}, logrus.New())
+
+	_, err := uc.GetQRCode(context.Background(), 1, ProviderWhatsApp, "wa-1")
+	if !errors.Is(err, errs.ErrNoPendingCheckpoint) {
+		t.Fatalf("expected ErrNoPendingCheckpoint, got %v", err)
+	}
+}
+
+func TestCreateHostedAuthLink(t *testing.T) {
+	ctx := context.Background()
+	stateService := service.NewHostedAuthStateService("state-secret")
+
+	var received *service.HostedAuthLinkRequest
+	unipileClient := &mockUnipileClient{
+		createHostedAuthLinkFunc: func(_ context.Context, req *service.HostedAuthLinkRequest) (*service.HostedAuthLinkResponse, error) {
+			received = req
+			return &service.HostedAuthLinkResponse{Object: "HostedAuthURL", URL: "https://account.unipile.com/link"}, nil
+		},
+	}
+
+	var issued *entity.HostedAuthState
+	accountRepo := &mockAccountRepo{
+		createHostedAuthStateFunc: func(_ context.Context, state *entity.HostedAuthState) error {
+			issued = state
+			return nil
+		},
+	}
+
+	uc := NewAccountUsecase(&mockTxRepo{}, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, stateService, HostedAuthConfig{
+		NotifyURL:          "https://app.example.com/api/v1/webhooks/unipile/hosted-auth",
+		SuccessRedirectURL: "https://app.example.com/dashboard",
+		LinkTTL:            30 * time.Minute,
+	}, logrus.New())
+
+	link, err := uc.CreateHostedAuthLink(ctx, 42, []string{"linkedin", "WhatsApp"})
+	if err != nil {
+		t.Fatalf("CreateHostedAuthLink returned error: %v", err)
+	}
+	if link.URL != "https://account.unipile.com/link" {
+		t.Fatalf("unexpected link: %+v", link)
+	}
+	if time.Until(link.ExpiresAt) > 30*time.Minute || time.Until(link.ExpiresAt) < 29*time.Minute {
+		t.Fatalf("unexpected link expiry: %s", link.ExpiresAt)
+	}
+
+	if received.Type != "create" || received.Name != "42" || received.SuccessRedirectURL != "https://app.example.com/dashboard" {
+		t.Fatalf("unexpected hosted auth request: %+v", received)
+	}
+	if providers, ok := received.Providers.([]string); !ok || len(providers) != 2 || providers[0] != "LINKEDIN" || providers[1] != "WHATSAPP" {
+		t.Fatalf("unexpected providers: %v", received.Providers)
+	}
+	if received.ExpiresOn != link.ExpiresAt.Format(hostedAuthExpiresOnLayout) {
+		t.Fatalf("unexpected expiresOn: %s", received.ExpiresOn)
+	}
+
+	notifyURL, err := url.Parse(received.NotifyURL)
+	if err != nil {
+		t.Fatalf("failed to parse notify URL: %v", err)
+	}
+	if notifyURL.Path != "/api/v1/webhooks/unipile/hosted-auth" {
+		t.Fatalf("unexpected notify URL: %s", received.NotifyURL)
+	}
+	userID, nonce, err := stateService.ValidateState(notifyURL.Query().Get("state"))
+	if err != nil || userID != 42 {
+		t.Fatalf("expected state for user 42, got %d (%v)", userID, err)
+	}
+	if issued == nil || issued.Nonce != nonce || issued.UserID != 42 || !issued.ExpiresAt.Equal(link.ExpiresAt.Add(hostedAuthStateGrace)) {
+		t.Fatalf("unexpected stored state: %+v", issued)
+	}
+
+	if _, err := uc.CreateHostedAuthLink(ctx, 42, []string{"unknown"}); !errors.Is(err, errs.ErrUnsupportedProvider) {
+		t.Fatalf("expected ErrUnsupportedProvider, got %v", err)
+	}
+}
+
+// newHostedAuthStates makes the account repository store hosted auth states in memory and returns a function issuing them
+func newHostedAuthStates(t *testing.T, accountRepo *mockAccountRepo, stateService service.HostedAuthStateService) func(userID uint) string {
+	states := map[string]*entity.HostedAuthState{}
+	accountRepo.getHostedAuthStateForUpdateFunc = func(_ context.Context, nonce string) (*entity.HostedAuthState, error) {
+		state, ok := states[nonce]
+		if !ok {
+			return nil, repository.ErrHostedAuthStateNotFound
+		}
+		copied := *state
+		return &copied, nil
+	}
+	accountRepo.saveHostedAuthStateFunc = func(_ context.Context, state *entity.HostedAuthState) error {
+		states[state.Nonce] = state
+		return nil
+	}
+	return func(userID uint) string {
+		state, nonce, err := stateService.GenerateState(userID, time.Hour)
+		if err != nil {
+			t.Fatalf("failed to generate state: %v", err)
+		}
+		states[nonce] = &entity.HostedAuthState{Nonce: nonce, UserID: userID, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now().Add(-time.Minute)}
+		return state
+	}
+}
+
+func TestHandleHostedAuthNotification(t *testing.T) {
+	ctx := context.Background()
+	stateService := service.NewHostedAuthStateService("state-secret")
+
+	var stored *entity.Account
+	accountRepo := &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			if stored == nil || stored.AccountID != accountID {
+				return nil, repository.ErrAccountNotFound
+			}
+			copied := *stored
+			return &copied, nil
+		},
+		createFunc: func(_ context.Context, account *entity.Account) error {
+			stored = account
+			return nil
+		},
+	}
+	issueState := newHostedAuthStates(t, accountRepo, stateService)
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+	unipileClient := &mockUnipileClient{
+		getAccountFunc: func(_ context.Context, accountID string) (*service.Account, error) {
+			return &service.Account{ID: accountID, Type: "LINKEDIN", CreatedAt: time.Now().UTC().Format(time.RFC3339)}, nil
+		},
+	}
+
+	eventHub := &mockEventHub{}
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, eventHub, CheckpointConfig{}, stateService, HostedAuthConfig{}, logrus.New())
+
+	state := issueState(42)
+	notification := &HostedAuthNotification{Status: "CREATION_SUCCESS", AccountID: "acc-1", Name: "42"}
+	account, err := uc.HandleHostedAuthNotification(ctx, state, notification)
+	if err != nil {
+		t.Fatalf("HandleHostedAuthNotification returned error: %v", err)
+	}
+	if account == nil || account.UserID != 42 || account.Provider != "LINKEDIN" || account.CurrentStatus != "OK" {
+		t.Fatalf("unexpected account: %+v", account)
+	}
+	if len(stored.AccountStatusHistories) != 1 || stored.AccountStatusHistories[0].Cause != entity.StatusCauseHostedAuth {
+		t.Fatalf("unexpected status histories: %+v", stored.AccountStatusHistories)
+	}
+	if len(eventHub.published) != 1 || eventHub.userIDs[0] != 42 {
+		t.Fatalf("expected one event for user 42, got %+v", eventHub.published)
+	}
+
+	// A retried notification does not create a second account
+	accountRepo.createFunc = func(_ context.Context, account *entity.Account) error {
+		t.Fatal("expected no account to be created")
+		return nil
+	}
+	account, err = uc.HandleHostedAuthNotification(ctx, state, notification)
+	if err != nil || account != nil {
+		t.Fatalf("expected retried notification to be ignored, got %+v (%v)", account, err)
+	}
+
+	// The used state cannot attach another account
+	_, err = uc.HandleHostedAuthNotification(ctx, state, &HostedAuthNotification{Status: "CREATION_SUCCESS", AccountID: "acc-2", Name: "42"})
+	if !errors.Is(err, errs.ErrInvalidHostedAuthState) {
+		t.Fatalf("expected ErrInvalidHostedAuthState, got %v", err)
+	}
+
+	// The account cannot be claimed by another user
+	_, err = uc.HandleHostedAuthNotification(ctx, issueState(7), &HostedAuthNotification{Status: "CREATION_SUCCESS", AccountID: "acc-1", Name: "7"})
+	if !errors.Is(err, errs.ErrAccountOwnedByAnotherUser) {
+		t.Fatalf("expected ErrAccountOwnedByAnotherUser, got %v", err)
+	}
+
+	// Notifications for an account that can no longer come back are acknowledged
+	stored.CurrentStatus = entity.AccountStatusDeleted
+	account, err = uc.HandleHostedAuthNotification(ctx, issueState(42), notification)
+	if err != nil || account != nil {
+		t.Fatalf("expected notification for a deleted account to be ignored, got %+v (%v)", account, err)
+	}
+	if len(eventHub.published) != 1 {
+		t.Fatalf("expected no new event, got %+v", eventHub.published)
+	}
+}
+
+func TestHandleHostedAuthNotification_InvalidState(t *testing.T) {
+	ctx := context.Background()
+	stateService := service.NewHostedAuthStateService("state-secret")
+	accountRepo := &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			t.Fatal("expected no account to be looked up")
+			return nil, nil
+		},
+	}
+	issueState := newHostedAuthStates(t, accountRepo, stateService)
+	state := issueState(42)
+	unknownState, _, err := stateService.GenerateState(42, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate state: %v", err)
+	}
+	txRepo := &mockTxRepo{
+		doFunc: func(ctx context.Context, fn func(*repository.Repositories) error) error {
+			return fn(&repository.Repositories{Account: accountRepo})
+		},
+	}
+
+	accountCreatedAt := time.Now()
+	unipileClient := &mockUnipileClient{
+		getAccountFunc: func(_ context.Context, accountID string) (*service.Account, error) {
+			return &service.Account{ID: accountID, Type: "LINKEDIN", CreatedAt: accountCreatedAt.UTC().Format(time.RFC3339)}, nil
+		},
+	}
+	uc := NewAccountUsecase(txRepo, accountRepo, unipileClient, &mockEventHub{}, CheckpointConfig{}, stateService, HostedAuthConfig{}, logrus.New())
+
+	tests := []struct {
+		name      string
+		state     string
+		user      string
+		createdAt time.Time
+	}{
+		{name: "tampered state", state: state + "x", user: "42", createdAt: time.Now()},
+		{name: "state of another user", state: state, user: "7", createdAt: time.Now()},
+		{name: "state not issued", state: unknownState, user: "42", createdAt: time.Now()},
+		{name: "account created before the link", state: state, user: "42", createdAt: time.Now().Add(-24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountCreatedAt = tt.createdAt
+			_, err := uc.HandleHostedAuthNotification(ctx, tt.state, &HostedAuthNotification{Status: "CREATION_SUCCESS", AccountID: "acc-1", Name: tt.user})
+			if !errors.Is(err, errs.ErrInvalidHostedAuthState) {
+				t.Fatalf("expected ErrInvalidHostedAuthState, got %v", err)
+			}
+		})
+	}
+}
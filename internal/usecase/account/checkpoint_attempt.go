@@ -0,0 +1,67 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// checkpointAttempt returns the attempt counter of the pending checkpoint of the account.
+// A new counter is returned when no code was submitted yet, nil when the account has no checkpoint.
+func checkpointAttempt(ctx context.Context, repos *repository.Repositories, account *entity.Account) (*entity.CheckpointAttempt, error) {
+	checkpoint := latestCheckpoint(account.AccountStatusHistories)
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	attempt, err := repos.Account.GetCheckpointAttempt(ctx, checkpoint.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckpointAttemptNotFound) {
+			return &entity.CheckpointAttempt{
+				AccountID:       account.ID,
+				StatusHistoryID: checkpoint.ID,
+				Checkpoint:      checkpoint.Checkpoint,
+			}, nil
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get checkpoint attempts")
+	}
+	return attempt, nil
+}
+
+// recordFailedAttempt counts a rejected code and locks the checkpoint once the attempt limit is reached.
+// It reports whether the checkpoint got locked.
+func (a *UsecaseImpl) recordFailedAttempt(ctx context.Context, repos *repository.Repositories, account *entity.Account, attempt *entity.CheckpointAttempt) (bool, error) {
+	if attempt == nil {
+		return false, nil
+	}
+
+	attempt.FailedAttempts++
+	locked := attempt.FailedAttempts >= a.checkpointConfig.maxAttempts()
+	if locked {
+		now := time.Now()
+		attempt.LockedAt = &now
+	}
+	if err := repos.Account.SaveCheckpointAttempt(ctx, attempt); err != nil {
+		return false, errs.WrapInternalError(err, "Failed to save checkpoint attempts")
+	}
+	if !locked {
+		return false, nil
+	}
+
+	// Audit the lock, the row carries no checkpoint so the locked checkpoint stays the pending one
+	if err := setAccountStatus(ctx, repos, account, account.CurrentStatus, entity.StatusCauseLocked, attempt); err != nil {
+		return false, err
+	}
+	a.logger.WithFields(logrus.Fields{
+		"accountID":      account.AccountID,
+		"checkpoint":     attempt.Checkpoint,
+		"failedAttempts": attempt.FailedAttempts,
+	}).Warn("Checkpoint locked after too many invalid codes")
+	return true, nil
+}
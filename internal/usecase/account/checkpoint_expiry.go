@@ -0,0 +1,153 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// ExpireCheckpoints moves PENDING accounts whose checkpoint expired to EXPIRED.
+// The expiry is recorded in the status history, and the half-created Unipile account
+// is deleted when DeleteExpiredAccounts is enabled.
+// Accounts pending on a reconnect checkpoint go back to the status they had before the reconnect instead,
+// their Unipile account is kept.
+func (a *UsecaseImpl) ExpireCheckpoints(ctx context.Context) error {
+	accounts, err := a.accountRepo.ListPendingWithExpiredCheckpoint(ctx, time.Now())
+	if err != nil {
+		return errs.WrapInternalError(err, "Failed to list accounts with expired checkpoints")
+	}
+
+	var expired, failed int
+	for _, account := range accounts {
+		logFields := logrus.Fields{
+			"userID":    account.UserID,
+			"accountID": account.AccountID,
+		}
+
+		expiredAccount, restored, err := a.expireCheckpoint(ctx, account)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+			a.logger.WithError(err).WithFields(logFields).Error("Failed to expire checkpoint")
+			continue
+		}
+		if expiredAccount == nil {
+			continue
+		}
+		expired++
+		a.publishAccountStatus(expiredAccount)
+
+		if restored || !a.checkpointConfig.DeleteExpiredAccounts {
+			continue
+		}
+		if err := a.unipileClient.DeleteAccount(ctx, account.AccountID); err != nil && !errors.Is(err, service.ErrUnipileAccountNotFound) {
+			a.logger.WithError(err).WithFields(logFields).Warn("Failed to delete Unipile account with expired checkpoint")
+		}
+	}
+
+	if expired > 0 || failed > 0 {
+		a.logger.WithFields(logrus.Fields{
+			"expired": expired,
+			"failed":  failed,
+		}).Info("Checkpoint expiry sweep completed")
+	}
+
+	return nil
+}
+
+// expireCheckpoint moves the account to EXPIRED when its latest checkpoint is still expired once locked,
+// or back to its status before the reconnect when the checkpoint came from a reconnect.
+// It returns the expired account, or nil when the account no longer needs to expire,
+// and whether it was restored rather than expired.
+func (a *UsecaseImpl) expireCheckpoint(ctx context.Context, account *entity.Account) (*entity.Account, bool, error) {
+	var expired *entity.Account
+	var restored bool
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		locked, err := repos.Account.GetByUserIDAndAccountIDForUpdate(ctx, account.UserID, account.AccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return nil
+			}
+			return err
+		}
+		// The checkpoint may have been solved or renewed since the accounts were listed
+		if locked.CurrentStatus != entity.AccountStatusPending {
+			return nil
+		}
+		checkpoint := latestCheckpoint(locked.AccountStatusHistories)
+		if checkpoint != nil && checkpoint.CheckpointExpiresAt.After(time.Now()) {
+			return nil
+		}
+
+		history := &entity.AccountStatusHistory{
+			Status: entity.AccountStatusExpired,
+			Cause:  entity.StatusCauseExpiry,
+		}
+		// An abandoned reconnect must not lose the account it was meant to reconnect
+		if status := reconnectedFrom(locked.AccountStatusHistories); status != "" {
+			history.Status = status
+			restored = true
+		}
+		if checkpoint != nil {
+			history.Checkpoint = checkpoint.Checkpoint
+			history.CheckpointMetadata = checkpoint.CheckpointMetadata
+			history.CheckpointExpiresAt = checkpoint.CheckpointExpiresAt
+		}
+		if err := applyStatusHistory(ctx, repos, locked, history); err != nil {
+			return err
+		}
+		expired = locked
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+	return expired, restored, nil
+}
+
+// reconnectedFrom returns the status the account had before the reconnect that made it PENDING,
+// or the empty status when the account became PENDING some other way
+func reconnectedFrom(histories []entity.AccountStatusHistory) entity.AccountStatus {
+	var pendingSince *entity.AccountStatusHistory
+	for i := range histories {
+		history := &histories[i]
+		if history.Status != entity.AccountStatusPending || history.PreviousStatus == entity.AccountStatusPending {
+			continue
+		}
+		if pendingSince == nil || isLaterHistory(history, pendingSince) {
+			pendingSince = history
+		}
+	}
+	if pendingSince == nil || pendingSince.Cause != entity.StatusCauseReconnect {
+		return ""
+	}
+	return pendingSince.PreviousStatus
+}
+
+// latestCheckpoint returns the most recent history row that carries a checkpoint
+func latestCheckpoint(histories []entity.AccountStatusHistory) *entity.AccountStatusHistory {
+	var latest *entity.AccountStatusHistory
+	for i := range histories {
+		history := &histories[i]
+		if history.Checkpoint == "" {
+			continue
+		}
+		if latest == nil || isLaterHistory(history, latest) {
+			latest = history
+		}
+	}
+	return latest
+}
+
+// isLaterHistory reports whether history was recorded after other
+func isLaterHistory(history, other *entity.AccountStatusHistory) bool {
+	return history.CreatedAt.After(other.CreatedAt) || (history.CreatedAt.Equal(other.CreatedAt) && history.ID > other.ID)
+}
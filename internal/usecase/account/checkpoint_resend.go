@@ -0,0 +1,76 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// ResendCheckpoint asks Unipile for a new checkpoint code and records the new checkpoint with a fresh expiry
+func (a *UsecaseImpl) ResendCheckpoint(ctx context.Context, userID uint, provider, accountID string) (*entity.Account, error) {
+	var account *entity.Account
+
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		var err error
+
+		account, err = lockProviderAccount(ctx, repos, userID, provider, accountID)
+		if err != nil {
+			return err
+		}
+		current := latestCheckpoint(account.AccountStatusHistories)
+		if account.CurrentStatus != entity.AccountStatusPending || current == nil {
+			return errs.ErrNoPendingCheckpoint
+		}
+
+		resp, err := a.unipileClient.ResendCheckpoint(ctx, &service.ResendCheckpointRequest{
+			Provider:  account.Provider,
+			AccountID: accountID,
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrUnipileInvalidCodeOrExpiredCheckpoint) {
+				return errs.ErrInvalidCodeOrExpiredCheckpoint
+			}
+			return a.unipileError(err, "Failed to resend checkpoint")
+		}
+
+		// Unipile may answer without the checkpoint, the pending one is then kept
+		checkpointType := current.Checkpoint
+		checkpointMetadata := current.CheckpointMetadata
+		if resp.Checkpoint != nil {
+			checkpointType = entity.CheckpointType(resp.Checkpoint.Type)
+			if checkpointMetadata, err = json.Marshal(resp.Checkpoint); err != nil {
+				return errs.WrapInternalError(err, "Failed to marshal checkpoint")
+			}
+		}
+
+		var payload json.RawMessage
+		if resp.RowBody != "" {
+			payload = json.RawMessage(resp.RowBody)
+		}
+
+		history := &entity.AccountStatusHistory{
+			Checkpoint:          checkpointType,
+			CheckpointMetadata:  checkpointMetadata,
+			CheckpointExpiresAt: time.Now().Add(a.checkpointConfig.checkpointTTL(checkpointType)),
+			Status:              entity.AccountStatusPending,
+			Cause:               entity.StatusCauseResend,
+			Payload:             payload,
+		}
+		if err := applyStatusHistory(ctx, repos, account, history); err != nil {
+			return err
+		}
+		account.AccountStatusHistories = append(account.AccountStatusHistories, *history)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	a.publishAccountStatus(account)
+
+	return account, nil
+}
@@ -0,0 +1,240 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// defaultHostedAuthLinkTTL is used when no hosted auth link lifetime is configured
+const defaultHostedAuthLinkTTL = time.Hour
+
+// hostedAuthStateGrace keeps the state valid after the link expires, for wizards completed just before it
+const hostedAuthStateGrace = 30 * time.Minute
+
+// hostedAuthClockSkew tolerates clock differences with Unipile when matching an account creation time to a link
+const hostedAuthClockSkew = time.Minute
+
+// hostedAuthExpiresOnLayout is the ISO 8601 layout Unipile expects for the link expiry
+const hostedAuthExpiresOnLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// HostedAuthConfig controls the links to the Unipile hosted auth wizard
+type HostedAuthConfig struct {
+	NotifyURL          string        // Callback Unipile notifies once an account is connected, the state is added to it
+	SuccessRedirectURL string        // Page the wizard redirects to after a successful connection
+	FailureRedirectURL string        // Page the wizard redirects to after a failed connection
+	LinkTTL            time.Duration // Lifetime of a link
+}
+
+// linkTTL returns the lifetime of a hosted auth link
+func (c HostedAuthConfig) linkTTL() time.Duration {
+	if c.LinkTTL > 0 {
+		return c.LinkTTL
+	}
+	return defaultHostedAuthLinkTTL
+}
+
+// HostedAuthLink is a link to the Unipile hosted auth wizard
+type HostedAuthLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// HostedAuthNotification represents the payload Unipile sends to the notify URL of a hosted auth link
+type HostedAuthNotification struct {
+	Status    string `json:"status"` // CREATION_SUCCESS, RECONNECTED
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"` // Name given to the link, the user ID
+}
+
+// CreateHostedAuthLink creates a link to the Unipile hosted auth wizard for the given providers, all providers when empty.
+// The link carries the user ID in its name and a signed single use state in its notify URL, so the callback can be attributed to the user.
+func (a *UsecaseImpl) CreateHostedAuthLink(ctx context.Context, userID uint, providers []string) (*HostedAuthLink, error) {
+	providerNames := make([]string, 0, len(providers))
+	for _, name := range providers {
+		provider, err := a.providers.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		providerNames = append(providerNames, provider.Name)
+	}
+	if len(providerNames) == 0 {
+		for _, provider := range a.providers.List() {
+			providerNames = append(providerNames, provider.Name)
+		}
+	}
+
+	ttl := a.hostedAuthConfig.linkTTL()
+	state, nonce, err := a.hostedAuthState.GenerateState(userID, ttl+hostedAuthStateGrace)
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to generate hosted auth state")
+	}
+	notifyURL, err := url.Parse(a.hostedAuthConfig.NotifyURL)
+	if err != nil || notifyURL.Host == "" {
+		return nil, errs.WrapInternalError(errors.New("invalid hosted auth notify URL"), "Hosted auth is not configured")
+	}
+	query := notifyURL.Query()
+	query.Set("state", state)
+	notifyURL.RawQuery = query.Encode()
+
+	expiresAt := time.Now().Add(ttl).UTC()
+	if err := a.accountRepo.CreateHostedAuthState(ctx, &entity.HostedAuthState{
+		Nonce:     nonce,
+		UserID:    userID,
+		ExpiresAt: expiresAt.Add(hostedAuthStateGrace),
+	}); err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to save hosted auth state")
+	}
+
+	resp, err := a.unipileClient.CreateHostedAuthLink(ctx, &service.HostedAuthLinkRequest{
+		Type:               "create",
+		Providers:          providerNames,
+		ExpiresOn:          expiresAt.Format(hostedAuthExpiresOnLayout),
+		Name:               strconv.FormatUint(uint64(userID), 10),
+		NotifyURL:          notifyURL.String(),
+		SuccessRedirectURL: a.hostedAuthConfig.SuccessRedirectURL,
+		FailureRedirectURL: a.hostedAuthConfig.FailureRedirectURL,
+	})
+	if err != nil {
+		return nil, a.unipileError(err, "Failed to create hosted auth link")
+	}
+
+	return &HostedAuthLink{URL: resp.URL, ExpiresAt: expiresAt}, nil
+}
+
+// HandleHostedAuthNotification stores the account connected through the hosted auth wizard.
+// The state must be valid, issued to the user named in the notification and not used for another account,
+// and the account must have been created on Unipile while the link was valid.
+// Retried notifications and notifications for accounts that expired or were deleted are ignored.
+// It returns the stored account, or nil when the notification was ignored.
+func (a *UsecaseImpl) HandleHostedAuthNotification(ctx context.Context, state string, notification *HostedAuthNotification) (*entity.Account, error) {
+	userID, nonce, err := a.hostedAuthState.ValidateState(state)
+	if err != nil {
+		return nil, errs.ErrInvalidHostedAuthState
+	}
+	if notification.Name != strconv.FormatUint(uint64(userID), 10) {
+		return nil, errs.ErrInvalidHostedAuthState
+	}
+
+	logFields := logrus.Fields{
+		"userID":    userID,
+		"accountID": notification.AccountID,
+		"status":    notification.Status,
+	}
+	if webhookStatuses[notification.Status] != entity.AccountStatusOK {
+		a.logger.WithFields(logFields).Info("Ignoring unsupported hosted auth notification")
+		return nil, nil
+	}
+
+	unipileAccount, err := a.unipileClient.GetAccount(ctx, notification.AccountID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileAccountNotFound) {
+			return nil, errs.WrapValidationError(errors.New("account not found"), "Account not found")
+		}
+		return nil, a.unipileError(err, "Failed to get account from Unipile")
+	}
+
+	var account *entity.Account
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		issued, err := repos.Account.GetHostedAuthStateForUpdate(ctx, nonce)
+		if err != nil {
+			if errors.Is(err, repository.ErrHostedAuthStateNotFound) {
+				return errs.ErrInvalidHostedAuthState
+			}
+			return errs.WrapInternalError(err, "Failed to get hosted auth state")
+		}
+		if issued.UserID != userID {
+			return errs.ErrInvalidHostedAuthState
+		}
+		if issued.IsUsed() {
+			if issued.AccountID != notification.AccountID {
+				return errs.ErrInvalidHostedAuthState
+			}
+			// A retried notification, the account was already attached
+			return nil
+		}
+		if !createdWithLink(unipileAccount, issued) {
+			return errs.ErrInvalidHostedAuthState
+		}
+
+		locked, err := repos.Account.GetByAccountIDForUpdate(ctx, notification.AccountID)
+		if err != nil && !errors.Is(err, repository.ErrAccountNotFound) {
+			return errs.WrapInternalError(err, "Failed to get account")
+		}
+		if locked != nil {
+			if locked.UserID != userID {
+				return errs.ErrAccountOwnedByAnotherUser
+			}
+			// Expired and deleted accounts cannot come back, failing would only make Unipile retry
+			if locked.CurrentStatus.IsTerminal() {
+				return nil
+			}
+			if locked.CurrentStatus != entity.AccountStatusOK {
+				if err := setAccountStatus(ctx, repos, locked, entity.AccountStatusOK, entity.StatusCauseHostedAuth, notification); err != nil {
+					return err
+				}
+			}
+			account = locked
+			return useHostedAuthState(ctx, repos, issued, notification.AccountID)
+		}
+
+		created := &entity.Account{
+			UserID:    userID,
+			Provider:  unipileAccount.Type,
+			AccountID: notification.AccountID,
+		}
+		history, err := newStatusHistory(entity.AccountStatusOK, entity.StatusCauseHostedAuth, notification)
+		if err != nil {
+			return errs.WrapInternalError(err, "Failed to marshal status history")
+		}
+		if err := created.TransitionTo(history.Status); err != nil {
+			return errs.WrapBusinessError(err, "Invalid account status transition")
+		}
+		created.AccountStatusHistories = append(created.AccountStatusHistories, *history)
+		if err := repos.Account.Create(ctx, created); err != nil {
+			return errs.WrapInternalError(err, "Failed to create account")
+		}
+		account = created
+		return useHostedAuthState(ctx, repos, issued, notification.AccountID)
+	}); err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		a.logger.WithFields(logFields).Info("Ignoring hosted auth notification for an account already handled")
+		return nil, nil
+	}
+
+	a.logger.WithFields(logFields).WithField("provider", account.Provider).Info("Account connected through hosted auth")
+	a.publishAccountStatus(account)
+	return account, nil
+}
+
+// createdWithLink reports whether the Unipile account was created while the link of the state was valid
+func createdWithLink(account *service.Account, state *entity.HostedAuthState) bool {
+	createdAt, err := time.Parse(time.RFC3339, account.CreatedAt)
+	if err != nil {
+		return false
+	}
+	return !createdAt.Before(state.CreatedAt.Add(-hostedAuthClockSkew)) && !createdAt.After(state.ExpiresAt)
+}
+
+// useHostedAuthState marks the state as used to attach the account, so it cannot attach another one
+func useHostedAuthState(ctx context.Context, repos *repository.Repositories, state *entity.HostedAuthState, accountID string) error {
+	usedAt := time.Now()
+	state.AccountID = accountID
+	state.UsedAt = &usedAt
+	if err := repos.Account.SaveHostedAuthState(ctx, state); err != nil {
+		return errs.WrapInternalError(err, "Failed to save hosted auth state")
+	}
+	return nil
+}
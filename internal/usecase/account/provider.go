@@ -0,0 +1,175 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+)
+
+// Names of the providers with a dedicated connect flow
+const (
+	ProviderLinkedIn = "LINKEDIN"
+	ProviderWhatsApp = "WHATSAPP"
+)
+
+// Auth method types
+const (
+	AuthTypeCredentials = "credentials"
+	AuthTypeCookie      = "cookie"
+	AuthTypeQRCode      = "qrcode"
+)
+
+// Auth fields of a connect request
+const (
+	AuthFieldUsername    = "username"
+	AuthFieldPassword    = "password"
+	AuthFieldAccessToken = "access_token"
+)
+
+// AuthMethod is a way to authenticate to a provider and the request fields it requires
+type AuthMethod struct {
+	Type   string   `json:"type"`
+	Fields []string `json:"fields"`
+}
+
+// Provider declares how accounts of a Unipile provider are connected
+type Provider struct {
+	Name            string                  `json:"name"` // Unipile provider, e.g. "LINKEDIN"
+	DisplayName     string                  `json:"display_name"`
+	AuthMethods     []AuthMethod            `json:"auth_methods"`
+	CheckpointTypes []entity.CheckpointType `json:"checkpoint_types"` // Checkpoints Unipile may return while connecting
+}
+
+// ValidateAuth checks the request carries the fields required by its auth method.
+// The type may be left empty for providers with a single auth method.
+func (p *Provider) ValidateAuth(req *ConnectAccountRequest) error {
+	method := p.authMethod(req.Type)
+	if method == nil {
+		types := make([]string, 0, len(p.AuthMethods))
+		for _, m := range p.AuthMethods {
+			types = append(types, "'"+m.Type+"'")
+		}
+		return validationError(fmt.Sprintf("type must be %s", strings.Join(types, " or ")))
+	}
+
+	for _, field := range method.Fields {
+		if req.authField(field) == "" {
+			names := make([]string, 0, len(method.Fields))
+			for _, f := range method.Fields {
+				names = append(names, strings.ReplaceAll(f, "_", " "))
+			}
+			return validationError(fmt.Sprintf("%s required for %s type", strings.Join(names, " and "), method.Type))
+		}
+	}
+	return nil
+}
+
+// authMethod returns the auth method of the given type, nil when the provider does not accept it
+func (p *Provider) authMethod(authType string) *AuthMethod {
+	if authType == "" && len(p.AuthMethods) == 1 {
+		return &p.AuthMethods[0]
+	}
+	for i := range p.AuthMethods {
+		if p.AuthMethods[i].Type == authType {
+			return &p.AuthMethods[i]
+		}
+	}
+	return nil
+}
+
+// validationError wraps detail in a validation error whose message is detail as a sentence
+func validationError(detail string) error {
+	return errs.WrapValidationError(errors.New(detail), strings.ToUpper(detail[:1])+detail[1:])
+}
+
+// SupportsCheckpoint reports whether the provider declares the checkpoint type
+func (p *Provider) SupportsCheckpoint(checkpointType entity.CheckpointType) bool {
+	for _, t := range p.CheckpointTypes {
+		if t == checkpointType {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderRegistry holds the providers accounts can be connected with
+type ProviderRegistry interface {
+	Get(name string) (*Provider, error)
+	List() []Provider
+}
+
+// providerRegistry holds the providers accounts can be connected with
+type providerRegistry struct {
+	providers []Provider
+}
+
+// NewProviderRegistry creates a registry of the given providers
+func NewProviderRegistry(providers ...Provider) ProviderRegistry {
+	return &providerRegistry{providers: providers}
+}
+
+// Get returns the provider with the given name, matched case-insensitively
+func (r *providerRegistry) Get(name string) (*Provider, error) {
+	for i := range r.providers {
+		if strings.EqualFold(r.providers[i].Name, name) {
+			return &r.providers[i], nil
+		}
+	}
+	return nil, errs.ErrUnsupportedProvider
+}
+
+// List returns the registered providers
+func (r *providerRegistry) List() []Provider {
+	return append([]Provider(nil), r.providers...)
+}
+
+// DefaultProviders returns the Unipile providers supported by the connector
+func DefaultProviders() []Provider {
+	credentials := AuthMethod{Type: AuthTypeCredentials, Fields: []string{AuthFieldUsername, AuthFieldPassword}}
+	qrCode := AuthMethod{Type: AuthTypeQRCode, Fields: []string{}}
+
+	return []Provider{
+		{
+			Name:        ProviderLinkedIn,
+			DisplayName: "LinkedIn",
+			AuthMethods: []AuthMethod{
+				credentials,
+				{Type: AuthTypeCookie, Fields: []string{AuthFieldAccessToken}},
+			},
+			CheckpointTypes: []entity.CheckpointType{
+				entity.CheckpointType2FA,
+				entity.CheckpointTypeOTP,
+				entity.CheckpointTypeInAppValidation,
+				entity.CheckpointTypeCaptcha,
+				entity.CheckpointTypePhoneRegister,
+			},
+		},
+		{
+			Name:            ProviderWhatsApp,
+			DisplayName:     "WhatsApp",
+			AuthMethods:     []AuthMethod{qrCode},
+			CheckpointTypes: []entity.CheckpointType{entity.CheckpointTypeQRCode},
+		},
+		{
+			Name:            "INSTAGRAM",
+			DisplayName:     "Instagram",
+			AuthMethods:     []AuthMethod{credentials},
+			CheckpointTypes: []entity.CheckpointType{entity.CheckpointType2FA, entity.CheckpointTypeOTP},
+		},
+		{
+			Name:            "TELEGRAM",
+			DisplayName:     "Telegram",
+			AuthMethods:     []AuthMethod{qrCode},
+			CheckpointTypes: []entity.CheckpointType{entity.CheckpointTypeQRCode, entity.CheckpointType2FA},
+		},
+		{
+			Name:            "MESSENGER",
+			DisplayName:     "Messenger",
+			AuthMethods:     []AuthMethod{credentials},
+			CheckpointTypes: []entity.CheckpointType{entity.CheckpointType2FA, entity.CheckpointTypeOTP},
+		},
+	}
+}
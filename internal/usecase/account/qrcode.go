@@ -0,0 +1,197 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// qrCodeRefreshInterval is how long a QR code is served before Unipile is asked for the rotated one
+const qrCodeRefreshInterval = 20 * time.Second
+
+// QRCode is the QR code of a pending QRCODE checkpoint
+type QRCode struct {
+	AccountID string    `json:"account_id"`
+	Code      string    `json:"code"` // Content to render as a QR code
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"` // Expiry of the checkpoint, not of this code
+}
+
+// qrCodeCheckpoint is the checkpoint metadata of a QRCODE checkpoint
+type qrCodeCheckpoint struct {
+	service.Checkpoint
+	// Last time Unipile was asked for a rotated code, nil until the code was first refreshed
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+}
+
+// refreshDue reports whether Unipile should be asked for a rotated code of a checkpoint issued at issuedAt
+func (c *qrCodeCheckpoint) refreshDue(issuedAt time.Time) bool {
+	if c.QRCode == "" {
+		return true
+	}
+	if c.RefreshedAt != nil {
+		issuedAt = *c.RefreshedAt
+	}
+	return time.Since(issuedAt) >= qrCodeRefreshInterval
+}
+
+// GetQRCode returns the current QR code of a pending QRCODE checkpoint.
+// Unipile rotates the code while it is not scanned, so Unipile is asked for the rotated code once the code was
+// served for qrCodeRefreshInterval, outside the account lock. A rotated code is recorded as a new history row
+// keeping the checkpoint expiry.
+func (a *UsecaseImpl) GetQRCode(ctx context.Context, userID uint, provider, accountID string) (*QRCode, error) {
+	qrCode, refresh, err := a.claimQRCodeRefresh(ctx, userID, provider, accountID)
+	if err != nil || refresh == nil {
+		return qrCode, err
+	}
+
+	resp, err := a.unipileClient.ResendCheckpoint(ctx, &service.ResendCheckpointRequest{
+		Provider:  refresh.provider,
+		AccountID: accountID,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileInvalidCodeOrExpiredCheckpoint) {
+			return nil, errs.ErrInvalidCodeOrExpiredCheckpoint
+		}
+		return nil, a.unipileError(err, "Failed to refresh QR code")
+	}
+	// Unipile keeps the code until it rotates it
+	if resp.Checkpoint == nil || resp.Checkpoint.QRCode == "" || resp.Checkpoint.QRCode == qrCode.Code {
+		if qrCode.Code == "" {
+			return nil, errs.ErrProviderUnavailable
+		}
+		return qrCode, nil
+	}
+
+	return a.recordRotatedQRCode(ctx, userID, provider, accountID, refresh.checkpointID, resp)
+}
+
+// qrCodeRefresh is a refresh of a QR code claimed by claimQRCodeRefresh
+type qrCodeRefresh struct {
+	provider     string
+	checkpointID uint // Status history row of the refreshed checkpoint
+}
+
+// claimQRCodeRefresh returns the current QR code and, when a refresh is due, claims it by recording the refresh
+// time, so concurrent polls keep being served the current code while Unipile is asked for the rotated one.
+func (a *UsecaseImpl) claimQRCodeRefresh(ctx context.Context, userID uint, provider, accountID string) (*QRCode, *qrCodeRefresh, error) {
+	var qrCode *QRCode
+	var refresh *qrCodeRefresh
+
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		account, err := lockProviderAccount(ctx, repos, userID, provider, accountID)
+		if err != nil {
+			return err
+		}
+		current, checkpoint, err := pendingQRCodeCheckpoint(account)
+		if err != nil {
+			return err
+		}
+		qrCode = &QRCode{
+			AccountID: accountID,
+			Code:      checkpoint.QRCode,
+			IssuedAt:  current.CreatedAt,
+			ExpiresAt: current.CheckpointExpiresAt,
+		}
+		if !checkpoint.refreshDue(current.CreatedAt) {
+			return nil
+		}
+
+		now := time.Now()
+		checkpoint.RefreshedAt = &now
+		if current.CheckpointMetadata, err = json.Marshal(checkpoint); err != nil {
+			return errs.WrapInternalError(err, "Failed to marshal checkpoint")
+		}
+		if err := repos.Account.UpdateCheckpointMetadata(ctx, current); err != nil {
+			return errs.WrapInternalError(err, "Failed to update checkpoint")
+		}
+		refresh = &qrCodeRefresh{provider: account.Provider, checkpointID: current.ID}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return qrCode, refresh, nil
+}
+
+// recordRotatedQRCode records the rotated QR code of the checkpoint as a new history row.
+// The code of a checkpoint that changed since the refresh was claimed is served as is.
+func (a *UsecaseImpl) recordRotatedQRCode(ctx context.Context, userID uint, provider, accountID string, checkpointID uint, resp *service.ResendCheckpointResponse) (*QRCode, error) {
+	var qrCode *QRCode
+
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		account, err := lockProviderAccount(ctx, repos, userID, provider, accountID)
+		if err != nil {
+			return err
+		}
+		current, checkpoint, err := pendingQRCodeCheckpoint(account)
+		if err != nil {
+			return err
+		}
+		if current.ID != checkpointID {
+			qrCode = &QRCode{
+				AccountID: accountID,
+				Code:      checkpoint.QRCode,
+				IssuedAt:  current.CreatedAt,
+				ExpiresAt: current.CheckpointExpiresAt,
+			}
+			return nil
+		}
+
+		checkpointMetadata, err := json.Marshal(resp.Checkpoint)
+		if err != nil {
+			return errs.WrapInternalError(err, "Failed to marshal checkpoint")
+		}
+		var payload json.RawMessage
+		if resp.RowBody != "" {
+			payload = json.RawMessage(resp.RowBody)
+		}
+
+		history := &entity.AccountStatusHistory{
+			Checkpoint:          entity.CheckpointTypeQRCode,
+			CheckpointMetadata:  checkpointMetadata,
+			CheckpointExpiresAt: current.CheckpointExpiresAt,
+			Status:              entity.AccountStatusPending,
+			Cause:               entity.StatusCauseQRCodeRefresh,
+			Payload:             payload,
+		}
+		if err := applyStatusHistory(ctx, repos, account, history); err != nil {
+			return err
+		}
+
+		qrCode = &QRCode{
+			AccountID: accountID,
+			Code:      resp.Checkpoint.QRCode,
+			IssuedAt:  time.Now(),
+			ExpiresAt: current.CheckpointExpiresAt,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return qrCode, nil
+}
+
+// pendingQRCodeCheckpoint returns the pending QRCODE checkpoint of the account with its metadata
+func pendingQRCodeCheckpoint(account *entity.Account) (*entity.AccountStatusHistory, *qrCodeCheckpoint, error) {
+	current := latestCheckpoint(account.AccountStatusHistories)
+	if account.CurrentStatus != entity.AccountStatusPending || current == nil || current.Checkpoint != entity.CheckpointTypeQRCode {
+		return nil, nil, errs.ErrNoPendingCheckpoint
+	}
+	if !current.CheckpointExpiresAt.After(time.Now()) {
+		return nil, nil, errs.ErrInvalidCodeOrExpiredCheckpoint
+	}
+
+	var checkpoint qrCodeCheckpoint
+	if err := json.Unmarshal(current.CheckpointMetadata, &checkpoint); err != nil {
+		return nil, nil, errs.WrapInternalError(err, "Failed to unmarshal checkpoint")
+	}
+	return current, &checkpoint, nil
+}
@@ -0,0 +1,146 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// reconcilePageSize is the number of Unipile accounts fetched per page during reconciliation
+const reconcilePageSize = 100
+
+// ReconcileAccounts syncs the status of local accounts with their Unipile accounts.
+// Local accounts missing upstream are marked DELETED; PENDING accounts are left to the checkpoint flow
+// and accounts in a terminal status are left untouched.
+func (a *UsecaseImpl) ReconcileAccounts(ctx context.Context) error {
+	// Load local accounts first, so accounts created during the Unipile walk are not flagged as missing
+	accounts, err := a.accountRepo.List(ctx)
+	if err != nil {
+		return errs.WrapInternalError(err, "Failed to list accounts")
+	}
+
+	upstreamAccounts := make(map[string]service.Account)
+	if err := service.ForEachAccount(ctx, a.unipileClient, reconcilePageSize, func(account *service.Account) error {
+		upstreamAccounts[account.ID] = *account
+		return nil
+	}); err != nil {
+		return a.unipileError(err, "Failed to list Unipile accounts")
+	}
+
+	var updated, skipped, failed int
+	for _, account := range accounts {
+		if account.CurrentStatus == entity.AccountStatusPending || account.CurrentStatus.IsTerminal() {
+			continue
+		}
+
+		// The Unipile account is kept as the history payload, nil when it is missing upstream
+		var payload any
+		status := entity.AccountStatusDeleted
+		if upstream, ok := upstreamAccounts[account.AccountID]; ok {
+			if status, ok = unipileAccountStatus(&upstream); !ok {
+				a.logger.WithField("accountID", account.AccountID).Debug("Skipping Unipile account without a known source status")
+				continue
+			}
+			payload = upstream
+		}
+		if status == account.CurrentStatus {
+			continue
+		}
+
+		reconciled, err := a.reconcileAccountStatus(ctx, account, status, payload)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, entity.ErrInvalidAccountStatusTransition) {
+				skipped++
+				a.logger.WithError(err).WithField("accountID", account.AccountID).Warn("Skipping account status rejected by the state machine")
+				continue
+			}
+			failed++
+			a.logger.WithError(err).WithFields(logrus.Fields{
+				"accountID": account.AccountID,
+				"status":    status,
+			}).Error("Failed to reconcile account status")
+			continue
+		}
+		if reconciled != nil {
+			a.publishAccountStatus(reconciled)
+			updated++
+		}
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"local":    len(accounts),
+		"upstream": len(upstreamAccounts),
+		"updated":  updated,
+		"skipped":  skipped,
+		"failed":   failed,
+	}).Info("Account reconciliation completed")
+
+	return nil
+}
+
+// reconcileAccountStatus updates the account status and appends a status history row.
+// It returns the updated account, or nil when the account no longer needs an update.
+func (a *UsecaseImpl) reconcileAccountStatus(ctx context.Context, account *entity.Account, status entity.AccountStatus, payload any) (*entity.Account, error) {
+	var reconciled *entity.Account
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		locked, err := repos.Account.GetByUserIDAndAccountIDForUpdate(ctx, account.UserID, account.AccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return nil
+			}
+			return err
+		}
+		// Status may have changed since the accounts were listed
+		if locked.CurrentStatus == status || locked.CurrentStatus == entity.AccountStatusPending {
+			return nil
+		}
+
+		if err := setAccountStatus(ctx, repos, locked, status, entity.StatusCauseReconciliation, payload); err != nil {
+			return err
+		}
+		reconciled = locked
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return reconciled, nil
+}
+
+// sourceStatuses maps Unipile account source statuses to account statuses
+var sourceStatuses = map[string]entity.AccountStatus{
+	"OK":          entity.AccountStatusOK,
+	"CONNECTING":  entity.AccountStatusConnecting,
+	"CREDENTIALS": entity.AccountStatusCredentials,
+	"PERMISSIONS": entity.AccountStatusError,
+	"ERROR":       entity.AccountStatusError,
+	"STOPPED":     entity.AccountStatusStopped,
+}
+
+// unipileAccountStatus derives a single status from the account sources:
+// OK when every source is OK, otherwise the status of the first source that is not OK.
+// It reports false when the account has no sources or a source status is unknown, so the account is left as is.
+func unipileAccountStatus(account *service.Account) (entity.AccountStatus, bool) {
+	if len(account.Sources) == 0 {
+		return "", false
+	}
+	status := entity.AccountStatusOK
+	for _, source := range account.Sources {
+		sourceStatus, ok := sourceStatuses[source.Status]
+		if !ok {
+			return "", false
+		}
+		if status == entity.AccountStatusOK {
+			status = sourceStatus
+		}
+	}
+	return status, true
+}
@@ -0,0 +1,55 @@
+package account
+
+import (
+	"context"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// reconnectableStatuses are the statuses of accounts Unipile lost the session of
+var reconnectableStatuses = map[entity.AccountStatus]bool{
+	entity.AccountStatusCredentials: true,
+	entity.AccountStatusError:       true,
+	entity.AccountStatusStopped:     true,
+}
+
+// ReconnectAccount reconnects a disconnected account with new credentials.
+// The existing account row is reused and a checkpoint, if any, is handled like on the first connect.
+func (a *UsecaseImpl) ReconnectAccount(ctx context.Context, userID uint, accountID string, req *ConnectAccountRequest) (*entity.Account, error) {
+	var account *entity.Account
+
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		var err error
+
+		account, err = lockProviderAccount(ctx, repos, userID, req.Provider, accountID)
+		if err != nil {
+			return err
+		}
+		if !reconnectableStatuses[account.CurrentStatus] {
+			return errs.ErrAccountNotReconnectable
+		}
+
+		resp, err := a.unipileClient.ReconnectAccount(ctx, accountID, req.unipileRequest(account.Provider))
+		if err != nil {
+			return a.unipileError(err, "Failed to reconnect account on Unipile")
+		}
+
+		history, err := a.checkpointStatusHistory(account.Provider, resp.Checkpoint, entity.StatusCauseReconnect, resp.RowBody)
+		if err != nil {
+			return err
+		}
+		if err := applyStatusHistory(ctx, repos, account, history); err != nil {
+			return err
+		}
+		// Return the new checkpoint with the account, like on the first connect
+		account.AccountStatusHistories = append(account.AccountStatusHistories, *history)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	a.publishAccountStatus(account)
+
+	return account, nil
+}
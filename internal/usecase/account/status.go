@@ -0,0 +1,60 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// setAccountStatus moves the account to status and appends a status history row recording the transition.
+// payload is the Unipile payload behind the transition and may be nil.
+// Transitions rejected by the account state machine return a business error wrapping entity.ErrInvalidAccountStatusTransition.
+func setAccountStatus(ctx context.Context, repos *repository.Repositories, account *entity.Account, status entity.AccountStatus, cause string, payload any) error {
+	history, err := newStatusHistory(status, cause, payload)
+	if err != nil {
+		return errs.WrapInternalError(err, "Failed to marshal status history")
+	}
+	return applyStatusHistory(ctx, repos, account, history)
+}
+
+// applyStatusHistory moves the account to the history status and appends the history row
+func applyStatusHistory(ctx context.Context, repos *repository.Repositories, account *entity.Account, history *entity.AccountStatusHistory) error {
+	history.PreviousStatus = account.CurrentStatus
+	if err := account.TransitionTo(history.Status); err != nil {
+		return errs.WrapBusinessError(err, "Invalid account status transition")
+	}
+	if err := repos.Account.Update(ctx, account); err != nil {
+		return errs.WrapInternalError(err, "Failed to update account status")
+	}
+
+	history.AccountID = account.ID
+	if err := repos.Account.CreateStatusHistory(ctx, history); err != nil {
+		return errs.WrapInternalError(err, "Failed to create status history")
+	}
+	return nil
+}
+
+// newStatusHistory builds a status history row for a transition to status
+func newStatusHistory(status entity.AccountStatus, cause string, payload any) (*entity.AccountStatusHistory, error) {
+	history := &entity.AccountStatusHistory{
+		Status: status,
+		Cause:  cause,
+	}
+
+	switch p := payload.(type) {
+	case nil:
+	case json.RawMessage:
+		history.Payload = p
+	default:
+		body, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		history.Payload = body
+	}
+
+	return history, nil
+}
@@ -0,0 +1,81 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// AccountStatusEvent represents an account status event delivered by a Unipile webhook
+type AccountStatusEvent struct {
+	AccountID   string `json:"account_id"`
+	AccountType string `json:"account_type"`
+	Message     string `json:"message"` // OK, CREDENTIALS, ERROR, STOPPED, DELETED, CREATION_SUCCESS, RECONNECTED, SYNC_SUCCESS
+}
+
+// webhookStatuses maps Unipile account status messages to account statuses
+var webhookStatuses = map[string]entity.AccountStatus{
+	"OK":               entity.AccountStatusOK,
+	"CREATION_SUCCESS": entity.AccountStatusOK,
+	"RECONNECTED":      entity.AccountStatusOK,
+	"SYNC_SUCCESS":     entity.AccountStatusOK,
+	"CREDENTIALS":      entity.AccountStatusCredentials,
+	"ERROR":            entity.AccountStatusError,
+	"STOPPED":          entity.AccountStatusStopped,
+	"DELETED":          entity.AccountStatusDeleted,
+}
+
+// HandleAccountStatusEvent applies an account status event to the matching account.
+// Events that do not change the status are ignored, so duplicate deliveries do not add history rows.
+// It returns the updated account, or nil when the event was ignored.
+func (a *UsecaseImpl) HandleAccountStatusEvent(ctx context.Context, event *AccountStatusEvent) (*entity.Account, error) {
+	logFields := logrus.Fields{
+		"accountID": event.AccountID,
+		"message":   event.Message,
+	}
+
+	status, ok := webhookStatuses[event.Message]
+	if !ok {
+		a.logger.WithFields(logFields).Info("Ignoring unsupported account status event")
+		return nil, nil
+	}
+
+	var account *entity.Account
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		locked, err := repos.Account.GetByAccountIDForUpdate(ctx, event.AccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				a.logger.WithFields(logFields).Info("Ignoring account status event for unknown account")
+				return nil
+			}
+			return errs.WrapInternalError(err, "Failed to get account")
+		}
+		if locked.CurrentStatus == status {
+			return nil
+		}
+
+		if err := setAccountStatus(ctx, repos, locked, status, entity.StatusCauseWebhook, event); err != nil {
+			// Unipile retries failed deliveries, so acknowledge events the state machine rejects
+			if errors.Is(err, entity.ErrInvalidAccountStatusTransition) {
+				a.logger.WithError(err).WithFields(logFields).Warn("Ignoring account status event rejected by the state machine")
+				return nil
+			}
+			return err
+		}
+		account = locked
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if account != nil {
+		a.logger.WithFields(logFields).WithField("status", status).Info("Account status updated from webhook")
+		a.publishAccountStatus(account)
+	}
+	return account, nil
+}
@@ -0,0 +1,36 @@
+package unipileerr
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/service"
+)
+
+// Map maps a Unipile client error to the coded error returned to API clients, logging the Unipile error details.
+// Errors without a provider meaning become internal errors with msg.
+func Map(logger *logrus.Logger, err error, msg string) error {
+	var apiErr *service.UnipileError
+	if errors.As(err, &apiErr) {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"status":    apiErr.Status,
+			"type":      apiErr.Type,
+			"requestID": apiErr.RequestID,
+		}).Warn(msg)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUnipileDisconnectedAccount), errors.Is(err, service.ErrUnipileInvalidCredentials):
+		return errs.ErrProviderAccountDisconnected
+	case errors.Is(err, service.ErrUnipileInsufficientPermissions):
+		return errs.ErrProviderPermissionDenied
+	case errors.Is(err, service.ErrUnipileRateLimited):
+		return errs.ErrProviderRateLimited
+	case errors.Is(err, service.ErrUnipileUnavailable), errors.Is(err, service.ErrUnipileMissingQRCode):
+		return errs.ErrProviderUnavailable
+	default:
+		return errs.WrapInternalError(err, msg)
+	}
+}
@@ -0,0 +1,37 @@
+package unipileerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/service"
+)
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{err: service.ErrUnipileDisconnectedAccount, want: errs.ErrProviderAccountDisconnected},
+		{err: fmt.Errorf("wrapped: %w", service.ErrUnipileInvalidCredentials), want: errs.ErrProviderAccountDisconnected},
+		{err: service.ErrUnipileInsufficientPermissions, want: errs.ErrProviderPermissionDenied},
+		{err: service.ErrUnipileRateLimited, want: errs.ErrProviderRateLimited},
+		{err: service.ErrUnipileUnavailable, want: errs.ErrProviderUnavailable},
+		{err: service.ErrUnipileMissingQRCode, want: errs.ErrProviderUnavailable},
+	}
+	for _, tt := range tests {
+		if got := Map(logrus.New(), tt.err, "Failed"); !errors.Is(got, tt.want) {
+			t.Fatalf("expected %v for %v, got %v", tt.want, tt.err, got)
+		}
+	}
+
+	got := Map(logrus.New(), errors.New("boom"), "Failed to list chats")
+	var codedErr *errs.CodedError
+	if !errors.As(got, &codedErr) || codedErr.Kind != errs.SystemErrorKind || codedErr.Message != "Failed to list chats" {
+		t.Fatalf("expected internal error, got %v", got)
+	}
+}
@@ -0,0 +1,350 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// providerLinkedIn is the only provider with connection invitations
+const providerLinkedIn = "LINKEDIN"
+
+// maxPageSize is the largest page Unipile serves
+const maxPageSize = 250
+
+// maxMessageLength is the longest note LinkedIn accepts with an invitation
+const maxMessageLength = 300
+
+// RelationEventNew is the Unipile users webhook event reporting a new relation of an account,
+// sent once a user accepted an invitation of the account
+const RelationEventNew = "new_relation"
+
+// Usecase handles LinkedIn connection invitations
+type Usecase interface {
+	SendInvitation(ctx context.Context, userID uint, accountID string, req *SendInvitationRequest) (*entity.Invitation, error)
+	ListSentInvitations(ctx context.Context, userID uint, accountID string, req *PageRequest) (*InvitationPage, error)
+	ListReceivedInvitations(ctx context.Context, userID uint, accountID string, req *PageRequest) (*InvitationPage, error)
+	WithdrawInvitation(ctx context.Context, userID uint, accountID, invitationID string) (*entity.Invitation, error)
+	HandleRelationEvent(ctx context.Context, event *RelationEvent) (int64, error)
+}
+
+// UsecaseImpl handles LinkedIn connection invitations
+type UsecaseImpl struct {
+	txRepo         repository.TxRepository
+	accountRepo    repository.AccountRepository
+	invitationRepo repository.InvitationRepository
+	unipileClient  service.UnipileClient
+	logger         *logrus.Logger
+}
+
+// NewInvitationUsecase creates a new invitation usecase
+func NewInvitationUsecase(txRepo repository.TxRepository, accountRepo repository.AccountRepository, invitationRepo repository.InvitationRepository, unipileClient service.UnipileClient, logger *logrus.Logger) Usecase {
+	return &UsecaseImpl{
+		txRepo:         txRepo,
+		accountRepo:    accountRepo,
+		invitationRepo: invitationRepo,
+		unipileClient:  unipileClient,
+		logger:         logger,
+	}
+}
+
+// SendInvitationRequest represents the request to invite a LinkedIn user to connect
+type SendInvitationRequest struct {
+	ProviderID string // Provider ID of the invited user, e.g. from a profile lookup
+	Message    string // Note sent with the invitation, optional
+}
+
+// PageRequest represents the pagination parameters of a list request
+type PageRequest struct {
+	Limit  int    // Page size (1-250), 0 uses the Unipile default
+	Cursor string // Cursor returned by the previous page, empty for the first page
+}
+
+// InvitationPage is a page of pending invitations, Cursor is empty on the last page
+type InvitationPage struct {
+	Invitations []*entity.Invitation `json:"invitations"`
+	Cursor      string               `json:"cursor"`
+}
+
+// RelationEvent represents a new relation reported by a Unipile users webhook
+type RelationEvent struct {
+	AccountID            string
+	UserProviderID       string
+	UserPublicIdentifier string
+	UserFullName         string
+}
+
+// SendInvitation invites a LinkedIn user to connect with the account and tracks the invitation as pending
+func (a *UsecaseImpl) SendInvitation(ctx context.Context, userID uint, accountID string, req *SendInvitationRequest) (*entity.Invitation, error) {
+	providerID := strings.TrimSpace(req.ProviderID)
+	if providerID == "" {
+		return nil, errs.WrapValidationError(errors.New("provider_id is required"), "Invalid invitation")
+	}
+	if utf8.RuneCountInString(req.Message) > maxMessageLength {
+		return nil, errs.WrapValidationError(fmt.Errorf("message must be at most %d characters", maxMessageLength), "Invalid invitation")
+	}
+
+	account, err := a.linkedInAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.unipileClient.SendInvitation(ctx, &service.SendInvitationRequest{
+		AccountID:  account.AccountID,
+		ProviderID: providerID,
+		Message:    req.Message,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileUserNotFound) {
+			return nil, errs.ErrProfileNotFound
+		}
+		return nil, unipileerr.Map(a.logger, err, "Failed to send invitation")
+	}
+	if resp.InvitationID == "" {
+		return nil, errs.WrapInternalError(errors.New("missing invitation id"), "Failed to send invitation")
+	}
+
+	invitation := &entity.Invitation{
+		AccountID:    account.ID,
+		InvitationID: resp.InvitationID,
+		Direction:    entity.InvitationDirectionSent,
+		Status:       entity.InvitationStatusPending,
+		ProviderID:   providerID,
+		Message:      req.Message,
+		SentAt:       time.Now().UTC(),
+	}
+	if err := a.invitationRepo.Upsert(ctx, invitation); err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to save invitation")
+	}
+	return invitation, nil
+}
+
+// ListSentInvitations lists one page of the pending invitations sent by the account, tracking them locally.
+// When the page is the whole listing, the tracked pending invitations it misses are closed.
+func (a *UsecaseImpl) ListSentInvitations(ctx context.Context, userID uint, accountID string, req *PageRequest) (*InvitationPage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	account, err := a.linkedInAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listedAt := time.Now()
+	resp, err := a.unipileClient.ListSentInvitations(ctx, &service.ListInvitationsRequest{AccountID: account.AccountID, Limit: req.Limit, Cursor: req.Cursor})
+	if err != nil {
+		return nil, unipileerr.Map(a.logger, err, "Failed to list sent invitations")
+	}
+
+	page := &InvitationPage{Invitations: make([]*entity.Invitation, 0, len(resp.Items)), Cursor: nextCursor(resp.Cursor, req.Cursor)}
+	for _, item := range resp.Items {
+		invitation := &entity.Invitation{
+			AccountID:        account.ID,
+			InvitationID:     item.ID,
+			Direction:        entity.InvitationDirectionSent,
+			Status:           entity.InvitationStatusPending,
+			ProviderID:       item.InvitedUserID,
+			PublicIdentifier: item.InvitedUserPublicID,
+			Name:             item.InvitedUser,
+			Headline:         item.InvitedUserDescription,
+			Message:          item.InvitationText,
+			SentAt:           parseTimestamp(item.ParsedDatetime),
+		}
+		if err := a.invitationRepo.Upsert(ctx, invitation); err != nil {
+			return nil, errs.WrapInternalError(err, "Failed to save invitation")
+		}
+		page.Invitations = append(page.Invitations, invitation)
+	}
+	if err := a.closeMissing(ctx, account, entity.InvitationDirectionSent, req, page, listedAt); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListReceivedInvitations lists one page of the pending invitations received by the account, tracking them locally.
+// When the page is the whole listing, the tracked pending invitations it misses are closed.
+func (a *UsecaseImpl) ListReceivedInvitations(ctx context.Context, userID uint, accountID string, req *PageRequest) (*InvitationPage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	account, err := a.linkedInAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listedAt := time.Now()
+	resp, err := a.unipileClient.ListReceivedInvitations(ctx, &service.ListInvitationsRequest{AccountID: account.AccountID, Limit: req.Limit, Cursor: req.Cursor})
+	if err != nil {
+		return nil, unipileerr.Map(a.logger, err, "Failed to list received invitations")
+	}
+
+	page := &InvitationPage{Invitations: make([]*entity.Invitation, 0, len(resp.Items)), Cursor: nextCursor(resp.Cursor, req.Cursor)}
+	for _, item := range resp.Items {
+		invitation := &entity.Invitation{
+			AccountID:        account.ID,
+			InvitationID:     item.ID,
+			Direction:        entity.InvitationDirectionReceived,
+			Status:           entity.InvitationStatusPending,
+			ProviderID:       item.Inviter.InviterID,
+			PublicIdentifier: item.Inviter.InviterPublicIdentifier,
+			Name:             item.Inviter.InviterName,
+			Headline:         item.Inviter.InviterDescription,
+			Message:          item.InvitationText,
+			SentAt:           parseTimestamp(item.ParsedDatetime),
+		}
+		if err := a.invitationRepo.Upsert(ctx, invitation); err != nil {
+			return nil, errs.WrapInternalError(err, "Failed to save invitation")
+		}
+		page.Invitations = append(page.Invitations, invitation)
+	}
+	if err := a.closeMissing(ctx, account, entity.InvitationDirectionReceived, req, page, listedAt); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// WithdrawInvitation withdraws a pending invitation sent by the account.
+// Only invitations tracked locally, sent or listed through the connector, can be withdrawn.
+func (a *UsecaseImpl) WithdrawInvitation(ctx context.Context, userID uint, accountID, invitationID string) (*entity.Invitation, error) {
+	account, err := a.linkedInAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := a.invitationRepo.GetByAccountIDAndInvitationID(ctx, account.ID, invitationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return nil, errs.ErrInvitationNotFound
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get invitation")
+	}
+	if invitation.Direction != entity.InvitationDirectionSent || !invitation.IsPending() {
+		return nil, errs.ErrInvitationNotPending
+	}
+
+	if err := a.unipileClient.WithdrawInvitation(ctx, account.AccountID, invitation.InvitationID); err != nil {
+		if errors.Is(err, service.ErrUnipileInvitationNotFound) {
+			return nil, errs.ErrInvitationNotFound
+		}
+		return nil, unipileerr.Map(a.logger, err, "Failed to withdraw invitation")
+	}
+
+	invitation.Withdraw(time.Now().UTC())
+	if err := a.invitationRepo.Update(ctx, invitation); err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to update invitation")
+	}
+	return invitation, nil
+}
+
+// HandleRelationEvent marks the pending invitations the account sent to or received from the new relation as accepted.
+// Events of accounts that are not stored are ignored. It returns the number of accepted invitations.
+func (a *UsecaseImpl) HandleRelationEvent(ctx context.Context, event *RelationEvent) (int64, error) {
+	logFields := logrus.Fields{
+		"accountID":      event.AccountID,
+		"userProviderID": event.UserProviderID,
+	}
+
+	var accepted int64
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		account, err := repos.Account.GetByAccountIDForUpdate(ctx, event.AccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				a.logger.WithFields(logFields).Info("Ignoring relation event for unknown account")
+				return nil
+			}
+			return errs.WrapInternalError(err, "Failed to get account")
+		}
+
+		accepted, err = repos.Invitation.AcceptPending(ctx, account.ID, event.UserProviderID, time.Now().UTC())
+		if err != nil {
+			return errs.WrapInternalError(err, "Failed to accept invitations")
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	if accepted > 0 {
+		a.logger.WithFields(logFields).WithField("accepted", accepted).Info("Invitations accepted")
+	}
+	return accepted, nil
+}
+
+// closeMissing closes the pending invitations of the account in the direction that Unipile no longer lists,
+// once a listing fits in a single page. Invitations tracked after listedAt may not be listed yet and are kept.
+func (a *UsecaseImpl) closeMissing(ctx context.Context, account *entity.Account, direction entity.InvitationDirection, req *PageRequest, page *InvitationPage, listedAt time.Time) error {
+	if req.Cursor != "" || page.Cursor != "" {
+		return nil
+	}
+
+	invitationIDs := make([]string, 0, len(page.Invitations))
+	for _, invitation := range page.Invitations {
+		invitationIDs = append(invitationIDs, invitation.InvitationID)
+	}
+	closed, err := a.invitationRepo.ClosePendingExcept(ctx, account.ID, direction, invitationIDs, listedAt)
+	if err != nil {
+		return errs.WrapInternalError(err, "Failed to close invitations")
+	}
+	if closed > 0 {
+		a.logger.WithFields(logrus.Fields{
+			"accountID": account.AccountID,
+			"direction": direction,
+			"closed":    closed,
+		}).Info("Invitations no longer listed closed")
+	}
+	return nil
+}
+
+// linkedInAccount returns the connected LinkedIn account of the user
+func (a *UsecaseImpl) linkedInAccount(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	account, err := a.accountRepo.GetByUserIDAndAccountID(ctx, userID, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, errs.ErrAccountNotFound
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get account")
+	}
+	if !strings.EqualFold(account.Provider, providerLinkedIn) {
+		return nil, errs.ErrUnsupportedProvider
+	}
+	if account.CurrentStatus != entity.AccountStatusOK {
+		return nil, errs.ErrAccountNotConnected
+	}
+	return account, nil
+}
+
+// validate checks the page size is one Unipile serves
+func (r *PageRequest) validate() error {
+	if r.Limit < 0 || r.Limit > maxPageSize {
+		return errs.WrapValidationError(fmt.Errorf("limit must be between 1 and %d", maxPageSize), "Invalid page size")
+	}
+	return nil
+}
+
+// nextCursor returns the cursor of the next page, empty when the page is the last one
+func nextCursor(cursor *string, current string) string {
+	if cursor == nil || *cursor == current {
+		return ""
+	}
+	return *cursor
+}
+
+// parseTimestamp parses a Unipile date, returning the zero time when it is missing or malformed
+func parseTimestamp(timestamp string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed.UTC()
+}
@@ -0,0 +1,374 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// mockAccountRepo implements the account lookups of AccountRepository, other methods panic
+type mockAccountRepo struct {
+	repository.AccountRepository
+	account *entity.Account // Account of user 1, nil when the user has none
+}
+
+func (m *mockAccountRepo) GetByUserIDAndAccountID(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+	if m.account == nil || userID != m.account.UserID || accountID != m.account.AccountID {
+		return nil, repository.ErrAccountNotFound
+	}
+	return m.account, nil
+}
+
+func (m *mockAccountRepo) GetByAccountIDForUpdate(_ context.Context, accountID string) (*entity.Account, error) {
+	if m.account == nil || accountID != m.account.AccountID {
+		return nil, repository.ErrAccountNotFound
+	}
+	return m.account, nil
+}
+
+// mockInvitationRepo implements InvitationRepository in memory, keeping the status and sent time of stored invitations like Postgres
+type mockInvitationRepo struct {
+	invitations map[string]*entity.Invitation
+}
+
+func (m *mockInvitationRepo) Upsert(_ context.Context, invitation *entity.Invitation) error {
+	if m.invitations == nil {
+		m.invitations = make(map[string]*entity.Invitation)
+	}
+	if stored, ok := m.invitations[invitation.InvitationID]; ok {
+		invitation.Status = stored.Status
+		if invitation.SentAt.IsZero() {
+			invitation.SentAt = stored.SentAt
+		}
+	}
+	stored := *invitation
+	m.invitations[invitation.InvitationID] = &stored
+	return nil
+}
+
+func (m *mockInvitationRepo) GetByAccountIDAndInvitationID(_ context.Context, _ uint, invitationID string) (*entity.Invitation, error) {
+	stored, ok := m.invitations[invitationID]
+	if !ok {
+		return nil, repository.ErrInvitationNotFound
+	}
+	invitation := *stored
+	return &invitation, nil
+}
+
+func (m *mockInvitationRepo) Update(_ context.Context, invitation *entity.Invitation) error {
+	stored := *invitation
+	m.invitations[invitation.InvitationID] = &stored
+	return nil
+}
+
+func (m *mockInvitationRepo) AcceptPending(_ context.Context, _ uint, providerID string, acceptedAt time.Time) (int64, error) {
+	var accepted int64
+	for _, invitation := range m.invitations {
+		if invitation.ProviderID == providerID && invitation.IsPending() {
+			invitation.Status = entity.InvitationStatusAccepted
+			invitation.AcceptedAt = &acceptedAt
+			accepted++
+		}
+	}
+	return accepted, nil
+}
+
+func (m *mockInvitationRepo) ClosePendingExcept(_ context.Context, _ uint, direction entity.InvitationDirection, invitationIDs []string, listedAt time.Time) (int64, error) {
+	listed := make(map[string]bool, len(invitationIDs))
+	for _, id := range invitationIDs {
+		listed[id] = true
+	}
+	var closed int64
+	for id, invitation := range m.invitations {
+		if invitation.Direction == direction && invitation.IsPending() && !listed[id] {
+			invitation.Status = entity.InvitationStatusClosed
+			invitation.ClosedAt = &listedAt
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// mockUnipileClient implements the invitation calls of the Unipile client, other methods panic
+type mockUnipileClient struct {
+	service.UnipileClient
+	sendInvitationFunc          func(ctx context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error)
+	listSentInvitationsFunc     func(ctx context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error)
+	listReceivedInvitationsFunc func(ctx context.Context, req *service.ListInvitationsRequest) (*service.ReceivedInvitationListResponse, error)
+	withdrawInvitationFunc      func(ctx context.Context, accountID, invitationID string) error
+}
+
+func (m *mockUnipileClient) SendInvitation(ctx context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error) {
+	return m.sendInvitationFunc(ctx, req)
+}
+
+func (m *mockUnipileClient) ListSentInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error) {
+	return m.listSentInvitationsFunc(ctx, req)
+}
+
+func (m *mockUnipileClient) ListReceivedInvitations(ctx context.Context, req *service.ListInvitationsRequest) (*service.ReceivedInvitationListResponse, error) {
+	return m.listReceivedInvitationsFunc(ctx, req)
+}
+
+func (m *mockUnipileClient) WithdrawInvitation(ctx context.Context, accountID, invitationID string) error {
+	return m.withdrawInvitationFunc(ctx, accountID, invitationID)
+}
+
+// mockTxRepo runs transactions with the given repositories
+type mockTxRepo struct {
+	repos repository.Repositories
+}
+
+func (m *mockTxRepo) Do(ctx context.Context, fn func(*repository.Repositories) error) error {
+	return fn(&m.repos)
+}
+
+func linkedInAccount() *mockAccountRepo {
+	return &mockAccountRepo{account: &entity.Account{ID: 7, UserID: 1, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusOK}}
+}
+
+// newTestUsecase creates an invitation usecase whose transactions run with the given repositories
+func newTestUsecase(accountRepo *mockAccountRepo, invitationRepo *mockInvitationRepo, unipileClient *mockUnipileClient) Usecase {
+	txRepo := &mockTxRepo{repos: repository.Repositories{Account: accountRepo, Invitation: invitationRepo}}
+	return NewInvitationUsecase(txRepo, accountRepo, invitationRepo, unipileClient, logrus.New())
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestSendInvitation(t *testing.T) {
+	var received *service.SendInvitationRequest
+	client := &mockUnipileClient{sendInvitationFunc: func(_ context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error) {
+		received = req
+		return &service.SendInvitationResponse{InvitationID: "inv-1"}, nil
+	}}
+	invitationRepo := &mockInvitationRepo{}
+	uc := newTestUsecase(linkedInAccount(), invitationRepo, client)
+
+	invitation, err := uc.SendInvitation(context.Background(), 1, "acc-1", &SendInvitationRequest{ProviderID: " ACoAA ", Message: "Hi Jane"})
+	if err != nil {
+		t.Fatalf("SendInvitation returned error: %v", err)
+	}
+	if received.AccountID != "acc-1" || received.ProviderID != "ACoAA" || received.Message != "Hi Jane" {
+		t.Fatalf("unexpected Unipile request: %+v", received)
+	}
+	if invitation.InvitationID != "inv-1" || invitation.AccountID != 7 || invitation.Direction != entity.InvitationDirectionSent ||
+		invitation.Status != entity.InvitationStatusPending || invitation.ProviderID != "ACoAA" || invitation.SentAt.IsZero() {
+		t.Fatalf("unexpected invitation: %+v", invitation)
+	}
+	if _, ok := invitationRepo.invitations["inv-1"]; !ok {
+		t.Fatal("expected the invitation to be stored")
+	}
+}
+
+func TestListSentInvitations_ClosesMissingInvitations(t *testing.T) {
+	sentAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	cursor := stringPtr("page-2")
+	client := &mockUnipileClient{listSentInvitationsFunc: func(_ context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error) {
+		return &service.SentInvitationListResponse{
+			// Unipile does not always report the sent time
+			Items:  []service.SentInvitation{{ID: "inv-1", InvitedUser: "Jane Doe", InvitedUserID: "ACoAA"}},
+			Cursor: cursor,
+		}, nil
+	}}
+	invitationRepo := &mockInvitationRepo{invitations: map[string]*entity.Invitation{
+		"inv-1":    {AccountID: 7, InvitationID: "inv-1", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, SentAt: sentAt},
+		"inv-2":    {AccountID: 7, InvitationID: "inv-2", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending},
+		"received": {AccountID: 7, InvitationID: "received", Direction: entity.InvitationDirectionReceived, Status: entity.InvitationStatusPending},
+	}}
+	uc := newTestUsecase(linkedInAccount(), invitationRepo, client)
+
+	// A page of a longer listing does not tell which invitations are gone
+	page, err := uc.ListSentInvitations(context.Background(), 1, "acc-1", &PageRequest{})
+	if err != nil {
+		t.Fatalf("ListSentInvitations returned error: %v", err)
+	}
+	if !page.Invitations[0].SentAt.Equal(sentAt) || !invitationRepo.invitations["inv-2"].IsPending() {
+		t.Fatalf("unexpected invitations: %+v", invitationRepo.invitations)
+	}
+
+	cursor = nil
+	if _, err := uc.ListSentInvitations(context.Background(), 1, "acc-1", &PageRequest{}); err != nil {
+		t.Fatalf("ListSentInvitations returned error: %v", err)
+	}
+	if !invitationRepo.invitations["inv-1"].IsPending() || invitationRepo.invitations["inv-2"].Status != entity.InvitationStatusClosed ||
+		invitationRepo.invitations["inv-2"].ClosedAt == nil || !invitationRepo.invitations["received"].IsPending() {
+		t.Fatalf("unexpected invitations: %+v", invitationRepo.invitations)
+	}
+}
+
+func TestSendInvitation_Errors(t *testing.T) {
+	whatsApp := linkedInAccount()
+	whatsApp.account.Provider = "WHATSAPP"
+	disconnected := linkedInAccount()
+	disconnected.account.CurrentStatus = entity.AccountStatusCredentials
+
+	tests := []struct {
+		name        string
+		accountRepo *mockAccountRepo
+		req         *SendInvitationRequest
+		clientErr   error
+		wantErr     error
+		wantKind    errs.Kind
+	}{
+		{name: "missing provider id", accountRepo: linkedInAccount(), req: &SendInvitationRequest{}, wantKind: errs.ValidationErrorKind},
+		{name: "message too long", accountRepo: linkedInAccount(), req: &SendInvitationRequest{ProviderID: "ACoAA", Message: strings.Repeat("é", maxMessageLength+1)}, wantKind: errs.ValidationErrorKind},
+		{name: "not a LinkedIn account", accountRepo: whatsApp, req: &SendInvitationRequest{ProviderID: "ACoAA"}, wantErr: errs.ErrUnsupportedProvider},
+		{name: "account not connected", accountRepo: disconnected, req: &SendInvitationRequest{ProviderID: "ACoAA"}, wantErr: errs.ErrAccountNotConnected},
+		{name: "rate limited", accountRepo: linkedInAccount(), req: &SendInvitationRequest{ProviderID: "ACoAA"}, clientErr: &service.UnipileError{Status: 429}, wantErr: errs.ErrProviderRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockUnipileClient{sendInvitationFunc: func(_ context.Context, req *service.SendInvitationRequest) (*service.SendInvitationResponse, error) {
+				if tt.clientErr == nil {
+					t.Fatal("expected Unipile not to be called")
+				}
+				return nil, tt.clientErr
+			}}
+			uc := newTestUsecase(tt.accountRepo, &mockInvitationRepo{}, client)
+
+			_, err := uc.SendInvitation(context.Background(), 1, "acc-1", tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			var codedErr *errs.CodedError
+			if !errors.As(err, &codedErr) || codedErr.Kind != tt.wantKind {
+				t.Fatalf("expected %v error, got %v", tt.wantKind, err)
+			}
+		})
+	}
+}
+
+func TestListSentInvitations(t *testing.T) {
+	var received *service.ListInvitationsRequest
+	client := &mockUnipileClient{listSentInvitationsFunc: func(_ context.Context, req *service.ListInvitationsRequest) (*service.SentInvitationListResponse, error) {
+		received = req
+		return &service.SentInvitationListResponse{
+			Items: []service.SentInvitation{
+				{ID: "inv-1", InvitedUser: "Jane Doe", InvitedUserID: "ACoAA", InvitedUserPublicID: "jane-doe", InvitationText: "Hi Jane", ParsedDatetime: "2025-01-01T10:00:00.000Z"},
+			},
+			Cursor: stringPtr("page-2"),
+		}, nil
+	}}
+	invitationRepo := &mockInvitationRepo{}
+	uc := newTestUsecase(linkedInAccount(), invitationRepo, client)
+
+	page, err := uc.ListSentInvitations(context.Background(), 1, "acc-1", &PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListSentInvitations returned error: %v", err)
+	}
+	if received.AccountID != "acc-1" || received.Limit != 10 {
+		t.Fatalf("unexpected Unipile request: %+v", received)
+	}
+	if page.Cursor != "page-2" || len(page.Invitations) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	invitation := page.Invitations[0]
+	if invitation.Direction != entity.InvitationDirectionSent || invitation.Status != entity.InvitationStatusPending || invitation.ProviderID != "ACoAA" ||
+		invitation.Name != "Jane Doe" || !invitation.SentAt.Equal(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected invitation: %+v", invitation)
+	}
+	if _, ok := invitationRepo.invitations["inv-1"]; !ok {
+		t.Fatal("expected the invitation to be stored")
+	}
+}
+
+func TestListReceivedInvitations(t *testing.T) {
+	client := &mockUnipileClient{listReceivedInvitationsFunc: func(_ context.Context, req *service.ListInvitationsRequest) (*service.ReceivedInvitationListResponse, error) {
+		return &service.ReceivedInvitationListResponse{
+			Items: []service.ReceivedInvitation{
+				{ID: "inv-2", InvitationText: "Let's connect", Inviter: service.Inviter{InviterID: "ACoBB", InviterName: "John Smith", InviterPublicIdentifier: "john-smith"}},
+			},
+		}, nil
+	}}
+	uc := newTestUsecase(linkedInAccount(), &mockInvitationRepo{}, client)
+
+	page, err := uc.ListReceivedInvitations(context.Background(), 1, "acc-1", &PageRequest{})
+	if err != nil {
+		t.Fatalf("ListReceivedInvitations returned error: %v", err)
+	}
+	if page.Cursor != "" || len(page.Invitations) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	invitation := page.Invitations[0]
+	if invitation.Direction != entity.InvitationDirectionReceived || invitation.ProviderID != "ACoBB" || invitation.Name != "John Smith" || invitation.Message != "Let's connect" {
+		t.Fatalf("unexpected invitation: %+v", invitation)
+	}
+}
+
+func TestWithdrawInvitation(t *testing.T) {
+	invitationRepo := &mockInvitationRepo{invitations: map[string]*entity.Invitation{
+		"inv-1":    {AccountID: 7, InvitationID: "inv-1", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending},
+		"accepted": {AccountID: 7, InvitationID: "accepted", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusAccepted},
+		"received": {AccountID: 7, InvitationID: "received", Direction: entity.InvitationDirectionReceived, Status: entity.InvitationStatusPending},
+	}}
+	var withdrawn []string
+	client := &mockUnipileClient{withdrawInvitationFunc: func(_ context.Context, accountID, invitationID string) error {
+		withdrawn = append(withdrawn, accountID+"/"+invitationID)
+		return nil
+	}}
+	uc := newTestUsecase(linkedInAccount(), invitationRepo, client)
+
+	invitation, err := uc.WithdrawInvitation(context.Background(), 1, "acc-1", "inv-1")
+	if err != nil {
+		t.Fatalf("WithdrawInvitation returned error: %v", err)
+	}
+	if invitation.Status != entity.InvitationStatusWithdrawn || invitation.WithdrawnAt == nil {
+		t.Fatalf("unexpected invitation: %+v", invitation)
+	}
+	if invitationRepo.invitations["inv-1"].Status != entity.InvitationStatusWithdrawn {
+		t.Fatal("expected the withdrawal to be stored")
+	}
+
+	for id, wantErr := range map[string]error{
+		"inv-1":    errs.ErrInvitationNotPending,
+		"accepted": errs.ErrInvitationNotPending,
+		"received": errs.ErrInvitationNotPending,
+		"missing":  errs.ErrInvitationNotFound,
+	} {
+		if _, err := uc.WithdrawInvitation(context.Background(), 1, "acc-1", id); !errors.Is(err, wantErr) {
+			t.Fatalf("expected %v for %s, got %v", wantErr, id, err)
+		}
+	}
+	if len(withdrawn) != 1 || withdrawn[0] != "acc-1/inv-1" {
+		t.Fatalf("unexpected Unipile withdrawals: %v", withdrawn)
+	}
+}
+
+func TestHandleRelationEvent(t *testing.T) {
+	invitationRepo := &mockInvitationRepo{invitations: map[string]*entity.Invitation{
+		"inv-1": {AccountID: 7, InvitationID: "inv-1", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoAA"},
+		"inv-2": {AccountID: 7, InvitationID: "inv-2", Direction: entity.InvitationDirectionSent, Status: entity.InvitationStatusPending, ProviderID: "ACoBB"},
+		"inv-3": {AccountID: 7, InvitationID: "inv-3", Direction: entity.InvitationDirectionReceived, Status: entity.InvitationStatusPending, ProviderID: "ACoAA"},
+	}}
+	uc := newTestUsecase(linkedInAccount(), invitationRepo, &mockUnipileClient{})
+
+	accepted, err := uc.HandleRelationEvent(context.Background(), &RelationEvent{AccountID: "acc-1", UserProviderID: "ACoAA"})
+	if err != nil {
+		t.Fatalf("HandleRelationEvent returned error: %v", err)
+	}
+	if accepted != 2 || invitationRepo.invitations["inv-1"].Status != entity.InvitationStatusAccepted ||
+		invitationRepo.invitations["inv-3"].Status != entity.InvitationStatusAccepted || !invitationRepo.invitations["inv-2"].IsPending() {
+		t.Fatalf("unexpected invitations after %d accepted: %+v", accepted, invitationRepo.invitations)
+	}
+
+	// Events of unknown accounts are ignored
+	accepted, err = uc.HandleRelationEvent(context.Background(), &RelationEvent{AccountID: "acc-unknown", UserProviderID: "ACoBB"})
+	if err != nil || accepted != 0 {
+		t.Fatalf("expected the event to be ignored, got %d, %v", accepted, err)
+	}
+}
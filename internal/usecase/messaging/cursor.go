@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// errInvalidCursor is returned for cursors that were not returned by a previous page
+var errInvalidCursor = errs.WrapValidationError(errors.New("invalid cursor"), "Invalid cursor")
+
+// encodeCursor encodes the position of the last item of a page into an opaque cursor
+func encodeCursor(at time.Time, id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(at.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeCursor decodes a cursor built by encodeCursor, ok is false for an empty cursor
+func decodeCursor(cursor string) (at time.Time, id uint, ok bool, err error) {
+	if cursor == "" {
+		return time.Time{}, 0, false, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, false, errInvalidCursor
+	}
+	rawAt, rawID, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, 0, false, errInvalidCursor
+	}
+	parsedAt, err := time.Parse(time.RFC3339Nano, rawAt)
+	if err != nil {
+		return time.Time{}, 0, false, errInvalidCursor
+	}
+	parsedID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false, errInvalidCursor
+	}
+	return parsedAt, uint(parsedID), true, nil
+}
+
+// decodeChatCursor decodes the cursor of a chat page, nil for the first page
+func decodeChatCursor(cursor string) (*repository.ChatPosition, error) {
+	at, id, ok, err := decodeCursor(cursor)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &repository.ChatPosition{LastMessageAt: at, ID: id}, nil
+}
+
+// decodeMessageCursor decodes the cursor of a message page, nil for the first page
+func decodeMessageCursor(cursor string) (*repository.MessagePosition, error) {
+	at, id, ok, err := decodeCursor(cursor)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &repository.MessagePosition{SentAt: at, ID: id}, nil
+}
@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// InboxRequest represents the filters and pagination parameters of the unified inbox
+type InboxRequest struct {
+	PageRequest
+	Unread    *bool      // Only unread or read chats, nil for both
+	AccountID string     // Only chats of this Unipile account, empty for every account of the user
+	Provider  string     // Only chats of accounts of this provider, e.g. "LINKEDIN", empty for every provider
+	Since     *time.Time // Only chats with activity at or after it
+	Until     *time.Time // Only chats with activity before it
+}
+
+// InboxItem is a chat of the unified inbox with the account it belongs to
+type InboxItem struct {
+	*entity.Chat
+	AccountID string `json:"account_id"` // Account ID from Unipile
+	Provider  string `json:"provider"`
+}
+
+// InboxPage is a page of the unified inbox, newest activity first, Cursor is empty on the last page
+type InboxPage struct {
+	Items  []InboxItem `json:"items"`
+	Cursor string      `json:"cursor"`
+}
+
+// Inbox lists one page of the synced chats of every account of the user
+func (a *UsecaseImpl) Inbox(ctx context.Context, userID uint, req *InboxRequest) (*InboxPage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if req.Since != nil && req.Until != nil && !req.Since.Before(*req.Until) {
+		return nil, errs.WrapValidationError(errors.New("since must be before until"), "Invalid date range")
+	}
+	after, err := decodeChatCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := a.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to list accounts")
+	}
+	accountsByID := make(map[uint]*entity.Account, len(accounts))
+	accountIDs := make([]uint, 0, len(accounts))
+	for _, account := range accounts {
+		if req.AccountID != "" && account.AccountID != req.AccountID {
+			continue
+		}
+		if req.Provider != "" && !strings.EqualFold(account.Provider, req.Provider) {
+			continue
+		}
+		accountsByID[account.ID] = account
+		accountIDs = append(accountIDs, account.ID)
+	}
+	if len(accountIDs) == 0 {
+		return &InboxPage{Items: []InboxItem{}}, nil
+	}
+
+	limit := req.pageSize()
+	chats, err := a.chatRepo.List(ctx, &repository.ChatFilter{
+		AccountIDs: accountIDs,
+		Unread:     req.Unread,
+		Since:      req.Since,
+		Until:      req.Until,
+		After:      after,
+		Limit:      limit + 1, // One more chat tells whether there is a next page
+	})
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to list chats")
+	}
+
+	page := &InboxPage{Items: make([]InboxItem, 0, min(len(chats), limit))}
+	if len(chats) > limit {
+		chats = chats[:limit]
+		last := chats[limit-1]
+		page.Cursor = encodeCursor(last.LastMessageAt, last.ID)
+	}
+	for _, chat := range chats {
+		account := accountsByID[chat.AccountID]
+		page.Items = append(page.Items, InboxItem{Chat: chat, AccountID: account.AccountID, Provider: account.Provider})
+	}
+	return page, nil
+}
@@ -0,0 +1,133 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// userAccounts returns a repository where user 1 owns a LinkedIn and a WhatsApp account
+func userAccounts() *mockAccountRepo {
+	return &mockAccountRepo{
+		getByUserIDFunc: func(_ context.Context, userID uint) ([]*entity.Account, error) {
+			if userID != 1 {
+				return nil, nil
+			}
+			return []*entity.Account{
+				{ID: 7, UserID: 1, AccountID: "acc-li", Provider: "LINKEDIN"},
+				{ID: 8, UserID: 1, AccountID: "acc-wa", Provider: "WHATSAPP"},
+			}, nil
+		},
+	}
+}
+
+func TestInbox(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	var received *repository.ChatFilter
+	chatRepo := &mockChatRepo{
+		listFunc: func(_ context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+			received = filter
+			return []*entity.Chat{
+				{ID: 3, AccountID: 8, ChatID: "chat-wa", LastMessageAt: base.Add(time.Hour)},
+				{ID: 2, AccountID: 7, ChatID: "chat-li", LastMessageAt: base},
+			}, nil
+		},
+	}
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, chatRepo, &mockMessageRepo{})
+
+	page, err := uc.Inbox(context.Background(), 1, &InboxRequest{})
+	if err != nil {
+		t.Fatalf("Inbox returned error: %v", err)
+	}
+	if len(received.AccountIDs) != 2 || received.Limit != defaultPageSize+1 {
+		t.Fatalf("unexpected filter: %+v", received)
+	}
+	if len(page.Items) != 2 || page.Cursor != "" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.Items[0].ChatID != "chat-wa" || page.Items[0].AccountID != "acc-wa" || page.Items[0].Provider != "WHATSAPP" {
+		t.Fatalf("unexpected first item: %+v", page.Items[0])
+	}
+	if page.Items[1].AccountID != "acc-li" || page.Items[1].Provider != "LINKEDIN" {
+		t.Fatalf("unexpected second item: %+v", page.Items[1])
+	}
+}
+
+func TestInbox_Filters(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	unread := true
+
+	tests := []struct {
+		name           string
+		req            *InboxRequest
+		wantAccountIDs []uint
+	}{
+		{name: "provider", req: &InboxRequest{Provider: "linkedin"}, wantAccountIDs: []uint{7}},
+		{name: "account", req: &InboxRequest{AccountID: "acc-wa"}, wantAccountIDs: []uint{8}},
+		{name: "unread in range", req: &InboxRequest{Unread: &unread, Since: &since, Until: &until}, wantAccountIDs: []uint{7, 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *repository.ChatFilter
+			chatRepo := &mockChatRepo{
+				listFunc: func(_ context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+					received = filter
+					return nil, nil
+				},
+			}
+			uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, chatRepo, &mockMessageRepo{})
+
+			if _, err := uc.Inbox(context.Background(), 1, tt.req); err != nil {
+				t.Fatalf("Inbox returned error: %v", err)
+			}
+			if len(received.AccountIDs) != len(tt.wantAccountIDs) {
+				t.Fatalf("expected accounts %v, got %v", tt.wantAccountIDs, received.AccountIDs)
+			}
+			for i, id := range tt.wantAccountIDs {
+				if received.AccountIDs[i] != id {
+					t.Fatalf("expected accounts %v, got %v", tt.wantAccountIDs, received.AccountIDs)
+				}
+			}
+			if received.Unread != tt.req.Unread || received.Since != tt.req.Since || received.Until != tt.req.Until {
+				t.Fatalf("unexpected filter: %+v", received)
+			}
+		})
+	}
+}
+
+func TestInbox_NoMatchingAccount(t *testing.T) {
+	chatRepo := &mockChatRepo{
+		listFunc: func(_ context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+			t.Fatal("expected chats not to be listed")
+			return nil, nil
+		},
+	}
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, chatRepo, &mockMessageRepo{})
+
+	page, err := uc.Inbox(context.Background(), 1, &InboxRequest{AccountID: "acc-of-another-user"})
+	if err != nil {
+		t.Fatalf("Inbox returned error: %v", err)
+	}
+	if page.Items == nil || len(page.Items) != 0 || page.Cursor != "" {
+		t.Fatalf("expected an empty page, got %+v", page)
+	}
+}
+
+func TestInbox_InvalidDateRange(t *testing.T) {
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, &mockChatRepo{}, &mockMessageRepo{})
+
+	since := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	until := since.Add(-time.Hour)
+	_, err := uc.Inbox(context.Background(), 1, &InboxRequest{Since: &since, Until: &until})
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// maxPageSize is the largest page Unipile serves
+const maxPageSize = 250
+
+// defaultPageSize is the page size of the local listings when none is requested
+const defaultPageSize = 50
+
+// Usecase handles messaging business logic
+type Usecase interface {
+	ListChats(ctx context.Context, userID uint, accountID string, req *ListChatsRequest) (*ChatPage, error)
+	Inbox(ctx context.Context, userID uint, req *InboxRequest) (*InboxPage, error)
+	SearchMessages(ctx context.Context, userID uint, req *SearchRequest) (*SearchPage, error)
+	ListChatAttendees(ctx context.Context, userID uint, accountID, chatID string, req *PageRequest) (*AttendeePage, error)
+	ListMessages(ctx context.Context, userID uint, accountID, chatID string, req *PageRequest) (*MessagePage, error)
+	SendMessage(ctx context.Context, userID uint, accountID, chatID string, req *SendMessageRequest) (*SentMessage, error)
+	StartChat(ctx context.Context, userID uint, accountID string, req *StartChatRequest) (*SentMessage, error)
+	SyncChats(ctx context.Context) error
+	HandleMessageEvent(ctx context.Context, event *MessageEvent) (*entity.Message, error)
+}
+
+// UsecaseImpl handles messaging business logic
+type UsecaseImpl struct {
+	txRepo        repository.TxRepository
+	accountRepo   repository.AccountRepository
+	chatRepo      repository.ChatRepository
+	messageRepo   repository.MessageRepository
+	unipileClient service.UnipileClient
+	logger        *logrus.Logger
+}
+
+// NewMessagingUsecase creates a new messaging usecase
+func NewMessagingUsecase(txRepo repository.TxRepository, accountRepo repository.AccountRepository, chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, unipileClient service.UnipileClient, logger *logrus.Logger) Usecase {
+	return &UsecaseImpl{
+		txRepo:        txRepo,
+		accountRepo:   accountRepo,
+		chatRepo:      chatRepo,
+		messageRepo:   messageRepo,
+		unipileClient: unipileClient,
+		logger:        logger,
+	}
+}
+
+// PageRequest represents the pagination parameters of a list request
+type PageRequest struct {
+	Limit  int    // Page size (1-250), 0 uses the default page size
+	Cursor string // Cursor returned by the previous page, empty for the first page
+}
+
+// validate checks the page size is one Unipile serves
+func (r *PageRequest) validate() error {
+	if r.Limit < 0 || r.Limit > maxPageSize {
+		return errs.WrapValidationError(fmt.Errorf("limit must be between 1 and %d", maxPageSize), "Invalid page size")
+	}
+	return nil
+}
+
+// pageSize returns the requested page size, or the default one
+func (r *PageRequest) pageSize() int {
+	if r.Limit == 0 {
+		return defaultPageSize
+	}
+	return r.Limit
+}
+
+// ListChatsRequest represents the filters and pagination parameters for listing chats
+type ListChatsRequest struct {
+	PageRequest
+	Unread *bool // Only unread or read chats, nil for both
+}
+
+// ChatPage is a page of chats, newest activity first, Cursor is empty on the last page
+type ChatPage struct {
+	Chats  []*entity.Chat `json:"chats"`
+	Cursor string         `json:"cursor"`
+	// Last successful sync of the account from Unipile, nil before the first one
+	SyncedAt *time.Time `json:"synced_at"`
+}
+
+// AttendeePage is a page of chat attendees, Cursor is empty on the last page
+type AttendeePage struct {
+	Attendees []service.ChatAttendee `json:"attendees"`
+	Cursor    string                 `json:"cursor"`
+}
+
+// MessagePage is a page of messages, newest first, Cursor is empty on the last page
+type MessagePage struct {
+	Messages []*entity.Message `json:"messages"`
+	Cursor   string            `json:"cursor"`
+}
+
+// ListChats lists one page of the synced chats of an account of the user
+func (a *UsecaseImpl) ListChats(ctx context.Context, userID uint, accountID string, req *ListChatsRequest) (*ChatPage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	after, err := decodeChatCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	account, err := a.ownedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.pageSize()
+	chats, err := a.chatRepo.List(ctx, &repository.ChatFilter{
+		AccountIDs: []uint{account.ID},
+		Unread:     req.Unread,
+		After:      after,
+		Limit:      limit + 1, // One more chat tells whether there is a next page
+	})
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to list chats")
+	}
+
+	page := &ChatPage{Chats: chats}
+	if len(chats) > limit {
+		page.Chats = chats[:limit]
+		last := page.Chats[limit-1]
+		page.Cursor = encodeCursor(last.LastMessageAt, last.ID)
+	}
+	page.Chats = nonNil(page.Chats)
+
+	state, err := a.chatRepo.GetSyncState(ctx, account.ID)
+	switch {
+	case err == nil:
+		page.SyncedAt = state.LastSyncedAt
+	case !errors.Is(err, repository.ErrChatSyncStateNotFound):
+		return nil, errs.WrapInternalError(err, "Failed to get chat sync state")
+	}
+
+	return page, nil
+}
+
+// ListChatAttendees lists one page of attendees of a chat of an account of the user
+func (a *UsecaseImpl) ListChatAttendees(ctx context.Context, userID uint, accountID, chatID string, req *PageRequest) (*AttendeePage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if err := a.ownedChat(ctx, userID, accountID, chatID); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.unipileClient.ListChatAttendees(ctx, &service.ListChatAttendeesRequest{
+		ChatID: chatID,
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+	})
+	if err != nil {
+		return nil, unipileerr.Map(a.logger, err, "Failed to list chat attendees")
+	}
+
+	return &AttendeePage{Attendees: nonNil(resp.Items), Cursor: nextCursor(resp.Cursor, req.Cursor)}, nil
+}
+
+// ListMessages lists one page of the synced messages of a chat of an account of the user
+func (a *UsecaseImpl) ListMessages(ctx context.Context, userID uint, accountID, chatID string, req *PageRequest) (*MessagePage, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	after, err := decodeMessageCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	account, err := a.ownedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	chat, err := a.chatRepo.GetByAccountIDAndChatID(ctx, account.ID, chatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrChatNotFound) {
+			return nil, errs.ErrChatNotFound
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get chat")
+	}
+
+	limit := req.pageSize()
+	messages, err := a.messageRepo.List(ctx, &repository.MessageFilter{
+		ChatID: chat.ID,
+		After:  after,
+		Limit:  limit + 1, // One more message tells whether there is a next page
+	})
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to list messages")
+	}
+
+	page := &MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		last := page.Messages[limit-1]
+		page.Cursor = encodeCursor(last.SentAt, last.ID)
+	}
+	page.Messages = nonNil(page.Messages)
+	return page, nil
+}
+
+// ownedAccount returns the account of the user, accounts of other users are reported as not found
+func (a *UsecaseImpl) ownedAccount(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	account, err := a.accountRepo.GetByUserIDAndAccountID(ctx, userID, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, errs.ErrAccountNotFound
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get account")
+	}
+	return account, nil
+}
+
+// ownedChat checks the chat belongs to an account of the user, chats of other accounts are reported as not found
+func (a *UsecaseImpl) ownedChat(ctx context.Context, userID uint, accountID, chatID string) error {
+	if _, err := a.ownedAccount(ctx, userID, accountID); err != nil {
+		return err
+	}
+
+	chat, err := a.unipileClient.GetChat(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileChatNotFound) {
+			return errs.ErrChatNotFound
+		}
+		return unipileerr.Map(a.logger, err, "Failed to get chat")
+	}
+	if chat.AccountID != accountID {
+		return errs.ErrChatNotFound
+	}
+	return nil
+}
+
+// nextCursor returns the cursor of the next page, empty when the page is the last one
+func nextCursor(cursor *string, current string) string {
+	if cursor == nil || *cursor == current {
+		return ""
+	}
+	return *cursor
+}
+
+// nonNil returns an empty slice for nil, so empty pages are encoded as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,388 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// mockAccountRepo implements the account lookups of the messaging usecase, other methods panic
+type mockAccountRepo struct {
+	repository.AccountRepository
+	getByUserIDAndAccountIDFunc func(ctx context.Context, userID uint, accountID string) (*entity.Account, error)
+	listFunc                    func(ctx context.Context) ([]*entity.Account, error)
+	getByUserIDFunc             func(ctx context.Context, userID uint) ([]*entity.Account, error)
+	getByAccountIDForUpdateFunc func(ctx context.Context, accountID string) (*entity.Account, error)
+}
+
+func (m *mockAccountRepo) List(ctx context.Context) ([]*entity.Account, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) GetByUserID(ctx context.Context, userID uint) ([]*entity.Account, error) {
+	if m.getByUserIDFunc != nil {
+		return m.getByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) GetByAccountIDForUpdate(ctx context.Context, accountID string) (*entity.Account, error) {
+	if m.getByAccountIDForUpdateFunc != nil {
+		return m.getByAccountIDForUpdateFunc(ctx, accountID)
+	}
+	return nil, repository.ErrAccountNotFound
+}
+
+func (m *mockAccountRepo) GetByUserIDAndAccountID(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	if m.getByUserIDAndAccountIDFunc != nil {
+		return m.getByUserIDAndAccountIDFunc(ctx, userID, accountID)
+	}
+	return nil, nil
+}
+
+// ownedAccounts returns a repository where the user owns the given accounts
+func ownedAccounts(userID uint, accountIDs ...string) *mockAccountRepo {
+	return &mockAccountRepo{
+		getByUserIDAndAccountIDFunc: func(_ context.Context, gotUserID uint, accountID string) (*entity.Account, error) {
+			for _, id := range accountIDs {
+				if gotUserID == userID && accountID == id {
+					return &entity.Account{ID: 7, UserID: userID, AccountID: id, Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusOK}, nil
+				}
+			}
+			return nil, repository.ErrAccountNotFound
+		},
+	}
+}
+
+// mockUnipileClient implements the messaging calls of the Unipile client, other methods panic
+type mockUnipileClient struct {
+	service.UnipileClient
+	listChatsFunc         func(ctx context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error)
+	getChatFunc           func(ctx context.Context, chatID string) (*service.Chat, error)
+	listChatAttendeesFunc func(ctx context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error)
+	listMessagesFunc      func(ctx context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error)
+	sendMessageFunc       func(ctx context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error)
+	startChatFunc         func(ctx context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error)
+}
+
+func (m *mockUnipileClient) ListChats(ctx context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+	if m.listChatsFunc != nil {
+		return m.listChatsFunc(ctx, req)
+	}
+	return &service.ChatListResponse{}, nil
+}
+
+func (m *mockUnipileClient) GetChat(ctx context.Context, chatID string) (*service.Chat, error) {
+	if m.getChatFunc != nil {
+		return m.getChatFunc(ctx, chatID)
+	}
+	return nil, service.ErrUnipileChatNotFound
+}
+
+func (m *mockUnipileClient) ListChatAttendees(ctx context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+	if m.listChatAttendeesFunc != nil {
+		return m.listChatAttendeesFunc(ctx, req)
+	}
+	return &service.ChatAttendeeListResponse{}, nil
+}
+
+func (m *mockUnipileClient) ListMessages(ctx context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error) {
+	if m.listMessagesFunc != nil {
+		return m.listMessagesFunc(ctx, req)
+	}
+	return &service.MessageListResponse{}, nil
+}
+
+func (m *mockUnipileClient) SendMessage(ctx context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error) {
+	if m.sendMessageFunc != nil {
+		return m.sendMessageFunc(ctx, req)
+	}
+	return &service.SendMessageResponse{}, nil
+}
+
+func (m *mockUnipileClient) StartChat(ctx context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error) {
+	if m.startChatFunc != nil {
+		return m.startChatFunc(ctx, req)
+	}
+	return &service.StartChatResponse{}, nil
+}
+
+// mockTxRepo runs the transaction with the repositories of the test
+type mockTxRepo struct {
+	repos repository.Repositories
+	inTx  bool // Set while a transaction runs
+}
+
+func (m *mockTxRepo) Do(ctx context.Context, fn func(*repository.Repositories) error) error {
+	m.inTx = true
+	defer func() { m.inTx = false }()
+	return fn(&m.repos)
+}
+
+// mockChatRepo implements ChatRepository with function fields, by default no chat is stored
+type mockChatRepo struct {
+	upsertFunc                  func(ctx context.Context, chat *entity.Chat) error
+	getByAccountIDAndChatIDFunc func(ctx context.Context, accountID uint, chatID string) (*entity.Chat, error)
+	listFunc                    func(ctx context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error)
+	updateFunc                  func(ctx context.Context, chat *entity.Chat) error
+	getSyncStateFunc            func(ctx context.Context, accountID uint) (*entity.ChatSyncState, error)
+	saveSyncStateFunc           func(ctx context.Context, state *entity.ChatSyncState) error
+}
+
+func (m *mockChatRepo) Upsert(ctx context.Context, chat *entity.Chat) error {
+	if m.upsertFunc != nil {
+		return m.upsertFunc(ctx, chat)
+	}
+	return nil
+}
+
+func (m *mockChatRepo) GetByAccountIDAndChatID(ctx context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+	if m.getByAccountIDAndChatIDFunc != nil {
+		return m.getByAccountIDAndChatIDFunc(ctx, accountID, chatID)
+	}
+	return nil, repository.ErrChatNotFound
+}
+
+func (m *mockChatRepo) List(ctx context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockChatRepo) Update(ctx context.Context, chat *entity.Chat) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, chat)
+	}
+	return nil
+}
+
+func (m *mockChatRepo) GetSyncState(ctx context.Context, accountID uint) (*entity.ChatSyncState, error) {
+	if m.getSyncStateFunc != nil {
+		return m.getSyncStateFunc(ctx, accountID)
+	}
+	return nil, repository.ErrChatSyncStateNotFound
+}
+
+func (m *mockChatRepo) SaveSyncState(ctx context.Context, state *entity.ChatSyncState) error {
+	if m.saveSyncStateFunc != nil {
+		return m.saveSyncStateFunc(ctx, state)
+	}
+	return nil
+}
+
+// mockMessageRepo implements MessageRepository with function fields, by default no message is stored
+type mockMessageRepo struct {
+	upsertFunc                  func(ctx context.Context, message *entity.Message) error
+	getByChatIDAndMessageIDFunc func(ctx context.Context, chatID uint, messageID string) (*entity.Message, error)
+	listFunc                    func(ctx context.Context, filter *repository.MessageFilter) ([]*entity.Message, error)
+	searchFunc                  func(ctx context.Context, filter *repository.MessageSearchFilter) ([]*repository.MessageSearchResult, error)
+}
+
+func (m *mockMessageRepo) Upsert(ctx context.Context, message *entity.Message) error {
+	if m.upsertFunc != nil {
+		return m.upsertFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockMessageRepo) GetByChatIDAndMessageID(ctx context.Context, chatID uint, messageID string) (*entity.Message, error) {
+	if m.getByChatIDAndMessageIDFunc != nil {
+		return m.getByChatIDAndMessageIDFunc(ctx, chatID, messageID)
+	}
+	return nil, repository.ErrMessageNotFound
+}
+
+func (m *mockMessageRepo) List(ctx context.Context, filter *repository.MessageFilter) ([]*entity.Message, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockMessageRepo) Search(ctx context.Context, filter *repository.MessageSearchFilter) ([]*repository.MessageSearchResult, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+// newTestUsecase creates a messaging usecase whose transactions run with the given repositories
+func newTestUsecase(accountRepo *mockAccountRepo, unipileClient *mockUnipileClient, chatRepo *mockChatRepo, messageRepo *mockMessageRepo) Usecase {
+	txRepo := &mockTxRepo{repos: repository.Repositories{Account: accountRepo, Chat: chatRepo, Message: messageRepo}}
+	return NewMessagingUsecase(txRepo, accountRepo, chatRepo, messageRepo, unipileClient, logrus.New())
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestListChats(t *testing.T) {
+	ctx := context.Background()
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	syncedAt := base.Add(time.Hour)
+	var received *repository.ChatFilter
+	chatRepo := &mockChatRepo{
+		listFunc: func(_ context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+			received = filter
+			return []*entity.Chat{
+				{ID: 3, ChatID: "chat-3", LastMessageAt: base.Add(2 * time.Minute)},
+				{ID: 2, ChatID: "chat-2", LastMessageAt: base.Add(time.Minute)},
+				{ID: 1, ChatID: "chat-1", LastMessageAt: base},
+			}, nil
+		},
+		getSyncStateFunc: func(_ context.Context, accountID uint) (*entity.ChatSyncState, error) {
+			return &entity.ChatSyncState{AccountID: accountID, LastSyncedAt: &syncedAt}, nil
+		},
+	}
+
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), &mockUnipileClient{}, chatRepo, &mockMessageRepo{})
+
+	unread := true
+	page, err := uc.ListChats(ctx, 1, "acc-1", &ListChatsRequest{PageRequest: PageRequest{Limit: 2}, Unread: &unread})
+	if err != nil {
+		t.Fatalf("ListChats returned error: %v", err)
+	}
+	if len(received.AccountIDs) != 1 || received.AccountIDs[0] != 7 || received.Limit != 3 || received.Unread == nil || !*received.Unread || received.After != nil {
+		t.Fatalf("unexpected filter: %+v", received)
+	}
+	if len(page.Chats) != 2 || page.Chats[1].ChatID != "chat-2" || page.Cursor == "" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.SyncedAt == nil || !page.SyncedAt.Equal(syncedAt) {
+		t.Fatalf("unexpected synced at: %v", page.SyncedAt)
+	}
+
+	// The cursor resumes after the last chat of the page
+	if _, err := uc.ListChats(ctx, 1, "acc-1", &ListChatsRequest{PageRequest: PageRequest{Cursor: page.Cursor}}); err != nil {
+		t.Fatalf("ListChats returned error: %v", err)
+	}
+	if received.After == nil || received.After.ID != 2 || !received.After.LastMessageAt.Equal(base.Add(time.Minute)) || received.Limit != defaultPageSize+1 {
+		t.Fatalf("unexpected filter: %+v", received)
+	}
+}
+
+func TestListChats_LastPage(t *testing.T) {
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), &mockUnipileClient{}, &mockChatRepo{}, &mockMessageRepo{})
+
+	page, err := uc.ListChats(context.Background(), 1, "acc-1", &ListChatsRequest{})
+	if err != nil {
+		t.Fatalf("ListChats returned error: %v", err)
+	}
+	if page.Chats == nil || len(page.Chats) != 0 || page.Cursor != "" || page.SyncedAt != nil {
+		t.Fatalf("expected an empty last page, got %+v", page)
+	}
+}
+
+func TestListChats_NotOwned(t *testing.T) {
+	chatRepo := &mockChatRepo{
+		listFunc: func(_ context.Context, filter *repository.ChatFilter) ([]*entity.Chat, error) {
+			t.Fatal("expected chats not to be listed")
+			return nil, nil
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(2, "acc-1"), &mockUnipileClient{}, chatRepo, &mockMessageRepo{})
+
+	_, err := uc.ListChats(context.Background(), 1, "acc-1", &ListChatsRequest{})
+	if !errors.Is(err, errs.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestListChats_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *ListChatsRequest
+	}{
+		{name: "limit too large", req: &ListChatsRequest{PageRequest: PageRequest{Limit: 500}}},
+		{name: "malformed cursor", req: &ListChatsRequest{PageRequest: PageRequest{Cursor: "not a cursor"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestUsecase(ownedAccounts(1, "acc-1"), &mockUnipileClient{}, &mockChatRepo{}, &mockMessageRepo{})
+
+			_, err := uc.ListChats(context.Background(), 1, "acc-1", tt.req)
+			var codedErr *errs.CodedError
+			if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestListMessages(t *testing.T) {
+	ctx := context.Background()
+
+	chatRepo := &mockChatRepo{
+		getByAccountIDAndChatIDFunc: func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+			if accountID != 7 || chatID != "chat-1" {
+				return nil, repository.ErrChatNotFound
+			}
+			return &entity.Chat{ID: 11, AccountID: accountID, ChatID: chatID}, nil
+		},
+	}
+	var received *repository.MessageFilter
+	messageRepo := &mockMessageRepo{
+		listFunc: func(_ context.Context, filter *repository.MessageFilter) ([]*entity.Message, error) {
+			received = filter
+			return []*entity.Message{{ID: 1, MessageID: "msg-1", Text: "Hello"}}, nil
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), &mockUnipileClient{}, chatRepo, messageRepo)
+
+	page, err := uc.ListMessages(ctx, 1, "acc-1", "chat-1", &PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListMessages returned error: %v", err)
+	}
+	if received.ChatID != 11 || received.Limit != 11 || received.After != nil {
+		t.Fatalf("unexpected filter: %+v", received)
+	}
+	if len(page.Messages) != 1 || page.Messages[0].Text != "Hello" || page.Cursor != "" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestListMessages_ChatOfAnotherAccount(t *testing.T) {
+	messageRepo := &mockMessageRepo{
+		listFunc: func(_ context.Context, filter *repository.MessageFilter) ([]*entity.Message, error) {
+			t.Fatal("expected messages not to be listed")
+			return nil, nil
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(1, "acc-1", "acc-2"), &mockUnipileClient{}, &mockChatRepo{}, messageRepo)
+
+	_, err := uc.ListMessages(context.Background(), 1, "acc-1", "chat-9", &PageRequest{})
+	if !errors.Is(err, errs.ErrChatNotFound) {
+		t.Fatalf("expected ErrChatNotFound, got %v", err)
+	}
+}
+
+func TestListChatAttendees_UnipileErrors(t *testing.T) {
+	unipileClient := &mockUnipileClient{
+		getChatFunc: func(_ context.Context, chatID string) (*service.Chat, error) {
+			return &service.Chat{ID: chatID, AccountID: "acc-1"}, nil
+		},
+		listChatAttendeesFunc: func(_ context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+			return nil, &service.UnipileError{Status: 429}
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), unipileClient, &mockChatRepo{}, &mockMessageRepo{})
+
+	_, err := uc.ListChatAttendees(context.Background(), 1, "acc-1", "chat-1", &PageRequest{})
+	if !errors.Is(err, errs.ErrProviderRateLimited) {
+		t.Fatalf("expected ErrProviderRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+// maxSearchTerms is the largest number of words and phrases a search query may have
+const maxSearchTerms = 16
+
+// maxSearchQueryLength is the longest search query accepted, in bytes
+const maxSearchQueryLength = 512
+
+// SearchRequest represents a full-text search of the synced messages.
+// Query matches the messages containing every word, "quoted phrases" match consecutive words
+// and a trailing * matches any word starting with the given one.
+type SearchRequest struct {
+	Query  string
+	Limit  int // Page size (1-250), 0 uses the default page size
+	Offset int // Number of results to skip
+}
+
+// SearchResult is a message matching a search, with its chat, its account and the highlighted matches
+type SearchResult struct {
+	*entity.Message
+	ChatID        string  `json:"chat_id"`    // Chat ID from Unipile
+	AccountID     string  `json:"account_id"` // Account ID from Unipile
+	Provider      string  `json:"provider"`
+	AttendeeNames string  `json:"attendee_names"`
+	Rank          float64 `json:"rank"`
+	// Message text fragments and attendee names with the matches wrapped in <mark> tags
+	Highlight         string `json:"highlight"`
+	AttendeeHighlight string `json:"attendee_highlight"`
+}
+
+// SearchPage is a page of search results, best match first, NextOffset is nil on the last page
+type SearchPage struct {
+	Items      []SearchResult `json:"items"`
+	NextOffset *int           `json:"next_offset"`
+}
+
+// SearchMessages searches the synced messages of every account of the user by text and attendee names
+func (a *UsecaseImpl) SearchMessages(ctx context.Context, userID uint, req *SearchRequest) (*SearchPage, error) {
+	page := PageRequest{Limit: req.Limit}
+	if err := page.validate(); err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 {
+		return nil, errs.WrapValidationError(errors.New("offset must not be negative"), "Invalid offset")
+	}
+	query, err := parseSearchQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := a.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to list accounts")
+	}
+	if len(accounts) == 0 {
+		return &SearchPage{Items: []SearchResult{}}, nil
+	}
+	accountsByID := make(map[uint]*entity.Account, len(accounts))
+	accountIDs := make([]uint, 0, len(accounts))
+	for _, account := range accounts {
+		accountsByID[account.ID] = account
+		accountIDs = append(accountIDs, account.ID)
+	}
+
+	limit := page.pageSize()
+	results, err := a.messageRepo.Search(ctx, &repository.MessageSearchFilter{
+		AccountIDs: accountIDs,
+		Query:      query,
+		Limit:      limit + 1, // One more result tells whether there is a next page
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to search messages")
+	}
+
+	searchPage := &SearchPage{Items: make([]SearchResult, 0, min(len(results), limit))}
+	if len(results) > limit {
+		results = results[:limit]
+		nextOffset := req.Offset + limit
+		searchPage.NextOffset = &nextOffset
+	}
+	for _, result := range results {
+		account := accountsByID[result.AccountID]
+		searchPage.Items = append(searchPage.Items, SearchResult{
+			Message:           &result.Message,
+			ChatID:            result.ChatExternalID,
+			AccountID:         account.AccountID,
+			Provider:          account.Provider,
+			AttendeeNames:     result.AttendeeNames,
+			Rank:              result.Rank,
+			Highlight:         result.Highlight,
+			AttendeeHighlight: result.AttendeeHighlight,
+		})
+	}
+	return searchPage, nil
+}
+
+// parseSearchQuery parses the words, "quoted phrases" and prefix* terms of a search query.
+// Punctuation separates words, so a term like e-mail is matched as a phrase.
+func parseSearchQuery(q string) (repository.SearchQuery, error) {
+	if len(q) > maxSearchQueryLength {
+		return nil, errs.WrapValidationError(fmt.Errorf("query must be at most %d characters", maxSearchQueryLength), "Invalid search query")
+	}
+
+	var query repository.SearchQuery
+	add := func(text string, prefix bool) {
+		words := strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) > 0 {
+			query = append(query, repository.SearchTerm{Words: words, Prefix: prefix})
+		}
+	}
+
+	// Segments alternate between unquoted text and phrases, an unterminated phrase runs to the end
+	for i, segment := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			add(segment, strings.HasSuffix(strings.TrimSpace(segment), "*"))
+			continue
+		}
+		for _, field := range strings.Fields(segment) {
+			add(field, strings.HasSuffix(field, "*"))
+		}
+	}
+
+	if len(query) == 0 {
+		return nil, errs.WrapValidationError(errors.New("query must contain at least one word"), "Invalid search query")
+	}
+	if len(query) > maxSearchTerms {
+		return nil, errs.WrapValidationError(fmt.Errorf("query must have at most %d words or phrases", maxSearchTerms), "Invalid search query")
+	}
+	return query, nil
+}
@@ -0,0 +1,143 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want repository.SearchQuery
+	}{
+		{
+			name: "words",
+			q:    "hello  World",
+			want: repository.SearchQuery{{Words: []string{"hello"}}, {Words: []string{"World"}}},
+		},
+		{
+			name: "prefix",
+			q:    "engin* offer",
+			want: repository.SearchQuery{{Words: []string{"engin"}, Prefix: true}, {Words: []string{"offer"}}},
+		},
+		{
+			name: "phrase",
+			q:    `"product manager" remote`,
+			want: repository.SearchQuery{{Words: []string{"product", "manager"}}, {Words: []string{"remote"}}},
+		},
+		{
+			name: "phrase with prefix",
+			q:    `"senior eng*"`,
+			want: repository.SearchQuery{{Words: []string{"senior", "eng"}, Prefix: true}},
+		},
+		{
+			name: "unterminated phrase",
+			q:    `call "next week`,
+			want: repository.SearchQuery{{Words: []string{"call"}}, {Words: []string{"next", "week"}}},
+		},
+		{
+			name: "punctuation",
+			q:    "e-mail, thanks!",
+			want: repository.SearchQuery{{Words: []string{"e", "mail"}}, {Words: []string{"thanks"}}},
+		},
+		{
+			name: "operators are not interpreted",
+			q:    "a&b | !c:*",
+			want: repository.SearchQuery{{Words: []string{"a", "b"}}, {Words: []string{"c"}, Prefix: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSearchQuery(tt.q)
+			if err != nil {
+				t.Fatalf("parseSearchQuery returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery_Invalid(t *testing.T) {
+	for _, q := range []string{"", "  ", `"" * -`, strings.Repeat("word ", maxSearchTerms+1), strings.Repeat("a", maxSearchQueryLength+1)} {
+		_, err := parseSearchQuery(q)
+		var codedErr *errs.CodedError
+		if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+			t.Fatalf("expected validation error for %q, got %v", q, err)
+		}
+	}
+}
+
+func TestSearchMessages(t *testing.T) {
+	var received *repository.MessageSearchFilter
+	messageRepo := &mockMessageRepo{
+		searchFunc: func(_ context.Context, filter *repository.MessageSearchFilter) ([]*repository.MessageSearchResult, error) {
+			received = filter
+			return []*repository.MessageSearchResult{
+				{Message: entity.Message{ID: 5, AccountID: 8, MessageID: "msg-5", Text: "Hello there"}, ChatExternalID: "chat-wa", Rank: 0.9, Highlight: "<mark>Hello</mark> there"},
+				{Message: entity.Message{ID: 4, AccountID: 7, MessageID: "msg-4"}, ChatExternalID: "chat-li", AttendeeNames: "Hello Kitty", AttendeeHighlight: "<mark>Hello</mark> Kitty"},
+				{Message: entity.Message{ID: 3, AccountID: 7, MessageID: "msg-3"}, ChatExternalID: "chat-li"},
+			}, nil
+		},
+	}
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, &mockChatRepo{}, messageRepo)
+
+	page, err := uc.SearchMessages(context.Background(), 1, &SearchRequest{Query: "hello", Limit: 2, Offset: 4})
+	if err != nil {
+		t.Fatalf("SearchMessages returned error: %v", err)
+	}
+	if !reflect.DeepEqual(received.AccountIDs, []uint{7, 8}) || received.Limit != 3 || received.Offset != 4 || len(received.Query) != 1 {
+		t.Fatalf("unexpected filter: %+v", received)
+	}
+	if len(page.Items) != 2 || page.NextOffset == nil || *page.NextOffset != 6 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	first := page.Items[0]
+	if first.MessageID != "msg-5" || first.ChatID != "chat-wa" || first.AccountID != "acc-wa" || first.Provider != "WHATSAPP" || first.Highlight != "<mark>Hello</mark> there" {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+	second := page.Items[1]
+	if second.AccountID != "acc-li" || second.AttendeeNames != "Hello Kitty" || second.AttendeeHighlight != "<mark>Hello</mark> Kitty" {
+		t.Fatalf("unexpected second result: %+v", second)
+	}
+}
+
+func TestSearchMessages_NoAccounts(t *testing.T) {
+	messageRepo := &mockMessageRepo{
+		searchFunc: func(_ context.Context, filter *repository.MessageSearchFilter) ([]*repository.MessageSearchResult, error) {
+			t.Fatal("expected messages not to be searched")
+			return nil, nil
+		},
+	}
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, &mockChatRepo{}, messageRepo)
+
+	page, err := uc.SearchMessages(context.Background(), 2, &SearchRequest{Query: "hello"})
+	if err != nil {
+		t.Fatalf("SearchMessages returned error: %v", err)
+	}
+	if page.Items == nil || len(page.Items) != 0 || page.NextOffset != nil {
+		t.Fatalf("expected an empty page, got %+v", page)
+	}
+}
+
+func TestSearchMessages_InvalidPage(t *testing.T) {
+	uc := newTestUsecase(userAccounts(), &mockUnipileClient{}, &mockChatRepo{}, &mockMessageRepo{})
+
+	for _, req := range []*SearchRequest{{Query: "hello", Offset: -1}, {Query: "hello", Limit: maxPageSize + 1}} {
+		_, err := uc.SearchMessages(context.Background(), 1, req)
+		var codedErr *errs.CodedError
+		if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+			t.Fatalf("expected validation error for %+v, got %v", req, err)
+		}
+	}
+}
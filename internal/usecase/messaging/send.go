@@ -0,0 +1,102 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// providerLinkedIn is the only provider supporting InMail
+const providerLinkedIn = "LINKEDIN"
+
+// SendMessageRequest represents the message to send in an existing chat
+type SendMessageRequest struct {
+	Text        string
+	Attachments []service.Attachment
+}
+
+// StartChatRequest represents a new chat and its first message
+type StartChatRequest struct {
+	AttendeeProviderIDs []string // Provider IDs of the attendees, e.g. LinkedIn member IDs
+	Text                string
+	Subject             string
+	InMail              bool // LinkedIn accounts only
+	Attachments         []service.Attachment
+}
+
+// SentMessage identifies a message sent through Unipile
+type SentMessage struct {
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+}
+
+// SendMessage sends a message in a chat of a connected account of the user
+func (a *UsecaseImpl) SendMessage(ctx context.Context, userID uint, accountID, chatID string, req *SendMessageRequest) (*SentMessage, error) {
+	if req.Text == "" && len(req.Attachments) == 0 {
+		return nil, errs.WrapValidationError(errors.New("text or attachments required"), "Message must have a text or attachments")
+	}
+	if _, err := a.connectedAccount(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+	if err := a.ownedChat(ctx, userID, accountID, chatID); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.unipileClient.SendMessage(ctx, &service.SendMessageRequest{
+		ChatID:      chatID,
+		Text:        req.Text,
+		Attachments: req.Attachments,
+	})
+	if err != nil {
+		return nil, unipileerr.Map(a.logger, err, "Failed to send message")
+	}
+
+	return &SentMessage{ChatID: chatID, MessageID: resp.MessageID}, nil
+}
+
+// StartChat starts a chat with the attendees from a connected account of the user and sends the first message
+func (a *UsecaseImpl) StartChat(ctx context.Context, userID uint, accountID string, req *StartChatRequest) (*SentMessage, error) {
+	if len(req.AttendeeProviderIDs) == 0 {
+		return nil, errs.WrapValidationError(errors.New("attendees required"), "At least one attendee is required")
+	}
+	if req.Text == "" && len(req.Attachments) == 0 {
+		return nil, errs.WrapValidationError(errors.New("text or attachments required"), "Message must have a text or attachments")
+	}
+	account, err := a.connectedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if req.InMail && account.Provider != providerLinkedIn {
+		return nil, errs.WrapValidationError(errors.New("inmail on non-linkedin account"), "InMail is only available for LinkedIn accounts")
+	}
+
+	resp, err := a.unipileClient.StartChat(ctx, &service.StartChatRequest{
+		AccountID:           accountID,
+		AttendeeProviderIDs: req.AttendeeProviderIDs,
+		Text:                req.Text,
+		Subject:             req.Subject,
+		InMail:              req.InMail,
+		Attachments:         req.Attachments,
+	})
+	if err != nil {
+		return nil, unipileerr.Map(a.logger, err, "Failed to start chat")
+	}
+
+	return &SentMessage{ChatID: resp.ChatID, MessageID: resp.MessageID}, nil
+}
+
+// connectedAccount returns the account of the user, rejecting accounts that cannot send messages
+func (a *UsecaseImpl) connectedAccount(ctx context.Context, userID uint, accountID string) (*entity.Account, error) {
+	account, err := a.ownedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.CurrentStatus != entity.AccountStatusOK {
+		return nil, errs.ErrAccountNotConnected
+	}
+	return account, nil
+}
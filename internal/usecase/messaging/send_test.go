@@ -0,0 +1,181 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// accountWithStatus returns a repository where user 1 owns acc-1 with the given provider and status
+func accountWithStatus(provider string, status entity.AccountStatus) *mockAccountRepo {
+	return &mockAccountRepo{
+		getByUserIDAndAccountIDFunc: func(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+			if userID != 1 || accountID != "acc-1" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &entity.Account{UserID: 1, AccountID: "acc-1", Provider: provider, CurrentStatus: status}, nil
+		},
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	var received *service.SendMessageRequest
+	unipileClient := &mockUnipileClient{
+		getChatFunc: func(_ context.Context, chatID string) (*service.Chat, error) {
+			return &service.Chat{ID: chatID, AccountID: "acc-1"}, nil
+		},
+		sendMessageFunc: func(_ context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error) {
+			received = req
+			return &service.SendMessageResponse{MessageID: "msg-1"}, nil
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), unipileClient, &mockChatRepo{}, &mockMessageRepo{})
+
+	sent, err := uc.SendMessage(context.Background(), 1, "acc-1", "chat-1", &SendMessageRequest{
+		Text:        "Hello",
+		Attachments: []service.Attachment{{FileName: "deck.pdf", Content: []byte("%PDF")}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if received.ChatID != "chat-1" || received.Text != "Hello" || len(received.Attachments) != 1 {
+		t.Fatalf("unexpected Unipile request: %+v", received)
+	}
+	if sent.ChatID != "chat-1" || sent.MessageID != "msg-1" {
+		t.Fatalf("unexpected sent message: %+v", sent)
+	}
+}
+
+func TestSendMessage_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountRepo *mockAccountRepo
+		req         *SendMessageRequest
+		wantErr     error
+	}{
+		{
+			name:        "account not connected",
+			accountRepo: accountWithStatus("LINKEDIN", entity.AccountStatusCredentials),
+			req:         &SendMessageRequest{Text: "Hello"},
+			wantErr:     errs.ErrAccountNotConnected,
+		},
+		{
+			name:        "account of another user",
+			accountRepo: ownedAccounts(2, "acc-1"),
+			req:         &SendMessageRequest{Text: "Hello"},
+			wantErr:     errs.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unipileClient := &mockUnipileClient{
+				sendMessageFunc: func(_ context.Context, req *service.SendMessageRequest) (*service.SendMessageResponse, error) {
+					t.Fatal("expected the message not to be sent")
+					return nil, nil
+				},
+			}
+			uc := newTestUsecase(tt.accountRepo, unipileClient, &mockChatRepo{}, &mockMessageRepo{})
+
+			_, err := uc.SendMessage(context.Background(), 1, "acc-1", "chat-1", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSendMessage_Empty(t *testing.T) {
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), &mockUnipileClient{}, &mockChatRepo{}, &mockMessageRepo{})
+
+	_, err := uc.SendMessage(context.Background(), 1, "acc-1", "chat-1", &SendMessageRequest{})
+	var codedErr *errs.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestStartChat(t *testing.T) {
+	var received *service.StartChatRequest
+	unipileClient := &mockUnipileClient{
+		startChatFunc: func(_ context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error) {
+			received = req
+			return &service.StartChatResponse{ChatID: "chat-9", MessageID: "msg-9"}, nil
+		},
+	}
+	uc := newTestUsecase(ownedAccounts(1, "acc-1"), unipileClient, &mockChatRepo{}, &mockMessageRepo{})
+
+	sent, err := uc.StartChat(context.Background(), 1, "acc-1", &StartChatRequest{
+		AttendeeProviderIDs: []string{"ACoAA1"},
+		Text:                "Hi there",
+		Subject:             "Opportunity",
+		InMail:              true,
+	})
+	if err != nil {
+		t.Fatalf("StartChat returned error: %v", err)
+	}
+	if received.AccountID != "acc-1" || !received.InMail || received.Subject != "Opportunity" || len(received.AttendeeProviderIDs) != 1 {
+		t.Fatalf("unexpected Unipile request: %+v", received)
+	}
+	if sent.ChatID != "chat-9" || sent.MessageID != "msg-9" {
+		t.Fatalf("unexpected sent message: %+v", sent)
+	}
+}
+
+func TestStartChat_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountRepo *mockAccountRepo
+		req         *StartChatRequest
+		wantKind    errs.Kind
+		wantErr     error
+	}{
+		{
+			name:        "no attendees",
+			accountRepo: ownedAccounts(1, "acc-1"),
+			req:         &StartChatRequest{Text: "Hi"},
+			wantKind:    errs.ValidationErrorKind,
+		},
+		{
+			name:        "inmail on a whatsapp account",
+			accountRepo: accountWithStatus("WHATSAPP", entity.AccountStatusOK),
+			req:         &StartChatRequest{AttendeeProviderIDs: []string{"123@s.whatsapp.net"}, Text: "Hi", InMail: true},
+			wantKind:    errs.ValidationErrorKind,
+		},
+		{
+			name:        "account not connected",
+			accountRepo: accountWithStatus("LINKEDIN", entity.AccountStatusStopped),
+			req:         &StartChatRequest{AttendeeProviderIDs: []string{"ACoAA1"}, Text: "Hi"},
+			wantErr:     errs.ErrAccountNotConnected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unipileClient := &mockUnipileClient{
+				startChatFunc: func(_ context.Context, req *service.StartChatRequest) (*service.StartChatResponse, error) {
+					t.Fatal("expected the chat not to be started")
+					return nil, nil
+				},
+			}
+			uc := newTestUsecase(tt.accountRepo, unipileClient, &mockChatRepo{}, &mockMessageRepo{})
+
+			_, err := uc.StartChat(context.Background(), 1, "acc-1", tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			var codedErr *errs.CodedError
+			if !errors.As(err, &codedErr) || codedErr.Kind != tt.wantKind {
+				t.Fatalf("expected error kind %v, got %v", tt.wantKind, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// syncPageSize is the number of chats or messages fetched per page during a sync
+const syncPageSize = maxPageSize
+
+// unipileTimeLayout is the layout of the dates Unipile accepts as filters
+const unipileTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// SyncChats syncs the chats and messages of the connected accounts from Unipile.
+// The first syncs of an account backfill its whole history, resuming from the saved cursor when interrupted;
+// later syncs fetch the chats with activity since the previous one, catching up on missed webhook events.
+func (a *UsecaseImpl) SyncChats(ctx context.Context) error {
+	accounts, err := a.accountRepo.List(ctx)
+	if err != nil {
+		return errs.WrapInternalError(err, "Failed to list accounts")
+	}
+
+	var synced, failed int
+	for _, account := range accounts {
+		if account.CurrentStatus != entity.AccountStatusOK {
+			continue
+		}
+
+		if err := a.syncAccount(ctx, account); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+			a.logger.WithError(err).WithField("accountID", account.AccountID).Error("Failed to sync chats")
+			continue
+		}
+		synced++
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"accounts": len(accounts),
+		"synced":   synced,
+		"failed":   failed,
+	}).Info("Chat sync completed")
+
+	return nil
+}
+
+// syncAccount syncs the chats of an account and their messages, saving the sync state as it goes
+func (a *UsecaseImpl) syncAccount(ctx context.Context, account *entity.Account) error {
+	state, err := a.chatRepo.GetSyncState(ctx, account.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrChatSyncStateNotFound) {
+			return errs.WrapInternalError(err, "Failed to get chat sync state")
+		}
+		// Saved before the first page, so its creation time marks the start of the backfill
+		state = &entity.ChatSyncState{AccountID: account.ID}
+		if err := a.chatRepo.SaveSyncState(ctx, state); err != nil {
+			return errs.WrapInternalError(err, "Failed to save chat sync state")
+		}
+	}
+
+	startedAt := time.Now().UTC()
+	req := &service.ListChatsRequest{AccountID: account.AccountID, Limit: syncPageSize}
+	var since time.Time
+	if state.IsBackfilled() {
+		since = *state.LastSyncedAt
+		req.After = since.UTC().Format(unipileTimeLayout)
+	} else {
+		req.Cursor = state.Cursor
+	}
+
+	for {
+		resp, err := a.unipileClient.ListChats(ctx, req)
+		if err != nil {
+			return unipileerr.Map(a.logger, err, "Failed to list chats")
+		}
+		for i := range resp.Items {
+			if err := a.syncChat(ctx, account, &resp.Items[i], since); err != nil {
+				return err
+			}
+		}
+
+		next := nextCursor(resp.Cursor, req.Cursor)
+		if !state.IsBackfilled() {
+			// Saved after every page, so an interrupted backfill resumes where it stopped
+			state.Cursor = next
+			if err := a.chatRepo.SaveSyncState(ctx, state); err != nil {
+				return errs.WrapInternalError(err, "Failed to save chat sync state")
+			}
+		}
+		if next == "" || len(resp.Items) == 0 {
+			break
+		}
+		req.Cursor = next
+	}
+
+	if !state.IsBackfilled() {
+		// Chats that changed while an interrupted backfill was paused are caught up by the next sync
+		backfillStartedAt := state.CreatedAt.UTC()
+		state.BackfilledAt = &startedAt
+		state.LastSyncedAt = &backfillStartedAt
+	} else {
+		state.LastSyncedAt = &startedAt
+	}
+	if err := a.chatRepo.SaveSyncState(ctx, state); err != nil {
+		return errs.WrapInternalError(err, "Failed to save chat sync state")
+	}
+	return nil
+}
+
+// syncChat stores a chat and its messages sent after since, all of them when since is zero.
+// Attendees are only fetched for new chats and chats whose attendee changed, the stored names are kept otherwise.
+func (a *UsecaseImpl) syncChat(ctx context.Context, account *entity.Account, unipileChat *service.Chat, since time.Time) error {
+	chat := chatFromUnipile(account, unipileChat)
+	stored, err := a.chatRepo.GetByAccountIDAndChatID(ctx, account.ID, unipileChat.ID)
+	if err != nil && !errors.Is(err, repository.ErrChatNotFound) {
+		return errs.WrapInternalError(err, "Failed to get chat")
+	}
+	if stored != nil && stored.AttendeeProviderID == chat.AttendeeProviderID && stored.Name == chat.Name {
+		chat.AttendeeNames = stored.AttendeeNames
+	} else if chat.AttendeeNames, err = a.chatAttendeeNames(ctx, unipileChat.ID); err != nil {
+		return err
+	}
+	if err := a.chatRepo.Upsert(ctx, chat); err != nil {
+		return errs.WrapInternalError(err, "Failed to save chat")
+	}
+
+	req := &service.ListMessagesRequest{ChatID: unipileChat.ID, Limit: syncPageSize}
+	if !since.IsZero() {
+		req.After = since.UTC().Format(unipileTimeLayout)
+	}
+	for {
+		resp, err := a.unipileClient.ListMessages(ctx, req)
+		if err != nil {
+			return unipileerr.Map(a.logger, err, "Failed to list messages")
+		}
+		for i := range resp.Items {
+			if err := a.messageRepo.Upsert(ctx, messageFromUnipile(chat, &resp.Items[i])); err != nil {
+				return errs.WrapInternalError(err, "Failed to save message")
+			}
+		}
+
+		next := nextCursor(resp.Cursor, req.Cursor)
+		if next == "" || len(resp.Items) == 0 {
+			return nil
+		}
+		req.Cursor = next
+	}
+}
+
+// chatAttendeeNames returns the names of the attendees of a chat other than the account user, comma separated
+func (a *UsecaseImpl) chatAttendeeNames(ctx context.Context, chatID string) (string, error) {
+	var names []string
+	req := &service.ListChatAttendeesRequest{ChatID: chatID, Limit: syncPageSize}
+	for {
+		resp, err := a.unipileClient.ListChatAttendees(ctx, req)
+		if err != nil {
+			return "", unipileerr.Map(a.logger, err, "Failed to list chat attendees")
+		}
+		for _, attendee := range resp.Items {
+			if attendee.IsSelf == 0 && attendee.Name != "" {
+				names = append(names, attendee.Name)
+			}
+		}
+
+		next := nextCursor(resp.Cursor, req.Cursor)
+		if next == "" || len(resp.Items) == 0 {
+			return strings.Join(names, ", "), nil
+		}
+		req.Cursor = next
+	}
+}
+
+// chatFromUnipile maps a Unipile chat to the chat stored for the account
+func chatFromUnipile(account *entity.Account, chat *service.Chat) *entity.Chat {
+	return &entity.Chat{
+		AccountID:          account.ID,
+		ChatID:             chat.ID,
+		ProviderID:         chat.ProviderID,
+		AttendeeProviderID: chat.AttendeeProviderID,
+		Name:               chat.Name,
+		Type:               chat.Type,
+		Subject:            chat.Subject,
+		ContentType:        chat.ContentType,
+		UnreadCount:        chat.UnreadCount,
+		Archived:           chat.Archived != 0,
+		ReadOnly:           chat.ReadOnly != 0,
+		LastMessageAt:      parseTimestamp(chat.Timestamp),
+	}
+}
+
+// messageFromUnipile maps a Unipile message to the message stored in the chat
+func messageFromUnipile(chat *entity.Chat, message *service.Message) *entity.Message {
+	var attachments json.RawMessage
+	if len(message.Attachments) > 0 {
+		// Marshalling plain structs cannot fail
+		attachments, _ = json.Marshal(message.Attachments)
+	}
+	return &entity.Message{
+		AccountID:        chat.AccountID,
+		ChatID:           chat.ID,
+		MessageID:        message.ID,
+		ProviderID:       message.ProviderID,
+		SenderID:         message.SenderID,
+		SenderAttendeeID: message.SenderAttendeeID,
+		Text:             message.Text,
+		SentAt:           parseTimestamp(message.Timestamp),
+		IsSender:         message.IsSender != 0,
+		Seen:             message.Seen != 0,
+		Edited:           message.Edited != 0,
+		Deleted:          message.Deleted != 0,
+		Attachments:      attachments,
+	}
+}
+
+// parseTimestamp parses a Unipile date, returning the zero time when it is missing or malformed
+func parseTimestamp(timestamp string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed.UTC()
+}
@@ -0,0 +1,246 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// accounts returns a repository listing the given accounts
+func accounts(list ...*entity.Account) *mockAccountRepo {
+	return &mockAccountRepo{
+		listFunc: func(_ context.Context) ([]*entity.Account, error) {
+			return list, nil
+		},
+	}
+}
+
+// recordingChatRepo returns a chat repository recording the upserted chats and saved sync states
+func recordingChatRepo(state *entity.ChatSyncState, chats *[]*entity.Chat, states *[]entity.ChatSyncState) *mockChatRepo {
+	return &mockChatRepo{
+		upsertFunc: func(_ context.Context, chat *entity.Chat) error {
+			chat.ID = uint(len(*chats) + 1)
+			*chats = append(*chats, chat)
+			return nil
+		},
+		getSyncStateFunc: func(_ context.Context, accountID uint) (*entity.ChatSyncState, error) {
+			if state == nil {
+				return nil, repository.ErrChatSyncStateNotFound
+			}
+			return state, nil
+		},
+		saveSyncStateFunc: func(_ context.Context, saved *entity.ChatSyncState) error {
+			if saved.CreatedAt.IsZero() {
+				saved.CreatedAt = time.Now()
+			}
+			*states = append(*states, *saved)
+			return nil
+		},
+	}
+}
+
+func TestSyncChats_Backfill(t *testing.T) {
+	accountRepo := accounts(
+		&entity.Account{ID: 7, AccountID: "acc-1", CurrentStatus: entity.AccountStatusOK},
+		&entity.Account{ID: 8, AccountID: "acc-2", CurrentStatus: entity.AccountStatusCredentials},
+	)
+
+	var chatRequests []service.ListChatsRequest
+	unipileClient := &mockUnipileClient{
+		listChatsFunc: func(_ context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+			chatRequests = append(chatRequests, *req)
+			if req.AccountID != "acc-1" {
+				t.Fatalf("expected only connected accounts to be synced, got %s", req.AccountID)
+			}
+			if req.Cursor == "" {
+				return &service.ChatListResponse{Items: []service.Chat{{ID: "chat-1", UnreadCount: 1, Timestamp: "2025-01-01T10:00:00.000Z"}}, Cursor: stringPtr("page-2")}, nil
+			}
+			return &service.ChatListResponse{Items: []service.Chat{{ID: "chat-2", Timestamp: "2025-01-01T09:00:00.000Z"}}}, nil
+		},
+		listMessagesFunc: func(_ context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error) {
+			if req.After != "" {
+				t.Fatalf("expected the backfill to fetch the whole history, got after %s", req.After)
+			}
+			return &service.MessageListResponse{Items: []service.Message{{ID: "msg-" + req.ChatID, Text: "Hello", IsSender: 1, Timestamp: "2025-01-01T08:00:00.000Z"}}}, nil
+		},
+		listChatAttendeesFunc: func(_ context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+			return &service.ChatAttendeeListResponse{Items: []service.ChatAttendee{
+				{Name: "Me", IsSelf: 1},
+				{Name: "Jane Doe"},
+				{Name: "John Smith"},
+			}}, nil
+		},
+	}
+
+	var chats []*entity.Chat
+	var states []entity.ChatSyncState
+	chatRepo := recordingChatRepo(nil, &chats, &states)
+	var messages []*entity.Message
+	messageRepo := &mockMessageRepo{
+		upsertFunc: func(_ context.Context, message *entity.Message) error {
+			messages = append(messages, message)
+			return nil
+		},
+	}
+
+	uc := newTestUsecase(accountRepo, unipileClient, chatRepo, messageRepo)
+	if err := uc.SyncChats(context.Background()); err != nil {
+		t.Fatalf("SyncChats returned error: %v", err)
+	}
+
+	if len(chatRequests) != 2 || chatRequests[1].Cursor != "page-2" {
+		t.Fatalf("unexpected chat requests: %+v", chatRequests)
+	}
+	if len(chats) != 2 || chats[0].AccountID != 7 || chats[0].UnreadCount != 1 || chats[0].AttendeeNames != "Jane Doe, John Smith" || !chats[0].LastMessageAt.Equal(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected chats: %+v", chats)
+	}
+	if len(messages) != 2 || messages[1].MessageID != "msg-chat-2" || messages[1].ChatID != 2 || !messages[1].IsSender {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+
+	// The cursor is saved after every page, then the state is marked backfilled
+	if len(states) != 4 || states[1].Cursor != "page-2" {
+		t.Fatalf("unexpected sync states: %+v", states)
+	}
+	last := states[len(states)-1]
+	if !last.IsBackfilled() || last.Cursor != "" || last.LastSyncedAt == nil || !last.LastSyncedAt.Equal(states[0].CreatedAt.UTC()) {
+		t.Fatalf("unexpected final sync state: %+v", last)
+	}
+}
+
+func TestSyncChats_ResumesBackfill(t *testing.T) {
+	var chatRequests []service.ListChatsRequest
+	unipileClient := &mockUnipileClient{
+		listChatsFunc: func(_ context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+			chatRequests = append(chatRequests, *req)
+			return &service.ChatListResponse{}, nil
+		},
+	}
+
+	var chats []*entity.Chat
+	var states []entity.ChatSyncState
+	state := &entity.ChatSyncState{ID: 1, AccountID: 7, Cursor: "page-5", CreatedAt: time.Now().Add(-time.Hour)}
+	uc := newTestUsecase(accounts(&entity.Account{ID: 7, AccountID: "acc-1", CurrentStatus: entity.AccountStatusOK}), unipileClient, recordingChatRepo(state, &chats, &states), &mockMessageRepo{})
+
+	if err := uc.SyncChats(context.Background()); err != nil {
+		t.Fatalf("SyncChats returned error: %v", err)
+	}
+	if len(chatRequests) != 1 || chatRequests[0].Cursor != "page-5" || chatRequests[0].After != "" {
+		t.Fatalf("unexpected chat requests: %+v", chatRequests)
+	}
+	if !state.IsBackfilled() {
+		t.Fatalf("expected the backfill to complete, got %+v", state)
+	}
+}
+
+func TestSyncChats_Incremental(t *testing.T) {
+	lastSyncedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	backfilledAt := lastSyncedAt
+	state := &entity.ChatSyncState{ID: 1, AccountID: 7, BackfilledAt: &backfilledAt, LastSyncedAt: &lastSyncedAt}
+
+	var chatRequest *service.ListChatsRequest
+	var messageRequest *service.ListMessagesRequest
+	unipileClient := &mockUnipileClient{
+		listChatsFunc: func(_ context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+			chatRequest = req
+			return &service.ChatListResponse{Items: []service.Chat{{ID: "chat-1", Timestamp: "2025-01-01T11:00:00.000Z"}}}, nil
+		},
+		listMessagesFunc: func(_ context.Context, req *service.ListMessagesRequest) (*service.MessageListResponse, error) {
+			messageRequest = req
+			return &service.MessageListResponse{}, nil
+		},
+	}
+
+	var chats []*entity.Chat
+	var states []entity.ChatSyncState
+	uc := newTestUsecase(accounts(&entity.Account{ID: 7, AccountID: "acc-1", CurrentStatus: entity.AccountStatusOK}), unipileClient, recordingChatRepo(state, &chats, &states), &mockMessageRepo{})
+
+	if err := uc.SyncChats(context.Background()); err != nil {
+		t.Fatalf("SyncChats returned error: %v", err)
+	}
+	if chatRequest.After != "2025-01-01T10:00:00.000Z" || chatRequest.Cursor != "" {
+		t.Fatalf("unexpected chat request: %+v", chatRequest)
+	}
+	if messageRequest.After != "2025-01-01T10:00:00.000Z" {
+		t.Fatalf("unexpected message request: %+v", messageRequest)
+	}
+	if len(states) != 1 || !states[0].LastSyncedAt.After(lastSyncedAt) {
+		t.Fatalf("unexpected sync states: %+v", states)
+	}
+}
+
+func TestSyncChats_ReusesStoredAttendees(t *testing.T) {
+	lastSyncedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	state := &entity.ChatSyncState{ID: 1, AccountID: 7, BackfilledAt: &lastSyncedAt, LastSyncedAt: &lastSyncedAt}
+
+	var attendeeRequests []string
+	unipileClient := &mockUnipileClient{
+		listChatsFunc: func(_ context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+			return &service.ChatListResponse{Items: []service.Chat{
+				{ID: "chat-same", Name: "Jane Doe", AttendeeProviderID: "ACoJane"},
+				{ID: "chat-changed", Name: "Project", AttendeeProviderID: "ACoJohn"},
+				{ID: "chat-new", Name: "Alice", AttendeeProviderID: "ACoAlice"},
+			}}, nil
+		},
+		listChatAttendeesFunc: func(_ context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+			attendeeRequests = append(attendeeRequests, req.ChatID)
+			return &service.ChatAttendeeListResponse{Items: []service.ChatAttendee{{Name: "Fetched"}}}, nil
+		},
+	}
+
+	var chats []*entity.Chat
+	var states []entity.ChatSyncState
+	chatRepo := recordingChatRepo(state, &chats, &states)
+	chatRepo.getByAccountIDAndChatIDFunc = func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+		switch chatID {
+		case "chat-same":
+			return &entity.Chat{ID: 1, ChatID: chatID, Name: "Jane Doe", AttendeeProviderID: "ACoJane", AttendeeNames: "Jane Doe"}, nil
+		case "chat-changed":
+			return &entity.Chat{ID: 2, ChatID: chatID, Name: "Project", AttendeeProviderID: "ACoJack", AttendeeNames: "Jack"}, nil
+		}
+		return nil, repository.ErrChatNotFound
+	}
+	uc := newTestUsecase(accounts(&entity.Account{ID: 7, AccountID: "acc-1", CurrentStatus: entity.AccountStatusOK}), unipileClient, chatRepo, &mockMessageRepo{})
+
+	if err := uc.SyncChats(context.Background()); err != nil {
+		t.Fatalf("SyncChats returned error: %v", err)
+	}
+	if len(attendeeRequests) != 2 || attendeeRequests[0] != "chat-changed" || attendeeRequests[1] != "chat-new" {
+		t.Fatalf("expected attendees of new and changed chats only, got %v", attendeeRequests)
+	}
+	if len(chats) != 3 || chats[0].AttendeeNames != "Jane Doe" || chats[1].AttendeeNames != "Fetched" || chats[2].AttendeeNames != "Fetched" {
+		t.Fatalf("unexpected chats: %+v", chats)
+	}
+}
+
+func TestSyncChats_AccountFailureDoesNotStopOthers(t *testing.T) {
+	var synced []string
+	unipileClient := &mockUnipileClient{
+		listChatsFunc: func(_ context.Context, req *service.ListChatsRequest) (*service.ChatListResponse, error) {
+			if req.AccountID == "acc-1" {
+				return nil, errors.New("boom")
+			}
+			synced = append(synced, req.AccountID)
+			return &service.ChatListResponse{}, nil
+		},
+	}
+
+	var chats []*entity.Chat
+	var states []entity.ChatSyncState
+	uc := newTestUsecase(accounts(
+		&entity.Account{ID: 7, AccountID: "acc-1", CurrentStatus: entity.AccountStatusOK},
+		&entity.Account{ID: 8, AccountID: "acc-2", CurrentStatus: entity.AccountStatusOK},
+	), unipileClient, recordingChatRepo(nil, &chats, &states), &mockMessageRepo{})
+
+	if err := uc.SyncChats(context.Background()); err != nil {
+		t.Fatalf("SyncChats returned error: %v", err)
+	}
+	if len(synced) != 1 || synced[0] != "acc-2" {
+		t.Fatalf("expected acc-2 to be synced, got %v", synced)
+	}
+}
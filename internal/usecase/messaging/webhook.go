@@ -0,0 +1,201 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// Unipile messaging webhook events
+const (
+	MessageEventReceived = "message_received"
+	MessageEventEdited   = "message_edited"
+	MessageEventDeleted  = "message_deleted"
+)
+
+// MessageEvent represents a message event delivered by a Unipile messaging webhook
+type MessageEvent struct {
+	Event            string          `json:"event"`
+	AccountID        string          `json:"account_id"`
+	AccountUserID    string          `json:"account_user_id"` // Provider ID of the account user, tells whether the account sent the message
+	ChatID           string          `json:"chat_id"`
+	MessageID        string          `json:"message_id"`
+	Text             string          `json:"text"`
+	Timestamp        string          `json:"timestamp"`
+	SenderAttendeeID string          `json:"sender_attendee_id"`
+	SenderProviderID string          `json:"sender_provider_id"`
+	Attachments      json.RawMessage `json:"attachments"`
+}
+
+// errChatNotStored is returned by storeMessageEvent when the chat of the event was not synced yet
+var errChatNotStored = errors.New("chat not stored")
+
+// IsMessageEvent reports whether HandleMessageEvent applies the event, other events are ignored
+func IsMessageEvent(event string) bool {
+	switch event {
+	case MessageEventReceived, MessageEventEdited, MessageEventDeleted:
+		return true
+	}
+	return false
+}
+
+// HandleMessageEvent applies a message event to the synced chats of the matching account.
+// Events of accounts that are not stored and unsupported events are ignored.
+// It returns the stored message, or nil when the event was ignored.
+func (a *UsecaseImpl) HandleMessageEvent(ctx context.Context, event *MessageEvent) (*entity.Message, error) {
+	logFields := logrus.Fields{
+		"accountID": event.AccountID,
+		"chatID":    event.ChatID,
+		"event":     event.Event,
+	}
+
+	if !IsMessageEvent(event.Event) {
+		a.logger.WithFields(logFields).Debug("Ignoring unsupported message event")
+		return nil, nil
+	}
+
+	message, account, err := a.storeMessageEvent(ctx, event, nil)
+	if errors.Is(err, errChatNotStored) {
+		// Fetched without holding the account lock, the event is then stored with the fetched chat
+		var chat *entity.Chat
+		chat, err = a.fetchChat(ctx, account, event.ChatID)
+		if err == nil {
+			message, _, err = a.storeMessageEvent(ctx, event, chat)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, errs.ErrChatNotFound) {
+			a.logger.WithFields(logFields).Info("Ignoring message event for unknown chat")
+			return nil, nil
+		}
+		return nil, err
+	}
+	if account == nil {
+		a.logger.WithFields(logFields).Info("Ignoring message event for unknown account")
+	}
+	return message, nil
+}
+
+// storeMessageEvent stores the message of an event in its chat while holding the account lock.
+// fetched is the chat fetched from Unipile, stored when the chat was not synced yet;
+// without it errChatNotStored is returned along with the account.
+// The returned account is nil when the account is not stored.
+func (a *UsecaseImpl) storeMessageEvent(ctx context.Context, event *MessageEvent, fetched *entity.Chat) (*entity.Message, *entity.Account, error) {
+	var message *entity.Message
+	var account *entity.Account
+	if err := a.txRepo.Do(ctx, func(repos *repository.Repositories) error {
+		// Locking the account serializes the events of its chats, so unread counts are not lost
+		locked, err := repos.Account.GetByAccountIDForUpdate(ctx, event.AccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return nil
+			}
+			return errs.WrapInternalError(err, "Failed to get account")
+		}
+		account = locked
+
+		chat, err := repos.Chat.GetByAccountIDAndChatID(ctx, account.ID, event.ChatID)
+		switch {
+		case err == nil:
+			// Synced meanwhile, the fetched chat is not needed
+			fetched = nil
+		case !errors.Is(err, repository.ErrChatNotFound):
+			return errs.WrapInternalError(err, "Failed to get chat")
+		case fetched == nil:
+			return errChatNotStored
+		default:
+			chat = fetched
+			if err := repos.Chat.Upsert(ctx, chat); err != nil {
+				return errs.WrapInternalError(err, "Failed to save chat")
+			}
+		}
+
+		existing, err := repos.Message.GetByChatIDAndMessageID(ctx, chat.ID, event.MessageID)
+		if err != nil && !errors.Is(err, repository.ErrMessageNotFound) {
+			return errs.WrapInternalError(err, "Failed to get message")
+		}
+
+		message = applyMessageEvent(chat, existing, event)
+		if message == nil {
+			return nil
+		}
+		if err := repos.Message.Upsert(ctx, message); err != nil {
+			return errs.WrapInternalError(err, "Failed to save message")
+		}
+
+		// A chat fetched from Unipile already accounts for the message
+		if existing != nil || fetched != nil || event.Event != MessageEventReceived {
+			return nil
+		}
+		if message.SentAt.After(chat.LastMessageAt) {
+			chat.LastMessageAt = message.SentAt
+		}
+		if !message.IsSender {
+			chat.UnreadCount++
+		}
+		if err := repos.Chat.Update(ctx, chat); err != nil {
+			return errs.WrapInternalError(err, "Failed to update chat")
+		}
+		return nil
+	}); err != nil {
+		return nil, account, err
+	}
+	return message, account, nil
+}
+
+// fetchChat fetches a chat that was not synced yet from Unipile, with the names of its attendees
+func (a *UsecaseImpl) fetchChat(ctx context.Context, account *entity.Account, chatID string) (*entity.Chat, error) {
+	unipileChat, err := a.unipileClient.GetChat(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileChatNotFound) {
+			return nil, errs.ErrChatNotFound
+		}
+		return nil, unipileerr.Map(a.logger, err, "Failed to get chat")
+	}
+	chat := chatFromUnipile(account, unipileChat)
+	if chat.AttendeeNames, err = a.chatAttendeeNames(ctx, chatID); err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
+
+// applyMessageEvent returns the message updated by the event, nil when there is nothing to store
+func applyMessageEvent(chat *entity.Chat, existing *entity.Message, event *MessageEvent) *entity.Message {
+	message := existing
+	if message == nil {
+		if event.Event == MessageEventDeleted {
+			return nil
+		}
+		message = &entity.Message{
+			AccountID:        chat.AccountID,
+			ChatID:           chat.ID,
+			MessageID:        event.MessageID,
+			SenderAttendeeID: event.SenderAttendeeID,
+			SenderID:         event.SenderProviderID,
+			SentAt:           parseTimestamp(event.Timestamp),
+			IsSender:         event.SenderProviderID != "" && event.SenderProviderID == event.AccountUserID,
+		}
+	}
+
+	switch event.Event {
+	case MessageEventReceived:
+		message.Text = event.Text
+		if len(event.Attachments) > 0 && string(event.Attachments) != "null" {
+			message.Attachments = event.Attachments
+		}
+	case MessageEventEdited:
+		message.Text = event.Text
+		message.Edited = true
+	case MessageEventDeleted:
+		message.Deleted = true
+	}
+	return message
+}
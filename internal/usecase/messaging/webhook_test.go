@@ -0,0 +1,196 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// webhookAccounts returns a repository storing acc-1
+func webhookAccounts() *mockAccountRepo {
+	return &mockAccountRepo{
+		getByAccountIDForUpdateFunc: func(_ context.Context, accountID string) (*entity.Account, error) {
+			if accountID != "acc-1" {
+				return nil, repository.ErrAccountNotFound
+			}
+			return &entity.Account{ID: 7, AccountID: accountID, CurrentStatus: entity.AccountStatusOK}, nil
+		},
+	}
+}
+
+func receivedEvent() *MessageEvent {
+	return &MessageEvent{
+		Event:            MessageEventReceived,
+		AccountID:        "acc-1",
+		AccountUserID:    "ACoSelf",
+		ChatID:           "chat-1",
+		MessageID:        "msg-1",
+		Text:             "Hello",
+		Timestamp:        "2025-01-02T10:00:00.000Z",
+		SenderAttendeeID: "att-1",
+		SenderProviderID: "ACoJane",
+	}
+}
+
+func TestHandleMessageEvent_Received(t *testing.T) {
+	chat := &entity.Chat{ID: 11, AccountID: 7, ChatID: "chat-1", UnreadCount: 1, LastMessageAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	var updated *entity.Chat
+	chatRepo := &mockChatRepo{
+		getByAccountIDAndChatIDFunc: func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+			return chat, nil
+		},
+		updateFunc: func(_ context.Context, c *entity.Chat) error {
+			updated = c
+			return nil
+		},
+	}
+	var upserted *entity.Message
+	messageRepo := &mockMessageRepo{
+		upsertFunc: func(_ context.Context, message *entity.Message) error {
+			upserted = message
+			return nil
+		},
+	}
+	uc := newTestUsecase(webhookAccounts(), &mockUnipileClient{}, chatRepo, messageRepo)
+
+	message, err := uc.HandleMessageEvent(context.Background(), receivedEvent())
+	if err != nil {
+		t.Fatalf("HandleMessageEvent returned error: %v", err)
+	}
+	if message == nil || upserted != message {
+		t.Fatalf("expected the message to be stored, got %+v", upserted)
+	}
+	if message.ChatID != 11 || message.AccountID != 7 || message.Text != "Hello" || message.IsSender {
+		t.Fatalf("unexpected message: %+v", message)
+	}
+	if updated == nil || updated.UnreadCount != 2 || !updated.LastMessageAt.Equal(time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected chat update: %+v", updated)
+	}
+}
+
+func TestHandleMessageEvent_Duplicate(t *testing.T) {
+	chatRepo := &mockChatRepo{
+		getByAccountIDAndChatIDFunc: func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+			return &entity.Chat{ID: 11, AccountID: 7, ChatID: chatID}, nil
+		},
+		updateFunc: func(_ context.Context, c *entity.Chat) error {
+			t.Fatal("expected a duplicate delivery not to update the chat")
+			return nil
+		},
+	}
+	messageRepo := &mockMessageRepo{
+		getByChatIDAndMessageIDFunc: func(_ context.Context, chatID uint, messageID string) (*entity.Message, error) {
+			return &entity.Message{ID: 5, ChatID: chatID, MessageID: messageID, Text: "Hello"}, nil
+		},
+	}
+	uc := newTestUsecase(webhookAccounts(), &mockUnipileClient{}, chatRepo, messageRepo)
+
+	if _, err := uc.HandleMessageEvent(context.Background(), receivedEvent()); err != nil {
+		t.Fatalf("HandleMessageEvent returned error: %v", err)
+	}
+}
+
+func TestHandleMessageEvent_ChatNotSynced(t *testing.T) {
+	var upsertedChat *entity.Chat
+	chatRepo := &mockChatRepo{
+		upsertFunc: func(_ context.Context, chat *entity.Chat) error {
+			chat.ID = 12
+			upsertedChat = chat
+			return nil
+		},
+		updateFunc: func(_ context.Context, c *entity.Chat) error {
+			t.Fatal("expected the fetched chat not to count the message twice")
+			return nil
+		},
+	}
+	accountRepo := webhookAccounts()
+	txRepo := &mockTxRepo{repos: repository.Repositories{Account: accountRepo, Chat: chatRepo, Message: &mockMessageRepo{}}}
+	unipileClient := &mockUnipileClient{
+		getChatFunc: func(_ context.Context, chatID string) (*service.Chat, error) {
+			if txRepo.inTx {
+				t.Fatal("expected the chat to be fetched without holding the account lock")
+			}
+			return &service.Chat{ID: chatID, AccountID: "acc-1", UnreadCount: 1, Timestamp: "2025-01-02T10:00:00.000Z"}, nil
+		},
+		listChatAttendeesFunc: func(_ context.Context, req *service.ListChatAttendeesRequest) (*service.ChatAttendeeListResponse, error) {
+			if txRepo.inTx {
+				t.Fatal("expected the attendees to be fetched without holding the account lock")
+			}
+			return &service.ChatAttendeeListResponse{Items: []service.ChatAttendee{{Name: "Jane Doe"}}}, nil
+		},
+	}
+	uc := NewMessagingUsecase(txRepo, accountRepo, chatRepo, &mockMessageRepo{}, unipileClient, logrus.New())
+
+	message, err := uc.HandleMessageEvent(context.Background(), receivedEvent())
+	if err != nil {
+		t.Fatalf("HandleMessageEvent returned error: %v", err)
+	}
+	if upsertedChat == nil || upsertedChat.AccountID != 7 || upsertedChat.UnreadCount != 1 || upsertedChat.AttendeeNames != "Jane Doe" || message.ChatID != 12 {
+		t.Fatalf("unexpected chat %+v for message %+v", upsertedChat, message)
+	}
+}
+
+func TestHandleMessageEvent_Ignored(t *testing.T) {
+	tests := []struct {
+		name  string
+		event func(e *MessageEvent)
+	}{
+		{name: "unsupported event", event: func(e *MessageEvent) { e.Event = "message_reaction" }},
+		{name: "unknown account", event: func(e *MessageEvent) { e.AccountID = "acc-9" }},
+		{name: "deleted message never synced", event: func(e *MessageEvent) { e.Event = MessageEventDeleted }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatRepo := &mockChatRepo{
+				getByAccountIDAndChatIDFunc: func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+					return &entity.Chat{ID: 11, AccountID: accountID, ChatID: chatID}, nil
+				},
+			}
+			messageRepo := &mockMessageRepo{
+				upsertFunc: func(_ context.Context, message *entity.Message) error {
+					t.Fatal("expected no message to be stored")
+					return nil
+				},
+			}
+			uc := newTestUsecase(webhookAccounts(), &mockUnipileClient{}, chatRepo, messageRepo)
+
+			event := receivedEvent()
+			tt.event(event)
+			message, err := uc.HandleMessageEvent(context.Background(), event)
+			if err != nil || message != nil {
+				t.Fatalf("expected the event to be ignored, got %+v, %v", message, err)
+			}
+		})
+	}
+}
+
+func TestHandleMessageEvent_Edited(t *testing.T) {
+	chatRepo := &mockChatRepo{
+		getByAccountIDAndChatIDFunc: func(_ context.Context, accountID uint, chatID string) (*entity.Chat, error) {
+			return &entity.Chat{ID: 11, AccountID: accountID, ChatID: chatID}, nil
+		},
+	}
+	messageRepo := &mockMessageRepo{
+		getByChatIDAndMessageIDFunc: func(_ context.Context, chatID uint, messageID string) (*entity.Message, error) {
+			return &entity.Message{ID: 5, ChatID: chatID, MessageID: messageID, Text: "Helo"}, nil
+		},
+	}
+	uc := newTestUsecase(webhookAccounts(), &mockUnipileClient{}, chatRepo, messageRepo)
+
+	event := receivedEvent()
+	event.Event = MessageEventEdited
+	message, err := uc.HandleMessageEvent(context.Background(), event)
+	if err != nil {
+		t.Fatalf("HandleMessageEvent returned error: %v", err)
+	}
+	if message.ID != 5 || message.Text != "Hello" || !message.Edited {
+		t.Fatalf("unexpected message: %+v", message)
+	}
+}
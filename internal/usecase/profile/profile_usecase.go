@@ -0,0 +1,131 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+	"unipile-connector/internal/usecase/internal/unipileerr"
+)
+
+// Usecase handles user profile lookups
+type Usecase interface {
+	GetProfile(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error)
+}
+
+// UsecaseImpl handles user profile lookups
+type UsecaseImpl struct {
+	accountRepo   repository.AccountRepository
+	profileRepo   repository.ProfileRepository
+	unipileClient service.UnipileClient
+	cacheTTL      time.Duration
+	logger        *logrus.Logger
+}
+
+// NewProfileUsecase creates a new profile usecase, looked up profiles are cached for cacheTTL
+func NewProfileUsecase(accountRepo repository.AccountRepository, profileRepo repository.ProfileRepository, unipileClient service.UnipileClient, cacheTTL time.Duration, logger *logrus.Logger) Usecase {
+	return &UsecaseImpl{
+		accountRepo:   accountRepo,
+		profileRepo:   profileRepo,
+		unipileClient: unipileClient,
+		cacheTTL:      cacheTTL,
+		logger:        logger,
+	}
+}
+
+// GetProfile gets the profile of a user as seen by an account of the user.
+// Profiles fetched less than the cache TTL ago are served from the local cache, whether they are looked up
+// by provider ID or by public identifier, so repeat lookups do not spend the provider quota of the account.
+func (a *UsecaseImpl) GetProfile(ctx context.Context, userID uint, accountID, identifier string) (*entity.Profile, error) {
+	identifier = normalizeIdentifier(identifier)
+	if identifier == "" {
+		return nil, errs.WrapValidationError(errors.New("identifier is required"), "Invalid profile identifier")
+	}
+
+	account, err := a.accountRepo.GetByUserIDAndAccountID(ctx, userID, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, errs.ErrAccountNotFound
+		}
+		return nil, errs.WrapInternalError(err, "Failed to get account")
+	}
+
+	cached, err := a.profileRepo.GetByAccountIDAndIdentifier(ctx, account.ID, identifier)
+	if err != nil && !errors.Is(err, repository.ErrProfileNotFound) {
+		return nil, errs.WrapInternalError(err, "Failed to get cached profile")
+	}
+	now := time.Now().UTC()
+	if cached != nil && cached.IsFresh(now, a.cacheTTL) {
+		return cached, nil
+	}
+
+	if account.CurrentStatus != entity.AccountStatusOK {
+		return nil, errs.ErrAccountNotConnected
+	}
+	userProfile, err := a.unipileClient.GetUserProfile(ctx, account.AccountID, identifier)
+	if err != nil {
+		if errors.Is(err, service.ErrUnipileUserNotFound) {
+			return nil, a.userNotFound(ctx, account)
+		}
+		return nil, unipileerr.Map(a.logger, err, "Failed to get user profile")
+	}
+
+	profile := profileFromUnipile(account, userProfile)
+	profile.FetchedAt = now
+	if profile.ProviderID == "" {
+		// Cannot be cached without the key identifying the user
+		return profile, nil
+	}
+	if err := a.profileRepo.Upsert(ctx, profile); err != nil {
+		return nil, errs.WrapInternalError(err, "Failed to cache profile")
+	}
+	return profile, nil
+}
+
+// userNotFound returns the error of a profile lookup Unipile answered with not found.
+// Unipile answers the same for an account it does not know, which is reported as such rather than as a missing profile.
+func (a *UsecaseImpl) userNotFound(ctx context.Context, account *entity.Account) error {
+	_, err := a.unipileClient.GetAccount(ctx, account.AccountID)
+	switch {
+	case err == nil:
+		return errs.ErrProfileNotFound
+	case errors.Is(err, service.ErrUnipileAccountNotFound):
+		return errs.ErrAccountNotFound
+	default:
+		return unipileerr.Map(a.logger, err, "Failed to get account from Unipile")
+	}
+}
+
+// normalizeIdentifier trims the spaces and slashes copied along with a provider ID or a public identifier
+func normalizeIdentifier(identifier string) string {
+	return strings.Trim(strings.TrimSpace(identifier), "/")
+}
+
+// profileFromUnipile maps a Unipile user profile to the profile cached for the account
+func profileFromUnipile(account *entity.Account, userProfile *service.UserProfile) *entity.Profile {
+	provider := userProfile.Provider
+	if provider == "" {
+		provider = account.Provider
+	}
+	return &entity.Profile{
+		AccountID:         account.ID,
+		Provider:          provider,
+		ProviderID:        userProfile.ProviderID,
+		PublicIdentifier:  userProfile.PublicIdentifier,
+		FirstName:         userProfile.FirstName,
+		LastName:          userProfile.LastName,
+		Headline:          userProfile.Headline,
+		Company:           userProfile.CurrentCompany(),
+		Location:          userProfile.Location,
+		ProfilePictureURL: userProfile.ProfilePictureURL,
+		PublicProfileURL:  userProfile.PublicProfileURL,
+		NetworkDistance:   userProfile.NetworkDistance,
+	}
+}
@@ -0,0 +1,194 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"unipile-connector/internal/domain/entity"
+	"unipile-connector/internal/domain/errs"
+	"unipile-connector/internal/domain/repository"
+	"unipile-connector/internal/domain/service"
+)
+
+// mockAccountRepo implements the account lookups of AccountRepository, other methods panic
+type mockAccountRepo struct {
+	repository.AccountRepository
+	account *entity.Account // Account of user 1, nil when the user has none
+}
+
+func (m *mockAccountRepo) GetByUserIDAndAccountID(_ context.Context, userID uint, accountID string) (*entity.Account, error) {
+	if m.account == nil || userID != m.account.UserID || accountID != m.account.AccountID {
+		return nil, repository.ErrAccountNotFound
+	}
+	return m.account, nil
+}
+
+// mockProfileRepo implements ProfileRepository in memory, profiles are keyed by provider ID
+type mockProfileRepo struct {
+	profiles map[string]*entity.Profile
+	upserts  int
+}
+
+func (m *mockProfileRepo) GetByAccountIDAndIdentifier(_ context.Context, _ uint, identifier string) (*entity.Profile, error) {
+	for _, profile := range m.profiles {
+		if profile.ProviderID == identifier || strings.EqualFold(profile.PublicIdentifier, identifier) {
+			return profile, nil
+		}
+	}
+	return nil, repository.ErrProfileNotFound
+}
+
+func (m *mockProfileRepo) Upsert(_ context.Context, profile *entity.Profile) error {
+	if m.profiles == nil {
+		m.profiles = make(map[string]*entity.Profile)
+	}
+	m.profiles[profile.ProviderID] = profile
+	m.upserts++
+	return nil
+}
+
+// mockUnipileClient implements the user and account calls of the Unipile client, other methods panic
+type mockUnipileClient struct {
+	service.UnipileClient
+	getUserProfileFunc func(ctx context.Context, accountID, identifier string) (*service.UserProfile, error)
+	getAccountFunc     func(ctx context.Context, accountID string) (*service.Account, error)
+	calls              int
+}
+
+func (m *mockUnipileClient) GetUserProfile(ctx context.Context, accountID, identifier string) (*service.UserProfile, error) {
+	m.calls++
+	return m.getUserProfileFunc(ctx, accountID, identifier)
+}
+
+func (m *mockUnipileClient) GetAccount(ctx context.Context, accountID string) (*service.Account, error) {
+	if m.getAccountFunc == nil {
+		return &service.Account{ID: accountID}, nil
+	}
+	return m.getAccountFunc(ctx, accountID)
+}
+
+func connectedAccount() *mockAccountRepo {
+	return &mockAccountRepo{account: &entity.Account{ID: 7, UserID: 1, AccountID: "acc-1", Provider: "LINKEDIN", CurrentStatus: entity.AccountStatusOK}}
+}
+
+func janeDoe(_ context.Context, accountID, identifier string) (*service.UserProfile, error) {
+	return &service.UserProfile{
+		ProviderID:       "ACoAA",
+		PublicIdentifier: "jane-doe",
+		FirstName:        "Jane",
+		LastName:         "Doe",
+		Headline:         "Engineer at Acme",
+		Location:         "Paris",
+		WorkExperience:   []service.WorkExperience{{Position: "Engineer", Company: "Acme"}},
+	}, nil
+}
+
+func TestGetProfile_FetchesAndCaches(t *testing.T) {
+	profileRepo := &mockProfileRepo{}
+	client := &mockUnipileClient{getUserProfileFunc: func(ctx context.Context, accountID, identifier string) (*service.UserProfile, error) {
+		if accountID != "acc-1" || identifier != "jane-doe" {
+			t.Fatalf("unexpected lookup %s %s", accountID, identifier)
+		}
+		return janeDoe(ctx, accountID, identifier)
+	}}
+	uc := NewProfileUsecase(connectedAccount(), profileRepo, client, time.Hour, logrus.New())
+
+	profile, err := uc.GetProfile(context.Background(), 1, "acc-1", " jane-doe ")
+	if err != nil {
+		t.Fatalf("GetProfile returned error: %v", err)
+	}
+	if profile.AccountID != 7 || profile.PublicIdentifier != "jane-doe" || profile.ProviderID != "ACoAA" || profile.Company != "Acme" ||
+		profile.Headline != "Engineer at Acme" || profile.Location != "Paris" || profile.Provider != "LINKEDIN" || profile.FetchedAt.IsZero() {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+	if profileRepo.upserts != 1 {
+		t.Fatalf("expected the profile to be cached, got %d upserts", profileRepo.upserts)
+	}
+
+	// Repeat lookups are served from the cache, by public identifier in any case or by provider ID
+	for _, identifier := range []string{"jane-doe", "Jane-Doe/", "ACoAA"} {
+		if _, err := uc.GetProfile(context.Background(), 1, "acc-1", identifier); err != nil {
+			t.Fatalf("GetProfile(%q) returned error: %v", identifier, err)
+		}
+	}
+	if client.calls != 1 || profileRepo.upserts != 1 {
+		t.Fatalf("expected one Unipile call and one cached profile, got %d calls and %d upserts", client.calls, profileRepo.upserts)
+	}
+}
+
+func TestGetProfile_RefreshesStaleProfile(t *testing.T) {
+	profileRepo := &mockProfileRepo{profiles: map[string]*entity.Profile{
+		"ACoAA": {AccountID: 7, ProviderID: "ACoAA", PublicIdentifier: "jane-doe", Headline: "Intern", FetchedAt: time.Now().Add(-2 * time.Hour)},
+	}}
+	client := &mockUnipileClient{getUserProfileFunc: janeDoe}
+	uc := NewProfileUsecase(connectedAccount(), profileRepo, client, time.Hour, logrus.New())
+
+	profile, err := uc.GetProfile(context.Background(), 1, "acc-1", "jane-doe")
+	if err != nil {
+		t.Fatalf("GetProfile returned error: %v", err)
+	}
+	if client.calls != 1 || profile.Headline != "Engineer at Acme" || profileRepo.upserts != 1 || len(profileRepo.profiles) != 1 {
+		t.Fatalf("expected the stale profile to be refreshed, got %+v", profile)
+	}
+}
+
+func TestGetProfile_Errors(t *testing.T) {
+	disconnected := connectedAccount()
+	disconnected.account.CurrentStatus = entity.AccountStatusCredentials
+
+	notFound := &service.UnipileError{Status: 404, Type: service.UnipileErrorTypeResourceNotFound}
+	userNotFound := fmt.Errorf("%w: %w", service.ErrUnipileUserNotFound, notFound)
+	accountNotFound := fmt.Errorf("%w: %w", service.ErrUnipileAccountNotFound, notFound)
+
+	tests := []struct {
+		name              string
+		accountRepo       *mockAccountRepo
+		accountID         string
+		identifier        string
+		clientErr         error
+		unipileAccountErr error
+		wantErr           error
+	}{
+		{name: "empty identifier", accountRepo: connectedAccount(), accountID: "acc-1", identifier: " "},
+		{name: "account of another user", accountRepo: connectedAccount(), accountID: "acc-2", identifier: "jane-doe", wantErr: errs.ErrAccountNotFound},
+		{name: "account not connected", accountRepo: disconnected, accountID: "acc-1", identifier: "jane-doe", wantErr: errs.ErrAccountNotConnected},
+		{name: "unknown user", accountRepo: connectedAccount(), accountID: "acc-1", identifier: "nobody", clientErr: userNotFound, wantErr: errs.ErrProfileNotFound},
+		{name: "unknown Unipile account", accountRepo: connectedAccount(), accountID: "acc-1", identifier: "jane-doe", clientErr: userNotFound, unipileAccountErr: accountNotFound, wantErr: errs.ErrAccountNotFound},
+		{name: "rate limited", accountRepo: connectedAccount(), accountID: "acc-1", identifier: "jane-doe", clientErr: &service.UnipileError{Status: 429}, wantErr: errs.ErrProviderRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockUnipileClient{getUserProfileFunc: func(ctx context.Context, accountID, identifier string) (*service.UserProfile, error) {
+				if tt.clientErr == nil {
+					t.Fatal("expected Unipile not to be called")
+				}
+				return nil, tt.clientErr
+			}, getAccountFunc: func(ctx context.Context, accountID string) (*service.Account, error) {
+				if tt.unipileAccountErr != nil {
+					return nil, tt.unipileAccountErr
+				}
+				return &service.Account{ID: accountID}, nil
+			}}
+			uc := NewProfileUsecase(tt.accountRepo, &mockProfileRepo{}, client, time.Hour, logrus.New())
+
+			_, err := uc.GetProfile(context.Background(), 1, tt.accountID, tt.identifier)
+			if tt.wantErr == nil {
+				var codedErr *errs.CodedError
+				if !errors.As(err, &codedErr) || codedErr.Kind != errs.ValidationErrorKind {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
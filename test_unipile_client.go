@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -27,7 +28,11 @@ func main() {
 	}
 
 	// Initialize Unipile client
-	unipileClient := client.NewUnipileClient(cfg.Unipile.BaseURL, cfg.Unipile.APIKey)
+	unipileClient := client.NewUnipileClient(cfg.Unipile.BaseURL, cfg.Unipile.APIKey, client.RetryPolicy{
+		MaxRetries: cfg.Unipile.MaxRetries,
+		BaseDelay:  cfg.Unipile.RetryBaseDelay,
+		MaxDelay:   cfg.Unipile.RetryMaxDelay,
+	}, nil)
 
 	fmt.Printf("🔗 Testing connection to: %s\n", cfg.Unipile.BaseURL)
 	fmt.Printf("🔑 Using API key: %s...\n", cfg.Unipile.APIKey[:8])
@@ -46,7 +51,7 @@ func main() {
 // TestConnection tests the connection to Unipile API by calling ListAccounts
 func TestConnection(unipileClient service.UnipileClient) {
 	fmt.Println("\nTesting connection...")
-	if err := unipileClient.TestConnection(); err != nil {
+	if err := unipileClient.TestConnection(context.Background()); err != nil {
 		fmt.Printf("❌ Connection test failed: %v\n", err)
 		fmt.Println("   This might be normal if you don't have any accounts yet")
 	} else {
@@ -57,7 +62,7 @@ func TestConnection(unipileClient service.UnipileClient) {
 // TestListAccounts tests the list accounts functionality
 func TestListAccounts(unipileClient service.UnipileClient) {
 	fmt.Println("\nTesting list accounts...")
-	accountsResp, err := unipileClient.ListAccounts()
+	accountsResp, err := unipileClient.ListAccounts(context.Background(), nil)
 	if err != nil {
 		fmt.Printf("❌ List accounts failed: %v\n", err)
 		fmt.Println("   This might be normal if you don't have any accounts yet")
@@ -96,7 +101,7 @@ func TestDeleteAccount(unipileClient service.UnipileClient) {
 	testAccountID := "non_existent_account_id_12345"
 	fmt.Printf("   Testing deletion of account ID: %s\n", testAccountID)
 
-	err := unipileClient.DeleteAccount(testAccountID)
+	err := unipileClient.DeleteAccount(context.Background(), testAccountID)
 	if err != nil {
 		fmt.Printf("❌ Delete account failed (expected): %v\n", err)
 		fmt.Println("   This is expected behavior for a non-existent account")
@@ -113,7 +118,7 @@ func TestLinkedInConnectionWithCredentials(unipileClient service.UnipileClient,
 	fmt.Println("   You can modify the credentials below for testing")
 
 	// Example credentials - replace with real ones for testing
-	testCredentials := &service.ConnectLinkedInRequest{
+	testCredentials := &service.ConnectAccountRequest{
 		Provider: "LINKEDIN",
 		Username: username, // Replace with real email
 		Password: password, // Replace with real password
@@ -121,7 +126,7 @@ func TestLinkedInConnectionWithCredentials(unipileClient service.UnipileClient,
 
 	fmt.Printf("   Testing with username: %s\n", testCredentials.Username)
 
-	resp, err := unipileClient.ConnectLinkedIn(testCredentials)
+	resp, err := unipileClient.ConnectAccount(context.Background(), testCredentials)
 	if err != nil {
 		fmt.Printf("❌ LinkedIn connection failed: %v\n", err)
 		fmt.Println("   This is expected if credentials are invalid or if there are checkpoints")
@@ -147,7 +152,7 @@ func TestLinkedInConnectionWithCookie(unipileClient service.UnipileClient, acces
 	fmt.Println("   You can modify the cookie below for testing")
 
 	// Example cookie - replace with real one for testing
-	testCookie := &service.ConnectLinkedInRequest{
+	testCookie := &service.ConnectAccountRequest{
 		Provider:    "LINKEDIN",
 		AccessToken: accessToken, // Replace with real li_at cookie
 		UserAgent:   userAgent,
@@ -155,7 +160,7 @@ func TestLinkedInConnectionWithCookie(unipileClient service.UnipileClient, acces
 
 	fmt.Printf("   Testing with access token: %s...\n", testCookie.AccessToken[:8])
 
-	resp, err := unipileClient.ConnectLinkedIn(testCookie)
+	resp, err := unipileClient.ConnectAccount(context.Background(), testCookie)
 	if err != nil {
 		fmt.Printf("❌ LinkedIn cookie connection failed: %v\n", err)
 		fmt.Println("   This is expected if the cookie is invalid or expired")
@@ -183,7 +188,7 @@ func TestCheckpointSolving(unipileClient service.UnipileClient, accountID, code
 		Code:      code, // Example 2FA code
 	}
 
-	checkpointResp, err := unipileClient.SolveCheckpoint(checkpointReq)
+	checkpointResp, err := unipileClient.SolveCheckpoint(context.Background(), checkpointReq)
 	if err != nil {
 		fmt.Printf("❌ Checkpoint solving failed: %v\n", err)
 		fmt.Println("   This is expected if the code is invalid or the checkpoint expired")
@@ -197,7 +202,7 @@ func TestCheckpointSolving(unipileClient service.UnipileClient, accountID, code
 // TestAccountStatusCheck tests the account status check functionality
 func TestAccountStatusCheck(unipileClient service.UnipileClient, accountID string) {
 	fmt.Println("\nTesting account status check...")
-	account, err := unipileClient.GetAccount(accountID)
+	account, err := unipileClient.GetAccount(context.Background(), accountID)
 	if err != nil {
 		fmt.Printf("❌ Account status check failed: %v\n", err)
 	} else {
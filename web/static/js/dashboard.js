@@ -2,6 +2,10 @@
 let currentUser = null;
 let currentAccountID = null;
 let authToken = null;
+let accountEventsController = null;
+let expirationTimerInterval = null;
+let currentProvider = 'linkedin';
+let qrCodeRefreshInterval = null;
 
 // Check authentication and load dashboard
 document.addEventListener('DOMContentLoaded', function () {
@@ -26,6 +30,9 @@ document.addEventListener('DOMContentLoaded', function () {
 
     // Setup connection type toggle
     setupConnectionTypeToggle();
+
+    // Listen for real-time account status updates
+    startAccountEventStream();
 });
 
 // Handle navbar brand click - stay on dashboard when logged in
@@ -60,6 +67,7 @@ async function logout() {
         console.warn('Logout request failed:', error);
     } finally {
         // Clear local storage regardless of server response
+        stopAccountEventStream();
         authToken = null;
         localStorage.removeItem('authToken');
         localStorage.removeItem('userId');
@@ -76,6 +84,101 @@ async function logout() {
     }
 }
 
+// Stream account status events from the server.
+// EventSource cannot send the Authorization header, so the stream is read with fetch.
+async function startAccountEventStream(retryDelay = 1000) {
+    if (!authToken) {
+        return;
+    }
+
+    accountEventsController = new AbortController();
+
+    try {
+        const response = await fetch('/api/v1/accounts/events', {
+            headers: getAuthHeaders(),
+            signal: accountEventsController.signal
+        });
+
+        if (response.status === 401) {
+            // Token expired or revoked, the server also closes open streams then,
+            // the next API call will send the user to login
+            return;
+        }
+        if (!response.ok || !response.body) {
+            throw new Error(`Event stream failed with status ${response.status}`);
+        }
+
+        // Connected, reset the reconnect backoff
+        retryDelay = 1000;
+
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffer = '';
+
+        while (true) {
+            const { value, done } = await reader.read();
+            if (done) {
+                break;
+            }
+
+            buffer += decoder.decode(value, { stream: true });
+            const messages = buffer.split('\n\n');
+            buffer = messages.pop();
+            messages.forEach(handleAccountEventMessage);
+        }
+    } catch (error) {
+        if (error.name === 'AbortError') {
+            return;
+        }
+        console.warn('Account event stream error:', error);
+    }
+
+    // Reconnect after server restarts or network errors
+    setTimeout(() => startAccountEventStream(Math.min(retryDelay * 2, 30000)), retryDelay);
+}
+
+// Stop the account event stream
+function stopAccountEventStream() {
+    if (accountEventsController) {
+        accountEventsController.abort();
+        accountEventsController = null;
+    }
+}
+
+// Handle a single Server-Sent Events message
+function handleAccountEventMessage(message) {
+    let eventType = 'message';
+    let data = '';
+
+    message.split('\n').forEach(line => {
+        if (line.startsWith('event:')) {
+            eventType = line.slice(6).trim();
+        } else if (line.startsWith('data:')) {
+            data += line.slice(5).trim();
+        }
+    });
+
+    // Comments (heartbeats) have no data
+    if (eventType !== 'account.status' || !data) {
+        return;
+    }
+
+    try {
+        const event = JSON.parse(data);
+
+        if (event.account_id === currentAccountID && event.status === 'OK') {
+            showAlert('LinkedIn account validated successfully!', 'success');
+            hideCheckpointSection();
+        } else if (event.status !== 'OK' && event.status !== 'PENDING' && event.status !== 'DELETED') {
+            showAlert(`Account ${event.account_id} status changed to ${event.status}`, 'warning');
+        }
+
+        loadUserAccounts();
+    } catch (error) {
+        console.warn('Invalid account event:', error);
+    }
+}
+
 // Get auth headers for API requests
 function getAuthHeaders() {
     const headers = {
@@ -214,6 +317,7 @@ function getStatusBadge(status) {
         'ERROR': '<span class="badge bg-danger">Error</span>',
         'STOPPED': '<span class="badge bg-secondary">Stopped</span>',
         'CREDENTIALS': '<span class="badge bg-warning">Credentials</span>',
+        'EXPIRED': '<span class="badge bg-secondary">Checkpoint Expired</span>',
         'SYNC_SUCCESS': '<span class="badge bg-success">Sync Success</span>',
         'RECONNECTED': '<span class="badge bg-success">Reconnected</span>'
     };
@@ -223,8 +327,9 @@ function getStatusBadge(status) {
 // Get actions for connecting accounts
 function getConnectingAccountActions(account) {
     if (account.current_status === 'PENDING' && account.account_status_histories && account.account_status_histories.length > 0) {
-        const latestHistory = account.account_status_histories[account.account_status_histories.length - 1];
-        if (latestHistory.checkpoint) {
+        // Audit rows such as a checkpoint lock carry no checkpoint, use the latest row that does
+        const latestHistory = account.account_status_histories.filter(history => history.checkpoint).pop();
+        if (latestHistory) {
             if (latestHistory.checkpoint === 'IN_APP_VALIDATION') {
                 return `
                     <div class="mt-2">
@@ -239,7 +344,7 @@ function getConnectingAccountActions(account) {
             } else {
                 return `
                     <div class="mt-2">
-                        <button type="button" class="btn btn-primary btn-sm" onclick="resumeCheckpoint('${account.account_id}', '${latestHistory.checkpoint}')">
+                        <button type="button" class="btn btn-primary btn-sm" onclick="resumeCheckpoint('${account.account_id}', '${latestHistory.checkpoint}', '${account.provider.toLowerCase()}')">
                             <i class="fas fa-play"></i> Resume Checkpoint
                         </button>
                         <button type="button" class="btn btn-outline-danger btn-sm ms-2" onclick="cancelConnection('${account.account_id}')">
@@ -251,6 +356,20 @@ function getConnectingAccountActions(account) {
         }
     }
 
+    if (['CREDENTIALS', 'ERROR', 'STOPPED'].includes(account.current_status)) {
+        return `
+            <div class="mt-2">
+                <button type="button" class="btn btn-primary btn-sm" onclick="connectLinkedIn('${account.account_id}')">
+                    <i class="fas fa-sync"></i> Reconnect
+                </button>
+                <button type="button" class="btn btn-outline-danger btn-sm ms-2" onclick="cancelConnection('${account.account_id}')">
+                    <i class="fas fa-times"></i> Disconnect
+                </button>
+                <div><small class="text-muted">Reconnect uses the credentials or cookie entered in the connection form.</small></div>
+            </div>
+        `;
+    }
+
     return `
         <div class="mt-2">
             <button type="button" class="btn btn-outline-danger btn-sm" onclick="cancelConnection('${account.account_id}')">
@@ -260,10 +379,13 @@ function getConnectingAccountActions(account) {
     `;
 }
 
-// Connect LinkedIn account
-async function connectLinkedIn() {
+// Connect LinkedIn account, or reconnect an existing one when reconnectAccountID is given
+async function connectLinkedIn(reconnectAccountID = null) {
     const connectionType = document.querySelector('input[name="connectionType"]:checked').value;
     let requestData = { type: connectionType };
+    if (reconnectAccountID) {
+        requestData.account_id = reconnectAccountID;
+    }
 
     if (connectionType === 'credentials') {
         const username = document.getElementById('linkedinUsername').value;
@@ -301,7 +423,8 @@ async function connectLinkedIn() {
     }
 
     try {
-        const response = await fetch('/api/v1/accounts/linkedin/connect', {
+        const url = reconnectAccountID ? '/api/v1/accounts/linkedin/reconnect' : '/api/v1/accounts/linkedin/connect';
+        const response = await fetch(url, {
             method: 'POST',
             headers: getAuthHeaders(),
             body: JSON.stringify(requestData)
@@ -313,6 +436,7 @@ async function connectLinkedIn() {
             // Check if account status is not "OK" - meaning checkpoint is required
             if (data.account.current_status && data.account.current_status !== "OK") {
                 // Checkpoint required
+                currentProvider = 'linkedin';
                 currentAccountID = data.account.account_id;
 
                 // Check if there's checkpoint information in account status histories
@@ -331,7 +455,7 @@ async function connectLinkedIn() {
                 }
             } else {
                 // Account connected successfully
-                showAlert('LinkedIn account connected successfully!', 'success');
+                showAlert(reconnectAccountID ? 'LinkedIn account reconnected successfully!' : 'LinkedIn account connected successfully!', 'success');
                 // Clear form
                 document.getElementById('linkedinUsername').value = '';
                 document.getElementById('linkedinPassword').value = '';
@@ -341,7 +465,7 @@ async function connectLinkedIn() {
                 loadUserAccounts();
             }
         } else {
-            showAlert(data.detail || 'Failed to connect LinkedIn account', 'danger');
+            showAlert(data.detail || (reconnectAccountID ? 'Failed to reconnect LinkedIn account' : 'Failed to connect LinkedIn account'), 'danger');
         }
     } catch (error) {
         showAlert('Network error. Please try again.', 'danger');
@@ -399,6 +523,16 @@ function showCheckpointSection(checkpoint, expiresAt) {
             checkpointCodeInput.maxLength = 20;
             checkpointCodeInput.type = 'text';
             break;
+        case 'QRCODE':
+            checkpointAlert.innerHTML = `
+                <strong>Scan the QR Code</strong><br>
+                Open WhatsApp on your phone, go to Linked Devices and scan this code. It refreshes automatically.
+                <div class="text-center mt-2"><img id="qrCodeImage" alt="QR code" width="256" height="256"></div>
+            `;
+            checkpointLabel.textContent = 'Waiting for scan...';
+            checkpointCodeInput.placeholder = 'Waiting for QR code scan...';
+            checkpointCodeInput.disabled = true;
+            break;
         case 'PHONE_REGISTER':
             checkpointAlert.innerHTML = `
                 <strong>Phone Verification Required</strong><br>
@@ -434,8 +568,8 @@ function showCheckpointSection(checkpoint, expiresAt) {
     checkpointSection.style.display = 'block';
     checkpointCodeInput.value = ''; // Clear previous input
 
-    // Handle IN_APP_VALIDATION with automatic long polling
-    if (checkpoint.type === 'IN_APP_VALIDATION') {
+    // Handle IN_APP_VALIDATION and QRCODE with automatic long polling
+    if (checkpoint.type === 'IN_APP_VALIDATION' || checkpoint.type === 'QRCODE') {
         // Disable the submit button and show waiting state
         const submitBtn = document.querySelector('button[onclick="solveCheckpoint()"]');
         if (submitBtn) {
@@ -443,6 +577,10 @@ function showCheckpointSection(checkpoint, expiresAt) {
             submitBtn.disabled = true;
         }
 
+        if (checkpoint.type === 'QRCODE') {
+            startQRCodeRefresh();
+        }
+
         // Start long polling for IN_APP_VALIDATION
         startInAppValidationPolling(expiresAt);
     } else {
@@ -465,6 +603,8 @@ function showCheckpointSection(checkpoint, expiresAt) {
 
 // Start expiration timer
 function startExpirationTimer(timeLeft) {
+    stopExpirationTimer();
+
     const timerElement = document.createElement('div');
     timerElement.id = 'expirationTimer';
     timerElement.className = 'alert alert-warning mt-2';
@@ -472,7 +612,7 @@ function startExpirationTimer(timeLeft) {
     const checkpointAlert = document.getElementById('checkpointAlert');
     checkpointAlert.appendChild(timerElement);
 
-    const timer = setInterval(() => {
+    expirationTimerInterval = setInterval(() => {
         const minutes = Math.floor(timeLeft / 60000);
         const seconds = Math.floor((timeLeft % 60000) / 1000);
 
@@ -483,8 +623,8 @@ function startExpirationTimer(timeLeft) {
         timeLeft -= 1000;
 
         if (timeLeft <= 0) {
-            clearInterval(timer);
-            timerElement.innerHTML = '<strong>Checkpoint expired!</strong> Please start over.';
+            stopExpirationTimer();
+            timerElement.innerHTML = '<strong>Checkpoint expired!</strong> Request a new code or start over.';
             timerElement.className = 'alert alert-danger mt-2';
             document.getElementById('checkpointCode').disabled = true;
             document.querySelector('button[onclick="solveCheckpoint()"]').disabled = true;
@@ -492,6 +632,14 @@ function startExpirationTimer(timeLeft) {
     }, 1000);
 }
 
+// Stop the expiration timer and remove it from the checkpoint section
+function stopExpirationTimer() {
+    if (expirationTimerInterval) {
+        clearInterval(expirationTimerInterval);
+        expirationTimerInterval = null;
+    }
+}
+
 // Start long polling for IN_APP_VALIDATION checkpoint
 async function startInAppValidationPolling(expiresAt) {
     if (!currentAccountID) {
@@ -513,7 +661,7 @@ async function startInAppValidationPolling(expiresAt) {
     console.log(`Starting IN_APP_VALIDATION polling with timeout: ${timeoutSeconds} seconds`);
 
     try {
-        const response = await fetch('/api/v1/accounts/linkedin/wait-validation', {
+        const response = await fetch(`/api/v1/accounts/${currentProvider}/wait-validation`, {
             method: 'POST',
             headers: getAuthHeaders(),
             body: JSON.stringify({
@@ -526,7 +674,7 @@ async function startInAppValidationPolling(expiresAt) {
 
         if (response.ok) {
             // Validation successful
-            showAlert('LinkedIn account validated successfully!', 'success');
+            showAlert(data.message || 'Account validated successfully!', 'success');
             hideCheckpointSection();
             loadUserAccounts();
         } else {
@@ -564,6 +712,90 @@ async function startInAppValidationPolling(expiresAt) {
     }
 }
 
+// Connect a WhatsApp account by scanning a QR code
+async function connectWhatsApp() {
+    const connectBtn = document.querySelector('button[onclick="connectWhatsApp()"]');
+    let originalText = '';
+    if (connectBtn) {
+        originalText = connectBtn.innerHTML;
+        connectBtn.innerHTML = '<i class="fas fa-spinner fa-spin"></i> Connecting...';
+        connectBtn.disabled = true;
+    }
+
+    try {
+        const response = await fetch('/api/v1/accounts/whatsapp/connect', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({})
+        });
+
+        const data = await response.json();
+
+        if (response.ok) {
+            currentProvider = 'whatsapp';
+            currentAccountID = data.account.account_id;
+            const histories = data.account.account_status_histories || [];
+            const latestHistory = histories[histories.length - 1];
+            showCheckpointSection({
+                type: latestHistory ? latestHistory.checkpoint : 'QRCODE'
+            }, latestHistory ? latestHistory.checkpoint_expires_at : null);
+            loadUserAccounts();
+        } else {
+            showAlert(data.detail || 'Failed to connect WhatsApp account', 'danger');
+        }
+    } catch (error) {
+        showAlert('Network error. Please try again.', 'danger');
+    } finally {
+        if (connectBtn && originalText) {
+            connectBtn.innerHTML = originalText;
+            connectBtn.disabled = false;
+        }
+    }
+}
+
+// Show the current QR code and keep it refreshed while it is not scanned
+function startQRCodeRefresh() {
+    stopQRCodeRefresh();
+    loadQRCode();
+    qrCodeRefreshInterval = setInterval(loadQRCode, 5000);
+}
+
+// Stop refreshing the QR code
+function stopQRCodeRefresh() {
+    if (qrCodeRefreshInterval) {
+        clearInterval(qrCodeRefreshInterval);
+        qrCodeRefreshInterval = null;
+    }
+}
+
+// Load the current QR code of the pending account
+async function loadQRCode() {
+    if (!currentAccountID) {
+        stopQRCodeRefresh();
+        return;
+    }
+
+    try {
+        const params = new URLSearchParams({ account_id: currentAccountID, format: 'data_uri' });
+        const response = await fetch(`/api/v1/accounts/${currentProvider}/qrcode?${params}`, {
+            headers: getAuthHeaders()
+        });
+        const data = await response.json();
+
+        if (response.ok) {
+            const image = document.getElementById('qrCodeImage');
+            if (image && image.src !== data.qrcode) {
+                image.src = data.qrcode;
+            }
+        } else if (response.status === 401 || response.status === 409) {
+            // The checkpoint expired or the account is no longer waiting for a scan
+            stopQRCodeRefresh();
+        }
+    } catch (error) {
+        console.error('QR code refresh error:', error);
+    }
+}
+
 // Validate checkpoint input based on type
 function validateCheckpointInput(code, checkpointType) {
     if (!code) {
@@ -619,8 +851,10 @@ async function solveCheckpoint() {
 
     // Show loading state
     const submitBtn = document.querySelector('button[onclick="solveCheckpoint()"]');
+    let originalText = '';
+    let followUpCheckpoint = false;
     if (submitBtn) {
-        const originalText = submitBtn.innerHTML;
+        originalText = submitBtn.innerHTML;
         submitBtn.innerHTML = '<i class="fas fa-spinner fa-spin"></i> Submitting...';
         submitBtn.disabled = true;
     }
@@ -631,7 +865,7 @@ async function solveCheckpoint() {
             code: code
         };
 
-        const response = await fetch('/api/v1/accounts/linkedin/checkpoint', {
+        const response = await fetch(`/api/v1/accounts/${currentProvider}/checkpoint`, {
             method: 'POST',
             headers: getAuthHeaders(),
             body: JSON.stringify(requestBody)
@@ -639,27 +873,88 @@ async function solveCheckpoint() {
 
         const data = await response.json();
 
-        if (response.ok) {
+        if (response.ok && data.account.current_status && data.account.current_status !== 'OK') {
+            // Unipile asked for another checkpoint, e.g. app validation after 2FA
+            followUpCheckpoint = true;
+            const histories = data.account.account_status_histories || [];
+            const latestHistory = histories[histories.length - 1];
+            hideCheckpointSection();
+            showCheckpointSection({
+                type: latestHistory ? latestHistory.checkpoint : 'UNKNOWN'
+            }, latestHistory ? latestHistory.checkpoint_expires_at : null);
+            showAlert('Additional verification required: ' + (latestHistory ? latestHistory.checkpoint : 'UNKNOWN'), 'info');
+            loadUserAccounts();
+        } else if (response.ok) {
             showAlert('LinkedIn account connected successfully!', 'success');
             hideCheckpointSection();
             loadUserAccounts();
         } else if (response.status === 401) {
             showAlert('Invalid code or checkpoint expired. Please try again.', 'danger');
             document.getElementById('checkpointCode').value = '';
+        } else if (response.status === 423) {
+            showAlert(data.message || 'Too many invalid codes, request a new code to continue', 'danger');
+            document.getElementById('checkpointCode').value = '';
         } else {
             showAlert(data.detail || 'Failed to solve checkpoint', 'danger');
         }
     } catch (error) {
         showAlert('Network error. Please try again.', 'danger');
     } finally {
-        // Restore button state
-        if (submitBtn) {
+        // Restore button state, the follow-up checkpoint section sets its own
+        if (submitBtn && originalText && !followUpCheckpoint) {
             submitBtn.innerHTML = originalText;
             submitBtn.disabled = false;
         }
     }
 }
 
+// Request a new code for the current checkpoint
+async function resendCheckpointCode() {
+    if (!currentAccountID) {
+        showAlert('No account ID available. Please try connecting again.', 'danger');
+        return;
+    }
+
+    const resendBtn = document.querySelector('button[onclick="resendCheckpointCode()"]');
+    let originalText = '';
+    if (resendBtn) {
+        originalText = resendBtn.innerHTML;
+        resendBtn.innerHTML = '<i class="fas fa-spinner fa-spin"></i> Sending...';
+        resendBtn.disabled = true;
+    }
+
+    try {
+        const response = await fetch(`/api/v1/accounts/${currentProvider}/checkpoint/resend`, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ account_id: currentAccountID })
+        });
+
+        const data = await response.json();
+
+        if (response.ok) {
+            const histories = data.account.account_status_histories || [];
+            const latestHistory = histories[histories.length - 1];
+            hideCheckpointSection();
+            showCheckpointSection({
+                type: latestHistory ? latestHistory.checkpoint : 'UNKNOWN'
+            }, latestHistory ? latestHistory.checkpoint_expires_at : null);
+            showAlert('A new verification code has been sent', 'info');
+        } else if (response.status === 401) {
+            showAlert('Checkpoint expired. Please start the connection again.', 'danger');
+        } else {
+            showAlert(data.detail || 'Failed to request a new code', 'danger');
+        }
+    } catch (error) {
+        showAlert('Network error. Please try again.', 'danger');
+    } finally {
+        if (resendBtn && originalText) {
+            resendBtn.innerHTML = originalText;
+            resendBtn.disabled = false;
+        }
+    }
+}
+
 // Test function to manually show checkpoint section
 function testCheckpoint() {
     console.log('Testing checkpoint section...');
@@ -711,7 +1006,9 @@ function hideCheckpointSection() {
     const checkpointCode = document.getElementById('checkpointCode');
     const submitBtn = document.querySelector('button[onclick="solveCheckpoint()"]');
 
-    // Clean up timer if it exists
+    // Clean up timers if they exist
+    stopExpirationTimer();
+    stopQRCodeRefresh();
     const timerElement = document.getElementById('expirationTimer');
     if (timerElement) {
         timerElement.remove();
@@ -787,7 +1084,8 @@ async function disconnectLinkedIn(accountId) {
 }
 
 // Resume checkpoint for a connecting account
-async function resumeCheckpoint(accountId, checkpointType) {
+async function resumeCheckpoint(accountId, checkpointType, provider = 'linkedin') {
+    currentProvider = provider;
     currentAccountID = accountId;
 
     // Show checkpoint section with the stored checkpoint type
@@ -800,6 +1098,7 @@ async function resumeCheckpoint(accountId, checkpointType) {
 
 // Resume IN_APP_VALIDATION for a connecting account
 async function resumeInAppValidation(accountId, expiresAt) {
+    currentProvider = 'linkedin';
     currentAccountID = accountId;
 
     // Show checkpoint section with IN_APP_VALIDATION type